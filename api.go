@@ -0,0 +1,156 @@
+package paypalsdk
+
+import (
+	"net/http"
+	"time"
+)
+
+/*
+按业务划分的接口, 由 *Client 实现。
+业务代码依赖这些接口而不是 *Client, 测试时可以替换为 paypalmock 中的实现, 或 paypaltest.Server 创建的 Client。
+*/
+
+type SubscriptionsAPI interface {
+	CreateSubscription(q *CreateSubscriptionReq) (*Subscription, error)
+	UpdateSubscription(subId string, op E_PatchOp) (*Subscription, error)
+	ShowSubscriptionDetails(subID string) (*Subscription, error)
+	ActivateSubscription(subId, reason string) error
+	SuspendSubscription(subId, reason string) error
+	CancelSubscription(subID, reason string) error
+	ListTransactionsForSubscription(subID, startTime, endTime string) (*ListTransactionRsp, error)
+}
+
+type PlansAPI interface {
+	CreatePlan(q *Plan) (*Plan, error)
+	ListPlans(productID string, page, pageSize int) (*PlanList, error)
+	ShowPlanDetails(planID string) (*Plan, error)
+	ActivatePlan(planID string) error
+	DeactivatePlan(planID string) error
+}
+
+type ProductsAPI interface {
+	CreateProduct(q *Product) (*Product, error)
+	ListProducts(page, pageSize int) (*ProductList, error)
+	ShowProductDetails(productID string) (*Product, error)
+}
+
+type WebhooksAPI interface {
+	CreateWebhook(q *CreateWebhookReq) (*Webhook, error)
+	ListWebhooks(anchor_type string) (*WebhookList, error)
+	DeleteWebhook(id string) error
+	UpdateWebhook(id string, patches []Patch) (*Webhook, error)
+	VerifyWebhookSignature(header http.Header, body []byte, webhookID string) (bool, error)
+	ListWebhookEvents(q *ListWebhookEventsReq) (*EventList, error)
+	ShowWebhookEvent(eventID string) (*Event, error)
+	ResendWebhookEvent(eventID string, webhookIDs []string) (*Event, error)
+}
+
+type SalesAPI interface {
+	ShowSale(saleID string) (*Sale, error)
+	RefundSale(saleID string, q *RefundSaleReq) (*DetailedRefund, error)
+}
+
+type PaymentsAPI interface {
+	ShowAuthorization(authID string) (*Authorization, error)
+	CaptureAuthorization(authID string, q *CaptureAuthorizationReq, requestID string) (*Capture, error)
+	ReauthorizeAuthorization(authID string, q *ReauthorizeReq, requestID string) (*Authorization, error)
+	VoidAuthorization(authID string) (*Authorization, error)
+	ShowCapture(captureID string) (*Capture, error)
+	RefundCapture(captureID string, q *RefundCaptureReq, requestID string) (*Refund, error)
+	ShowRefund(refundID string) (*Refund, error)
+}
+
+type DisputesAPI interface {
+	ListDisputes(q *ListDisputesReq) (*DisputeList, error)
+	ShowDisputeDetails(disputeID string) (*Dispute, error)
+	AcceptDisputeClaim(disputeID string, q *AcceptDisputeClaimReq) (*DisputeSubsequentAction, error)
+	MakeDisputeOffer(disputeID string, q *MakeDisputeOfferReq) (*DisputeSubsequentAction, error)
+	ProvideDisputeEvidence(disputeID string, evidences []*Evidence, files []*EvidenceFile) (*DisputeSubsequentAction, error)
+	AppealDispute(disputeID string, evidences []*Evidence, files []*EvidenceFile) (*DisputeSubsequentAction, error)
+	SendDisputeMessage(disputeID, message string) (*DisputeSubsequentAction, error)
+	EscalateDispute(disputeID, note string) (*DisputeSubsequentAction, error)
+}
+
+type InvoicesAPI interface {
+	GenerateNextInvoiceNumber() (string, error)
+	CreateDraftInvoice(q *Invoice) (*Invoice, error)
+	ShowInvoiceDetails(invoiceID string) (*Invoice, error)
+	ListInvoices(page, pageSize int) (*InvoiceList, error)
+	SearchInvoices(q *SearchInvoiceReq, page, pageSize int) (*InvoiceList, error)
+	SendInvoice(invoiceID string, q *InvoiceNotification) (*LinkDescription, error)
+	RemindInvoice(invoiceID string, q *InvoiceNotification) error
+	CancelInvoice(invoiceID string, q *InvoiceNotification) error
+	RecordInvoicePayment(invoiceID string, q *InvoicePaymentDetail) (string, error)
+	DeleteInvoicePayment(invoiceID, paymentID string) error
+	RecordInvoiceRefund(invoiceID string, q *InvoiceRefundDetail) (string, error)
+	DeleteInvoiceRefund(invoiceID, refundID string) error
+	GenerateInvoiceQRCode(invoiceID string, q *InvoiceQRCodeReq) (string, error)
+	ListInvoiceTemplates(page, pageSize int) (*InvoiceTemplateList, error)
+	CreateInvoiceTemplate(q *InvoiceTemplate) (*InvoiceTemplate, error)
+	ShowInvoiceTemplate(templateID string) (*InvoiceTemplate, error)
+	UpdateInvoiceTemplate(templateID string, q *InvoiceTemplate) (*InvoiceTemplate, error)
+	DeleteInvoiceTemplate(templateID string) error
+}
+
+type PayoutsAPI interface {
+	CreatePayout(q *CreatePayoutReq) (*PayoutBatch, error)
+	ShowPayoutBatch(batchID string, page, pageSize int) (*PayoutBatch, error)
+	ShowPayoutItem(itemID string) (*PayoutItemDetail, error)
+	CancelPayoutItem(itemID string) (*PayoutItemDetail, error)
+}
+
+type ReportingAPI interface {
+	ListTransactions(q *TransactionSearchReq, page int) (*TransactionSearchRsp, error)
+	SearchAllTransactions(q *TransactionSearchReq) ([]*TransactionDetail, error)
+	ListBalances(asOfTime time.Time, currencyCode string) (*BalancesRsp, error)
+}
+
+type TrackingAPI interface {
+	AddTrackers(trackers []*Tracker) (*AddTrackersRsp, error)
+	UpdateTracker(t *Tracker) error
+	ShowTracker(transactionID, trackingNumber string) (*Tracker, error)
+}
+
+type VaultAPI interface {
+	CreateSetupToken(q *CreateSetupTokenReq, requestID string) (*SetupToken, error)
+	ShowSetupToken(setupTokenID string) (*SetupToken, error)
+	CreatePaymentToken(q *CreatePaymentTokenReq, requestID string) (*PaymentToken, error)
+	CreatePaymentTokenFromSetupToken(setupTokenID string, customer *VaultCustomer, requestID string) (*PaymentToken, error)
+	ShowPaymentToken(paymentTokenID string) (*PaymentToken, error)
+	ListPaymentTokens(customerID string, page, pageSize int) (*PaymentTokenList, error)
+	DeletePaymentToken(paymentTokenID string) error
+}
+
+type PartnersAPI interface {
+	CreatePartnerReferral(q *PartnerReferralData) (*PartnerReferral, error)
+	ShowPartnerReferral(referralID string) (*PartnerReferral, error)
+	ShowSellerStatus(partnerID, merchantID string) (*SellerStatus, error)
+	FindSellerByTrackingID(partnerID, trackingID string) (*SellerStatus, error)
+}
+
+type IdentityAPI interface {
+	AuthorizeURL(redirectURI string, scopes []string, state, nonce string) string
+	GrantNewAccessTokenFromAuthCode(code, redirectURI string) (*IdentityTokenResponse, error)
+	GrantNewAccessTokenFromRefreshToken(refreshToken string) (*IdentityTokenResponse, error)
+	GetUserInfo(accessToken string) (*UserInfo, error)
+}
+
+// API 包含以上所有接口
+type API interface {
+	SubscriptionsAPI
+	PlansAPI
+	ProductsAPI
+	WebhooksAPI
+	SalesAPI
+	PaymentsAPI
+	DisputesAPI
+	InvoicesAPI
+	PayoutsAPI
+	ReportingAPI
+	TrackingAPI
+	VaultAPI
+	PartnersAPI
+	IdentityAPI
+}
+
+var _ API = (*Client)(nil)
@@ -0,0 +1,228 @@
+package paypalsdk
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+/*
+调用钩子：
+SendWithAuth、Send 的每次调用(包括重试和刷新 token)对应一个 Call, 调用前后通知 Client.Hooks,
+用于链路追踪、统计等, 见 otel 子包。Call 在 BeforeCall 时只有 Operation、Method、Route、Start,
+AfterCall 时其余字段已填好。
+*/
+
+// Call describes one API operation, eg: creating a subscription
+type Call struct {
+	Operation      string        // eg: paypal.subscriptions.create
+	Method         string        // eg: POST
+	Route          string        // 路径模板, eg: /v1/billing/subscriptions/{id}/cancel
+	StatusCode     int           // 0 表示没有收到响应
+	DebugID        string        // 响应头 Paypal-Debug-Id, 联系 PayPal 排查问题时需要
+	Retries        int           // 重试次数, 不含第一次请求
+	TokenRefreshed bool          // 调用前是否刷新了 access token
+	Err            error         // 调用返回的错误
+	Start          time.Time     // 开始时间
+	Duration       time.Duration // 耗时, 包括重试和刷新 token
+}
+
+// CallHook is notified before and after every API call.
+// The context returned by BeforeCall is attached to the request, so interceptors and the transport can see it
+type CallHook interface {
+	BeforeCall(ctx context.Context, call *Call) context.Context
+	AfterCall(ctx context.Context, call *Call)
+}
+
+// WithCallHooks appends hooks notified of every API call
+func WithCallHooks(hooks ...CallHook) ClientOption {
+	return func(c *Client) {
+		c.AddCallHooks(hooks...)
+	}
+}
+
+// AddCallHooks appends hooks notified of every API call
+func (c *Client) AddCallHooks(hooks ...CallHook) {
+	c.Hooks = append(c.Hooks[:len(c.Hooks):len(c.Hooks)], hooks...)
+}
+
+type callContextKey struct{}
+
+// CallFromContext returns the Call in progress, interceptors may use it to find the operation of a request
+func CallFromContext(ctx context.Context) *Call {
+	call, _ := ctx.Value(callContextKey{}).(*Call)
+	return call
+}
+
+// startCall returns the Call of req. A new Call is started unless req already belongs to one,
+// eg: Send called by SendWithAuth; only the one who started a Call should end it
+func (c *Client) startCall(req *http.Request) (*http.Request, *Call, bool) {
+	if call := CallFromContext(req.Context()); call != nil {
+		return req, call, false
+	}
+	operation, route := ResolveOperation(req.Method, req.URL.Path)
+	call := &Call{
+		Operation: operation,
+		Method:    req.Method,
+		Route:     route,
+		Start:     time.Now(),
+	}
+	ctx := context.WithValue(req.Context(), callContextKey{}, call)
+	for _, h := range c.Hooks {
+		ctx = h.BeforeCall(ctx, call)
+	}
+	return req.WithContext(ctx), call, true
+}
+
+func (c *Client) endCall(req *http.Request, call *Call, err error) {
+	call.Err = err
+	call.Duration = time.Since(call.Start)
+
+	m := c.metrics()
+	m.ObserveCall(call.Operation, StatusClass(call.StatusCode), call.Duration)
+	if call.Retries > 0 {
+		m.ObserveRetries(call.Operation, call.Retries)
+	}
+	for i := len(c.Hooks) - 1; i >= 0; i-- {
+		c.Hooks[i].AfterCall(req.Context(), call)
+	}
+}
+
+type operationRoute struct {
+	method    string
+	route     string
+	operation string
+}
+
+// 已知接口的路径模板, {id} 匹配任意一段路径
+var operationRoutes = []*operationRoute{
+	{"POST", kGetAccessTokenAPI, "paypal.oauth2.token"},
+
+	{"POST", K_SUBSCRIPTION_API, "paypal.subscriptions.create"},
+	{"GET", K_SUBSCRIPTION_API + "/{id}", "paypal.subscriptions.get"},
+	{"PATCH", K_SUBSCRIPTION_API + "/{id}", "paypal.subscriptions.update"},
+	{"POST", K_SUBSCRIPTION_API + "/{id}/activate", "paypal.subscriptions.activate"},
+	{"POST", K_SUBSCRIPTION_API + "/{id}/cancel", "paypal.subscriptions.cancel"},
+	{"POST", K_SUBSCRIPTION_API + "/{id}/suspend", "paypal.subscriptions.suspend"},
+	{"POST", K_SUBSCRIPTION_API + "/{id}/capture", "paypal.subscriptions.capture"},
+	{"POST", K_SUBSCRIPTION_API + "/{id}/revise", "paypal.subscriptions.revise"},
+	{"GET", K_SUBSCRIPTION_API + "/{id}/transactions", "paypal.subscriptions.transactions"},
+
+	{"POST", "/v1/billing/plans", "paypal.plans.create"},
+	{"GET", "/v1/billing/plans", "paypal.plans.list"},
+	{"GET", "/v1/billing/plans/{id}", "paypal.plans.get"},
+	{"PATCH", "/v1/billing/plans/{id}", "paypal.plans.update"},
+	{"POST", "/v1/billing/plans/{id}/activate", "paypal.plans.activate"},
+	{"POST", "/v1/billing/plans/{id}/deactivate", "paypal.plans.deactivate"},
+	{"POST", "/v1/billing/plans/{id}/update-pricing-schemes", "paypal.plans.update_pricing"},
+
+	{"POST", "/v1/catalogs/products", "paypal.products.create"},
+	{"GET", "/v1/catalogs/products", "paypal.products.list"},
+	{"GET", "/v1/catalogs/products/{id}", "paypal.products.get"},
+	{"PATCH", "/v1/catalogs/products/{id}", "paypal.products.update"},
+
+	{"POST", "/v1/notifications/webhooks", "paypal.webhooks.create"},
+	{"GET", "/v1/notifications/webhooks", "paypal.webhooks.list"},
+	{"GET", "/v1/notifications/webhooks/{id}", "paypal.webhooks.get"},
+	{"PATCH", "/v1/notifications/webhooks/{id}", "paypal.webhooks.update"},
+	{"DELETE", "/v1/notifications/webhooks/{id}", "paypal.webhooks.delete"},
+	{"POST", "/v1/notifications/verify-webhook-signature", "paypal.webhooks.verify_signature"},
+	{"GET", "/v1/notifications/webhooks-events", "paypal.webhook_events.list"},
+	{"GET", "/v1/notifications/webhooks-events/{id}", "paypal.webhook_events.get"},
+	{"POST", "/v1/notifications/webhooks-events/{id}/resend", "paypal.webhook_events.resend"},
+
+	{"GET", K_PAYMENT_AUTHORIZATION_API + "/{id}", "paypal.authorizations.get"},
+	{"POST", K_PAYMENT_AUTHORIZATION_API + "/{id}/capture", "paypal.authorizations.capture"},
+	{"POST", K_PAYMENT_AUTHORIZATION_API + "/{id}/reauthorize", "paypal.authorizations.reauthorize"},
+	{"POST", K_PAYMENT_AUTHORIZATION_API + "/{id}/void", "paypal.authorizations.void"},
+	{"GET", K_PAYMENT_CAPTURE_API + "/{id}", "paypal.captures.get"},
+	{"POST", K_PAYMENT_CAPTURE_API + "/{id}/refund", "paypal.captures.refund"},
+	{"GET", K_PAYMENT_REFUND_API + "/{id}", "paypal.refunds.get"},
+
+	{"GET", K_SALE_API + "/{id}", "paypal.sales.get"},
+	{"POST", K_SALE_API + "/{id}/refund", "paypal.sales.refund"},
+
+	{"POST", K_PAYOUT_API, "paypal.payouts.create"},
+	{"GET", K_PAYOUT_API + "/{id}", "paypal.payouts.get"},
+	{"GET", K_PAYOUT_ITEM_API + "/{id}", "paypal.payout_items.get"},
+	{"POST", K_PAYOUT_ITEM_API + "/{id}/cancel", "paypal.payout_items.cancel"},
+
+	{"POST", "/v2/invoicing/generate-next-invoice-number", "paypal.invoices.next_number"},
+	{"POST", K_INVOICE_API, "paypal.invoices.create"},
+	{"GET", K_INVOICE_API, "paypal.invoices.list"},
+	{"POST", "/v2/invoicing/search-invoices", "paypal.invoices.search"},
+	{"GET", K_INVOICE_API + "/{id}", "paypal.invoices.get"},
+	{"POST", K_INVOICE_API + "/{id}/send", "paypal.invoices.send"},
+	{"POST", K_INVOICE_API + "/{id}/remind", "paypal.invoices.remind"},
+	{"POST", K_INVOICE_API + "/{id}/cancel", "paypal.invoices.cancel"},
+	{"POST", K_INVOICE_API + "/{id}/payments", "paypal.invoices.record_payment"},
+	{"DELETE", K_INVOICE_API + "/{id}/payments/{id}", "paypal.invoices.delete_payment"},
+	{"POST", K_INVOICE_API + "/{id}/refunds", "paypal.invoices.record_refund"},
+	{"DELETE", K_INVOICE_API + "/{id}/refunds/{id}", "paypal.invoices.delete_refund"},
+	{"POST", K_INVOICE_API + "/{id}/generate-qr-code", "paypal.invoices.qr_code"},
+	{"GET", K_INVOICE_TEMPLATE_API, "paypal.invoice_templates.list"},
+	{"POST", K_INVOICE_TEMPLATE_API, "paypal.invoice_templates.create"},
+	{"GET", K_INVOICE_TEMPLATE_API + "/{id}", "paypal.invoice_templates.get"},
+	{"PUT", K_INVOICE_TEMPLATE_API + "/{id}", "paypal.invoice_templates.update"},
+	{"DELETE", K_INVOICE_TEMPLATE_API + "/{id}", "paypal.invoice_templates.delete"},
+
+	{"GET", K_DISPUTE_API, "paypal.disputes.list"},
+	{"GET", K_DISPUTE_API + "/{id}", "paypal.disputes.get"},
+	{"POST", K_DISPUTE_API + "/{id}/accept-claim", "paypal.disputes.accept_claim"},
+	{"POST", K_DISPUTE_API + "/{id}/make-offer", "paypal.disputes.make_offer"},
+	{"POST", K_DISPUTE_API + "/{id}/provide-evidence", "paypal.disputes.provide_evidence"},
+	{"POST", K_DISPUTE_API + "/{id}/appeal", "paypal.disputes.appeal"},
+	{"POST", K_DISPUTE_API + "/{id}/send-message", "paypal.disputes.send_message"},
+	{"POST", K_DISPUTE_API + "/{id}/escalate", "paypal.disputes.escalate"},
+
+	{"GET", K_REPORTING_TRANSACTION_API, "paypal.transactions.list"},
+	{"GET", K_REPORTING_BALANCE_API, "paypal.balances.list"},
+
+	{"POST", K_VAULT_SETUP_TOKEN_API, "paypal.setup_tokens.create"},
+	{"GET", K_VAULT_SETUP_TOKEN_API + "/{id}", "paypal.setup_tokens.get"},
+	{"POST", K_VAULT_PAYMENT_TOKEN_API, "paypal.payment_tokens.create"},
+	{"GET", K_VAULT_PAYMENT_TOKEN_API, "paypal.payment_tokens.list"},
+	{"GET", K_VAULT_PAYMENT_TOKEN_API + "/{id}", "paypal.payment_tokens.get"},
+	{"DELETE", K_VAULT_PAYMENT_TOKEN_API + "/{id}", "paypal.payment_tokens.delete"},
+
+	{"GET", K_IDENTITY_USERINFO_API, "paypal.identity.userinfo"},
+
+	{"POST", K_PARTNER_REFERRAL_API, "paypal.partner_referrals.create"},
+	{"GET", K_PARTNER_REFERRAL_API + "/{id}", "paypal.partner_referrals.get"},
+	{"GET", K_PARTNER_API + "/{id}/merchant-integrations", "paypal.merchant_integrations.find"},
+	{"GET", K_PARTNER_API + "/{id}/merchant-integrations/{id}", "paypal.merchant_integrations.get"},
+
+	{"POST", K_TRACKER_BATCH_API, "paypal.trackers.add"},
+	{"GET", K_TRACKER_API + "/{id}", "paypal.trackers.get"},
+	{"PUT", K_TRACKER_API + "/{id}", "paypal.trackers.update"},
+}
+
+// ResolveOperation returns the operation name and route template of a call, eg:
+// POST /v1/billing/subscriptions/I-BW452GLLEP1G/cancel returns paypal.subscriptions.cancel and /v1/billing/subscriptions/{id}/cancel.
+// Unknown paths return paypal.request and the path with every segment after the first three replaced by {id}
+func ResolveOperation(method, path string) (string, string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, r := range operationRoutes {
+		if r.method == method && matchRoute(r.route, segments) {
+			return r.operation, r.route
+		}
+	}
+	for i := 3; i < len(segments); i++ {
+		segments[i] = "{id}"
+	}
+	return "paypal.request", "/" + strings.Join(segments, "/")
+}
+
+func matchRoute(route string, segments []string) bool {
+	parts := strings.Split(strings.Trim(route, "/"), "/")
+	if len(parts) != len(segments) {
+		return false
+	}
+	for i, p := range parts {
+		if p != "{id}" && p != segments[i] {
+			return false
+		}
+	}
+	return true
+}
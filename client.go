@@ -2,6 +2,7 @@ package paypalsdk
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,27 +18,83 @@ const (
 	kGetAccessTokenAPI = "/v1/oauth2/token"
 )
 
+// Client represents a Paypal REST API Client
+type Client struct {
+	Client         *http.Client
+	ClientID       string
+	Secret         string
+	APIBase        string
+	Log            io.Writer // If user set log file name all requests will be logged there
+	Token          *TokenResponse
+	tokenExpiresAt time.Time
+
+	// AuthAssertion is sent as PayPal-Auth-Assertion, so that calls are made on behalf of a seller
+	AuthAssertion string
+	// PartnerAttributionID is the partner's BN code, sent as PayPal-Partner-Attribution-Id
+	PartnerAttributionID string
+
+	// ValidateRequests makes NewRequest validate payloads implementing Validator before they are sent
+	ValidateRequests bool
+	// PreflightChecks makes Activate/Suspend/CancelSubscription check the subscription can take the action first
+	PreflightChecks bool
+
+	Logger       Logger        // requests and responses are logged here, nil means the package logger
+	Retry        *RetryPolicy  // nil means no retry
+	Interceptors []Interceptor // see Use
+	Hooks        []CallHook    // see AddCallHooks
+	Metrics      Metrics       // nil means no metrics
+	UserAgent    string
+	Locale       string // sent as Accept-Language, empty means DefaultLocale
+
+	timeout time.Duration
+	ctx     context.Context // see WithContext
+	origin  *Client         // the client WithContext was called on, it keeps the refreshed access token
+}
+
 // NewClient returns new Client struct
-// APIBase is a base API URL, for testing you can use paypalsdk.APIBaseSandBox
-func NewClient(clientID string, secret string, APIBase string) (*Client, error) {
-	if clientID == "" || secret == "" || APIBase == "" {
-		return nil, errors.New("ClientID, Secret and APIBase are required to create a Client")
+// It calls the sandbox unless WithLive or WithAPIBase is given, eg:
+//
+//	c, err := paypalsdk.NewClient(clientID, secret, paypalsdk.WithLive(), paypalsdk.WithTimeout(10*time.Second))
+func NewClient(clientID string, secret string, opts ...ClientOption) (*Client, error) {
+	if clientID == "" || secret == "" {
+		return nil, errors.New("ClientID and Secret are required to create a Client")
 	}
 
-	return &Client{
-		Client:   &http.Client{},
+	c := &Client{
+		Client:   newHTTPClient(),
 		ClientID: clientID,
 		Secret:   secret,
-		APIBase:  APIBase,
-	}, nil
+		APIBase:  APIBaseSandBox,
+		Locale:   DefaultLocale,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.APIBase == "" {
+		return nil, errors.New("APIBase is required to create a Client")
+	}
+	if c.Client == nil {
+		c.Client = newHTTPClient()
+	}
+	if c.timeout > 0 {
+		// 不修改调用方传入的 http.Client
+		hc := *c.Client
+		hc.Timeout = c.timeout
+		c.Client = &hc
+	}
+	return c, nil
 }
 
 // GetAccessToken returns struct of TokenResponse
 // No need to call SetAccessToken to apply new access token for current Client
 // Endpoint: POST /v1/oauth2/token
 func (c *Client) GetAccessToken() (*TokenResponse, error) {
+	return c.getAccessToken(c.context())
+}
+
+func (c *Client) getAccessToken(ctx context.Context) (*TokenResponse, error) {
 	buf := bytes.NewBuffer([]byte("grant_type=client_credentials"))
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/oauth2/token"), buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/oauth2/token"), buf)
 	if err != nil {
 		return &TokenResponse{}, err
 	}
@@ -46,11 +103,15 @@ func (c *Client) GetAccessToken() (*TokenResponse, error) {
 
 	t := TokenResponse{}
 	err = c.SendWithBasicAuth(req, &t)
+	c.metrics().ObserveTokenRefresh(err)
 
 	// Set Token fur current Client
 	if t.Token != "" {
 		c.Token = &t
 		c.tokenExpiresAt = time.Now().Add(time.Duration(t.ExpiresIn) * time.Second)
+		if c.origin != nil {
+			c.origin.Token, c.origin.tokenExpiresAt = c.Token, c.tokenExpiresAt
+		}
 	}
 
 	return &t, err
@@ -69,57 +130,128 @@ func (c *Client) SetAccessToken(token string) {
 	c.tokenExpiresAt = time.Time{}
 }
 
+// SetPartnerAttributionID sets the BN code sent as PayPal-Partner-Attribution-Id with every call
+func (c *Client) SetPartnerAttributionID(id string) {
+	c.PartnerAttributionID = id
+}
+
+// SetAuthAssertion makes every call of current client act on behalf of the seller identified by merchantPayerID.
+// Pass an empty merchantPayerID to act as the partner itself again
+func (c *Client) SetAuthAssertion(merchantPayerID string) {
+	if merchantPayerID == "" {
+		c.AuthAssertion = ""
+		return
+	}
+	c.AuthAssertion = AuthAssertion(c.ClientID, merchantPayerID)
+}
+
+// OnBehalfOf returns a copy of current client which acts on behalf of the seller identified by merchantPayerID,
+// current client is left unchanged. The copy shares http.Client and the access token obtained so far
+func (c *Client) OnBehalfOf(merchantPayerID string) *Client {
+	seller := *c
+	seller.SetAuthAssertion(merchantPayerID)
+	return &seller
+}
+
+// WithContext returns a copy of current client whose requests carry ctx, current client is left unchanged.
+// Spans of the calls join the trace in ctx, and the calls are cancelled with ctx, eg:
+//
+//	sub, err := c.WithContext(ctx).CreateSubscription(req)
+//
+// The copy shares http.Client and the access token, a token it refreshes is kept by current client too
+func (c *Client) WithContext(ctx context.Context) *Client {
+	if ctx == nil {
+		panic("nil context")
+	}
+	cc := *c
+	cc.ctx = ctx
+	if c.origin != nil {
+		cc.origin = c.origin
+	} else {
+		cc.origin = c
+	}
+	return &cc
+}
+
+// context returns the context set by WithContext, or context.Background
+func (c *Client) context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// SetValidateRequests turns on/off client-side validation of request payloads.
+// When on, invalid requests fail with *ValidationError instead of being sent to PayPal
+func (c *Client) SetValidateRequests(validate bool) {
+	c.ValidateRequests = validate
+}
+
+// SetPreflightChecks turns on/off the checks of subscription status before actions.
+// When on, an action the subscription cannot take fails with *SubscriptionTransitionError instead of a 422 from PayPal,
+// at the cost of a ShowSubscriptionDetails call
+func (c *Client) SetPreflightChecks(check bool) {
+	c.PreflightChecks = check
+}
+
+// logger returns the Logger set by WithLogger, or the package logger
+func (c *Client) logger() Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return logger
+}
+
 // SetLog will set/change the output destination.
 // If log file is set paypalsdk will log all requests and responses to this Writer
 func (c *Client) SetLog(log io.Writer) {
 	c.Log = log
 }
-func (c *Client) Send(req *http.Request, result interface{}) error {
+func (c *Client) Send(req *http.Request, result interface{}) (err error) {
+	req, call, started := c.startCall(req)
+	if started {
+		defer func() { c.endCall(req, call, err) }()
+	}
+
+	locale := c.Locale
+	if locale == "" {
+		locale = DefaultLocale
+	}
 	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Accept-Language", "en_US")
+	req.Header.Add("Accept-Language", locale)
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
 
 	if req.Header.Get("Content-Type") == "" {
 		req.Header.Add("Content-Type", "application/json")
 	}
 
 	var (
-		err  error
 		rsp  *http.Response
 		data []byte
 	)
 
-	rsp, err = c.Client.Do(req)
+	rsp, err = c.roundTrip()(req)
 	if err != nil {
 		return err
 	}
 	defer rsp.Body.Close()
+	call.StatusCode = rsp.StatusCode
+	call.DebugID = rsp.Header.Get("Paypal-Debug-Id")
 
 	data, err = ioutil.ReadAll(rsp.Body)
 	if err != nil {
 		return err
 	}
 
-	if req.URL.Path != kGetAccessTokenAPI {
-		var buf = &bytes.Buffer{}
-		buf.WriteString("\n=========== Begin ============")
-		buf.WriteString("\n【请求信息】")
-		buf.WriteString(fmt.Sprintf("\n%s %d %s", req.Method, rsp.StatusCode, req.URL.String()))
-		for key := range req.Header {
-			buf.WriteString(fmt.Sprintf("\n%s: %s", key, req.Header.Get(key)))
-		}
-		buf.WriteString("\n【返回信息】")
-		for key := range rsp.Header {
-			buf.WriteString(fmt.Sprintf("\n%s: %s", key, rsp.Header.Get(key)))
-		}
-		buf.WriteString(fmt.Sprintf("\n%s", string(data)))
-		buf.WriteString("\n===========  End  ============")
-
-		logger.Println(buf.String())
-	}
-
 	switch rsp.StatusCode {
-	case http.StatusOK, http.StatusCreated:
-		if result != nil {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted:
+		if w, ok := result.(io.Writer); ok {
+			_, err = w.Write(data)
+			return err
+		}
+		if result != nil && len(data) > 0 {
 			if err = json.Unmarshal(data, result); err != nil {
 				if err.Error() == "json: cannot unmarshal number into Go value of type string" {
 					return nil
@@ -208,11 +340,18 @@ func (c *Client) Send(req *http.Request, v interface{}) error {
 // If the access token soon to be expired or already expired, it will try to get a new one before
 // making the main request
 // client.Token will be updated when changed
-func (c *Client) SendWithAuth(req *http.Request, v interface{}) error {
+func (c *Client) SendWithAuth(req *http.Request, v interface{}) (err error) {
+	req, call, started := c.startCall(req)
+	if started {
+		defer func() { c.endCall(req, call, err) }()
+	}
+
 	if c.Token != nil {
 		if !c.tokenExpiresAt.IsZero() && c.tokenExpiresAt.Sub(time.Now()) < RequestNewTokenBeforeExpiresIn {
 			// c.Token will be updated in GetAccessToken call
-			if _, err := c.GetAccessToken(); err != nil {
+			// 刷新 token 是单独的一次调用, 但与当前调用在同一链路中
+			call.TokenRefreshed = true
+			if _, err := c.getAccessToken(context.WithValue(req.Context(), callContextKey{}, (*Call)(nil))); err != nil {
 				return err
 			}
 		}
@@ -220,6 +359,13 @@ func (c *Client) SendWithAuth(req *http.Request, v interface{}) error {
 		req.Header.Set("Authorization", "Bearer "+c.Token.Token)
 	}
 
+	if c.AuthAssertion != "" && req.Header.Get("PayPal-Auth-Assertion") == "" {
+		req.Header.Set("PayPal-Auth-Assertion", c.AuthAssertion)
+	}
+	if c.PartnerAttributionID != "" && req.Header.Get("PayPal-Partner-Attribution-Id") == "" {
+		req.Header.Set("PayPal-Partner-Attribution-Id", c.PartnerAttributionID)
+	}
+
 	return c.Send(req, v)
 }
 
@@ -233,6 +379,19 @@ func (c *Client) SendWithBasicAuth(req *http.Request, v interface{}) error {
 // NewRequest constructs a request
 // Convert payload to a JSON
 func (c *Client) NewRequest(method, url string, payload interface{}) (*http.Request, error) {
+	return c.NewRequestWithContext(c.context(), method, url, payload)
+}
+
+// NewRequestWithContext constructs a request carrying ctx, see NewRequest
+func (c *Client) NewRequestWithContext(ctx context.Context, method, url string, payload interface{}) (*http.Request, error) {
+	if c.ValidateRequests {
+		if v, ok := payload.(Validator); ok {
+			if err := v.Validate(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	var buf io.Reader
 	if payload != nil {
 		var b []byte
@@ -242,7 +401,7 @@ func (c *Client) NewRequest(method, url string, payload interface{}) (*http.Requ
 		}
 		buf = bytes.NewBuffer(b)
 	}
-	request, err := http.NewRequest(method, url, buf)
+	request, err := http.NewRequestWithContext(ctx, method, url, buf)
 	if err != nil {
 		logrus.WithField("request", fmt.Sprintf("%+v", request)).WithError(err).Error("NewRequest:error")
 	}
@@ -0,0 +1,45 @@
+package paypalsdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"access_token":"A21AA","expires_in":32400}`))
+	}))
+	defer srv.Close()
+	c, err := NewClient("id", "secret", WithAPIBase(srv.URL), WithLogger(discardLogger{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "v")
+	req, err := c.WithContext(ctx).NewRequest("GET", srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Context().Value(key{}) != "v" {
+		t.Error("request of WithContext copy does not carry ctx")
+	}
+	if req, _ = c.NewRequest("GET", srv.URL, nil); req.Context().Value(key{}) != nil {
+		t.Error("WithContext changed current client")
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.WithContext(cancelled).GetAccessToken(); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetAccessToken with a cancelled context error = %v, want context.Canceled", err)
+	}
+	if _, err := c.WithContext(ctx).WithContext(ctx).GetAccessToken(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Token == nil || c.Token.Token != "A21AA" {
+		t.Errorf("token refreshed by WithContext copy not kept, Token = %+v", c.Token)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+/*
+配置文件：
+默认为 ~/.paypalctl/config, 每个 profile 一节, 如:
+
+	[default]
+	client_id = AXxx...
+	secret    = EXxx...
+	mode      = sandbox
+
+	[prod]
+	client_id = ...
+	secret    = ...
+	mode      = live
+
+凭据的优先级: -profile(或 PAYPALCTL_PROFILE) 指定的 profile > PAYPAL_CLIENT_ID、PAYPAL_SECRET、PAYPAL_MODE > default profile。
+mode 为 sandbox 或 live, 默认为 sandbox, 与 paypalsdk.NewClientFromEnv 一致; -sandbox 总是调用沙箱。
+*/
+
+const (
+	kEnvProfile = "PAYPALCTL_PROFILE"
+	kEnvConfig  = "PAYPALCTL_CONFIG"
+
+	kEnvClientID = "PAYPAL_CLIENT_ID"
+	kEnvSecret   = "PAYPAL_SECRET"
+	kEnvMode     = "PAYPAL_MODE"
+
+	kDefaultProfile = "default"
+)
+
+// profile is a section of the config file
+type profile struct {
+	ClientID string
+	Secret   string
+	Mode     string // sandbox or live
+}
+
+// credentialsSource is where the credentials are read from
+type credentialsSource struct {
+	profile string // empty to use the environment, or the default profile if the environment is not set
+	config  string // empty for the default config file
+}
+
+func (s credentialsSource) load() (*profile, error) {
+	if s.profile == "" {
+		if id, secret := os.Getenv(kEnvClientID), os.Getenv(kEnvSecret); id != "" && secret != "" {
+			return &profile{ClientID: id, Secret: secret, Mode: os.Getenv(kEnvMode)}, nil
+		}
+	}
+	name := s.profile
+	if name == "" {
+		name = kDefaultProfile
+	}
+	path, err := s.path()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) && s.profile == "" {
+		return nil, fmt.Errorf("no credentials: set %s and %s, or create %s", kEnvClientID, kEnvSecret, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	profiles, err := parseConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	p, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: no profile %q", path, name)
+	}
+	if p.ClientID == "" || p.Secret == "" {
+		return nil, fmt.Errorf("%s: client_id and secret are required in profile %q", path, name)
+	}
+	return p, nil
+}
+
+func (s credentialsSource) path() (string, error) {
+	if s.config != "" {
+		return s.config, nil
+	}
+	if path := os.Getenv(kEnvConfig); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".paypalctl", "config"), nil
+}
+
+// parseConfig parses the config file, lines starting with # or ; are comments
+func parseConfig(r io.Reader) (map[string]*profile, error) {
+	profiles := map[string]*profile{}
+	var current *profile
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			current = &profile{}
+			profiles[name] = current
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i < 0 || current == nil {
+			return nil, fmt.Errorf("line %d: expected [profile] or key = value", n)
+		}
+		key, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		switch key {
+		case "client_id":
+			current.ClientID = value
+		case "secret":
+			current.Secret = value
+		case "mode":
+			current.Mode = value
+		default:
+			return nil, fmt.Errorf("line %d: unknown key %q", n, key)
+		}
+	}
+	return profiles, scanner.Err()
+}
+
+// newClient creates the client from the credentials of src, opts are applied last
+func newClient(src credentialsSource, sandbox bool, apiBase string, opts ...paypalsdk.ClientOption) (*paypalsdk.Client, error) {
+	p, err := src.load()
+	if err != nil {
+		return nil, err
+	}
+	var base paypalsdk.ClientOption
+	switch mode := strings.ToLower(p.Mode); {
+	case apiBase != "":
+		base = paypalsdk.WithAPIBase(apiBase)
+	case sandbox, mode == "", mode == "sandbox":
+		base = paypalsdk.WithSandbox()
+	case mode == "live":
+		base = paypalsdk.WithLive()
+	default:
+		return nil, fmt.Errorf("mode must be sandbox or live, got %q", p.Mode)
+	}
+	return paypalsdk.NewClient(p.ClientID, p.Secret, append([]paypalsdk.ClientOption{base}, opts...)...)
+}
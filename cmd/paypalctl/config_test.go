@@ -0,0 +1,171 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+const testConfig = `# paypalctl
+[default]
+client_id = default-id
+secret    = default-secret
+
+; 正式环境
+[ prod ]
+client_id=prod-id
+secret=prod-secret
+mode = live
+
+[incomplete]
+client_id = incomplete-id
+`
+
+// writeConfig writes the config file to a temporary directory and clears the environment of credentials
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	for _, key := range []string{kEnvClientID, kEnvSecret, kEnvMode, kEnvProfile, kEnvConfig} {
+		t.Setenv(key, "")
+	}
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    map[string]*profile
+		wantErr string
+	}{
+		{
+			name:   "profiles",
+			config: testConfig,
+			want: map[string]*profile{
+				"default":    {ClientID: "default-id", Secret: "default-secret"},
+				"prod":       {ClientID: "prod-id", Secret: "prod-secret", Mode: "live"},
+				"incomplete": {ClientID: "incomplete-id"},
+			},
+		},
+		{name: "empty", config: "", want: map[string]*profile{}},
+		{name: "comments only", config: "# a\n; b\n\n", want: map[string]*profile{}},
+		{name: "value with =", config: "[a]\nsecret = x=y\n", want: map[string]*profile{"a": {Secret: "x=y"}}},
+		{name: "repeated profile", config: "[a]\nclient_id = 1\n[a]\nsecret = 2\n", want: map[string]*profile{"a": {Secret: "2"}}},
+		{name: "key before profile", config: "client_id = x\n[a]\n", wantErr: "line 1: expected [profile] or key = value"},
+		{name: "no =", config: "[a]\n\nclient_id x\n", wantErr: "line 3: expected [profile] or key = value"},
+		{name: "unknown key", config: "[a]\nregion = us\n", wantErr: `line 2: unknown key "region"`},
+	}
+	for _, tt := range tests {
+		got, err := parseConfig(strings.NewReader(tt.config))
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: profiles = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCredentialsSourceLoad(t *testing.T) {
+	tests := []struct {
+		name      string
+		profile   string
+		env       map[string]string
+		noConfig  bool
+		configEnv bool // 通过 PAYPALCTL_CONFIG 指定配置文件
+		wantID    string
+		wantMode  string
+		wantErr   string
+	}{
+		{name: "default profile", wantID: "default-id"},
+		{name: "environment", env: map[string]string{kEnvClientID: "env-id", kEnvSecret: "env-secret", kEnvMode: "live"}, wantID: "env-id", wantMode: "live"},
+		{name: "profile over environment", profile: "prod", env: map[string]string{kEnvClientID: "env-id", kEnvSecret: "env-secret"}, wantID: "prod-id", wantMode: "live"},
+		{name: "environment without secret", env: map[string]string{kEnvClientID: "env-id"}, wantID: "default-id"},
+		{name: "config from environment", configEnv: true, wantID: "default-id"},
+		{name: "environment without config file", noConfig: true, env: map[string]string{kEnvClientID: "env-id", kEnvSecret: "env-secret"}, wantID: "env-id"},
+		{name: "no credentials", noConfig: true, wantErr: "no credentials: set PAYPAL_CLIENT_ID and PAYPAL_SECRET, or create "},
+		{name: "profile without config file", profile: "prod", noConfig: true, wantErr: "no such file or directory"},
+		{name: "unknown profile", profile: "staging", wantErr: `no profile "staging"`},
+		{name: "incomplete profile", profile: "incomplete", wantErr: `client_id and secret are required in profile "incomplete"`},
+	}
+	for _, tt := range tests {
+		path := writeConfig(t, testConfig)
+		if tt.noConfig {
+			path = filepath.Join(filepath.Dir(path), "missing")
+		}
+		for key, value := range tt.env {
+			t.Setenv(key, value)
+		}
+		src := credentialsSource{profile: tt.profile, config: path}
+		if tt.configEnv {
+			t.Setenv(kEnvConfig, path)
+			src.config = ""
+		}
+
+		p, err := src.load()
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if p.ClientID != tt.wantID || p.Mode != tt.wantMode {
+			t.Errorf("%s: profile = %+v, want client_id %s mode %q", tt.name, p, tt.wantID, tt.wantMode)
+		}
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		mode     string
+		sandbox  bool
+		apiBase  string
+		wantBase string
+		wantErr  bool
+	}{
+		{"", false, "", paypalsdk.APIBaseSandBox, false},
+		{"sandbox", false, "", paypalsdk.APIBaseSandBox, false},
+		{"live", false, "", paypalsdk.APIBaseLive, false},
+		{"LIVE", false, "", paypalsdk.APIBaseLive, false},
+		{"live", true, "", paypalsdk.APIBaseSandBox, false},
+		{"live", true, "http://127.0.0.1:8080", "http://127.0.0.1:8080", false},
+		{"production", false, "", "", true},
+		{"production", true, "", paypalsdk.APIBaseSandBox, false},
+	}
+	for _, tt := range tests {
+		writeConfig(t, "")
+		t.Setenv(kEnvClientID, "env-id")
+		t.Setenv(kEnvSecret, "env-secret")
+		t.Setenv(kEnvMode, tt.mode)
+
+		c, err := newClient(credentialsSource{}, tt.sandbox, tt.apiBase)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("mode %q: newClient succeeded, want an error", tt.mode)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("mode %q: %v", tt.mode, err)
+		}
+		if c.APIBase != tt.wantBase || c.ClientID != "env-id" {
+			t.Errorf("mode %q, -sandbox=%v, -api-base=%q: APIBase = %s, want %s", tt.mode, tt.sandbox, tt.apiBase, c.APIBase, tt.wantBase)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Command paypalctl operates on PayPal resources from the shell, with the SDK instead of curl:
+//
+//	paypalctl [flags] token
+//	paypalctl [flags] subscription show|cancel|suspend|activate|transactions <subscription-id>
+//	paypalctl [flags] plan list|create
+//	paypalctl [flags] webhook list|create|delete|sync
+//	paypalctl [flags] event list|resend|verify
+//
+// Credentials are read from PAYPAL_CLIENT_ID, PAYPAL_SECRET and PAYPAL_MODE, or from a profile of
+// ~/.paypalctl/config (see config.go) when -profile is given or the environment is not set.
+// Run a command with -h for its flags.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+// errUsage is returned after the usage of a command was printed, the exit code is 2
+var errUsage = errors.New("usage")
+
+// app is the state shared by the commands
+type app struct {
+	client  *paypalsdk.Client // set by parse
+	connect func() (*paypalsdk.Client, error)
+	out     *printer
+	stdin   io.Reader
+	stderr  io.Writer
+}
+
+// action is a subcommand, args are the arguments after its name
+type action func(a *app, args []string) error
+
+// commands are the commands and their subcommands, token has no subcommand
+var commands = map[string]map[string]action{
+	"token": {"": tokenCmd},
+	"subscription": {
+		"show":         subscriptionShowCmd,
+		"cancel":       subscriptionCancelCmd,
+		"suspend":      subscriptionSuspendCmd,
+		"activate":     subscriptionActivateCmd,
+		"transactions": subscriptionTransactionsCmd,
+	},
+	"plan": {
+		"list":   planListCmd,
+		"create": planCreateCmd,
+	},
+	"webhook": {
+		"list":   webhookListCmd,
+		"create": webhookCreateCmd,
+		"delete": webhookDeleteCmd,
+		"sync":   webhookSyncCmd,
+	},
+	"event": {
+		"list":   eventListCmd,
+		"resend": eventResendCmd,
+		"verify": eventVerifyCmd,
+	},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("paypalctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		profile = fs.String("profile", os.Getenv(kEnvProfile), "profile of the config file, instead of the environment")
+		config  = fs.String("config", "", "config file, default is $"+kEnvConfig+" or ~/.paypalctl/config")
+		sandbox = fs.Bool("sandbox", false, "call the sandbox, whatever the mode of the environment or profile")
+		apiBase = fs.String("api-base", "", "call this base URL instead of PayPal, eg: a fake server")
+		output  = fs.String("o", "table", "output format: table or json")
+		verbose = fs.Bool("v", false, "log requests and responses to stderr")
+	)
+	fs.Usage = func() { usage(stderr, fs) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(stderr, "paypalctl: -o must be table or json, got %q\n", *output)
+		return 2
+	}
+
+	args = fs.Args()
+	if len(args) == 0 {
+		fs.Usage()
+		return 2
+	}
+	subs, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "paypalctl: unknown command %q\n", args[0])
+		fs.Usage()
+		return 2
+	}
+	cmd, rest := subs[""], args[1:]
+	if cmd == nil {
+		if len(rest) == 0 || subs[rest[0]] == nil {
+			fmt.Fprintf(stderr, "usage: paypalctl %s %s\n", args[0], strings.Join(names(subs), "|"))
+			return 2
+		}
+		cmd, rest = subs[rest[0]], rest[1:]
+	}
+
+	opts := []paypalsdk.ClientOption{paypalsdk.WithLogger(discard{})}
+	if *verbose {
+		opts[0] = paypalsdk.WithLogger(stderrLogger{stderr})
+	}
+	connect := func() (*paypalsdk.Client, error) {
+		client, err := newClient(credentialsSource{profile: *profile, config: *config}, *sandbox, *apiBase, opts...)
+		if err != nil {
+			return nil, err
+		}
+		// SendWithAuth 不会自动获取第一个 token
+		_, err = client.GetAccessToken()
+		return client, err
+	}
+	a := &app{connect: connect, out: &printer{w: stdout, json: *output == "json"}, stdin: stdin, stderr: stderr}
+	switch err := cmd(a, rest); err {
+	case nil:
+		return 0
+	case errUsage, flag.ErrHelp:
+		return 2
+	default:
+		fmt.Fprintln(stderr, "paypalctl:", err)
+		return 1
+	}
+}
+
+func usage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintln(w, "usage: paypalctl [flags] <command> [<subcommand>] [flags] [args]")
+	fmt.Fprintln(w, "\ncommands:")
+	for _, name := range names(commands) {
+		if sub := names(commands[name]); len(sub) > 0 && sub[0] != "" {
+			fmt.Fprintf(w, "  %-13s %s\n", name, strings.Join(sub, "|"))
+		} else {
+			fmt.Fprintf(w, "  %s\n", name)
+		}
+	}
+	fmt.Fprintln(w, "\nflags:")
+	fs.PrintDefaults()
+}
+
+func names(m interface{}) []string {
+	var list []string
+	switch m := m.(type) {
+	case map[string]map[string]action:
+		for name := range m {
+			list = append(list, name)
+		}
+	case map[string]action:
+		for name := range m {
+			list = append(list, name)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// newFlagSet returns the flag set of a subcommand, its usage line is "paypalctl name args"
+func (a *app) newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "usage: paypalctl %s [flags] %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses the flags of a subcommand and returns its arguments, there must be n of them.
+// Flags may follow the arguments, eg: subscription cancel I-XXX -reason "...".
+// The client is created afterwards, so that -h and usage errors do not need credentials
+func (a *app) parse(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != n {
+		fs.Usage()
+		return nil, errUsage
+	}
+	client, err := a.connect()
+	if err != nil {
+		return nil, err
+	}
+	a.client = client
+	return positional, nil
+}
+
+func tokenCmd(a *app, args []string) error {
+	fs := a.newFlagSet("token", "")
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	token := a.client.Token
+	return a.out.print(token, []string{"TOKEN", "TYPE", "EXPIRES IN"}, [][]string{
+		{token.Token, token.Type, fmt.Sprintf("%ds", token.ExpiresIn)},
+	})
+}
+
+type discard struct{}
+
+func (discard) Println(v ...interface{}) {}
+
+type stderrLogger struct {
+	w io.Writer
+}
+
+func (l stderrLogger) Println(v ...interface{}) {
+	fmt.Fprintln(l.w, v...)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+	"github.com/YYRise/PayPal-GO-SDK/paypaltest"
+)
+
+// newServer returns a fake with a product, a plan of it and a subscription approved by the buyer,
+// and sets the environment to its credentials
+func newServer(t *testing.T) (s *paypaltest.Server, planID, subID string) {
+	t.Helper()
+	writeConfig(t, "")
+	t.Setenv(kEnvClientID, paypaltest.DefaultClientID)
+	t.Setenv(kEnvSecret, paypaltest.DefaultSecret)
+	s = paypaltest.NewServer()
+	t.Cleanup(s.Close)
+
+	c, err := s.NewClient(paypalsdk.WithLogger(discard{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := c.CreateProduct(&paypalsdk.Product{Name: "Video streaming", Type: paypalsdk.E_PRODUCT_TYPE_SERVICE})
+	if err != nil {
+		t.Fatal(err)
+	}
+	amount := paypalsdk.NewMoney("USD", 999)
+	plan, err := c.CreatePlan(&paypalsdk.Plan{
+		ProductID: product.ID,
+		Name:      "Monthly",
+		BillingCycles: []*paypalsdk.BillingCycle{{
+			TenureType:    paypalsdk.E_TENURE_TYPE_REGULAR,
+			Sequence:      1,
+			Frequency:     &paypalsdk.Frequency{IntervalUnit: paypalsdk.E_FREQUENCY_INTERVAL_MONTH, IntervalCount: 1},
+			PricingScheme: &paypalsdk.PricingScheme{FixedPrice: amount},
+		}},
+		PaymentPreferences: &paypalsdk.PaymentPreferences{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := c.CreateSubscription(&paypalsdk.CreateSubscriptionReq{PlanID: plan.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ApproveSubscription(sub.ID); err != nil {
+		t.Fatal(err)
+	}
+	return s, plan.ID, sub.ID
+}
+
+// field returns the value of a field printed by printFields
+func field(out, name string) string {
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, name+":") {
+			return strings.TrimSpace(strings.TrimPrefix(line, name+":"))
+		}
+	}
+	return ""
+}
+
+func TestRun(t *testing.T) {
+	s, planID, subID := newServer(t)
+	base := []string{"-api-base", s.URL}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantOut    []string // stdout 包含的内容
+		wantStderr string
+	}{
+		{"token", []string{"token"}, 0, []string{"TOKEN", "Bearer"}, ""},
+		{"subscription show", []string{"subscription", "show", subID}, 0, []string{"ID:", subID, planID}, ""},
+		{"plan list", []string{"plan", "list"}, 0, []string{"ID  ", "STATUS", planID + "  ACTIVE"}, ""},
+		{"unknown subscription", []string{"subscription", "show", "I-404"}, 1, nil, "The specified resource does not exist."},
+		{"suspend without reason", []string{"subscription", "suspend", subID}, 2, nil, "-reason is required"},
+		{"flags after arguments", []string{"subscription", "suspend", subID, "-reason", "Customer request"}, 0, []string{"SUSPENDED"}, ""},
+		{"rejected action", []string{"subscription", "suspend", subID, "-reason", "Customer request"}, 1, nil, "SUSPENDED"},
+		{"missing argument", []string{"subscription", "show"}, 2, nil, "usage: paypalctl subscription show"},
+		{"unknown command", []string{"refund"}, 2, nil, `unknown command "refund"`},
+		{"unknown subcommand", []string{"plan", "delete"}, 2, nil, "usage: paypalctl plan create|list"},
+		{"bad output", []string{"-o", "yaml", "token"}, 2, nil, "-o must be table or json"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		code := run(append(base, tt.args...), strings.NewReader(""), &stdout, &stderr)
+		if code != tt.wantCode {
+			t.Errorf("%s: exit code = %d, want %d, stderr: %s", tt.name, code, tt.wantCode, stderr.String())
+		}
+		for _, want := range tt.wantOut {
+			if !strings.Contains(stdout.String(), want) {
+				t.Errorf("%s: stdout does not contain %q:\n%s", tt.name, want, stdout.String())
+			}
+		}
+		if !strings.Contains(stderr.String(), tt.wantStderr) {
+			t.Errorf("%s: stderr = %q, want %q", tt.name, stderr.String(), tt.wantStderr)
+		}
+	}
+}
+
+func TestRunSubscriptionShow(t *testing.T) {
+	s, planID, subID := newServer(t)
+	tests := []struct {
+		output string
+		check  func(out string) error
+	}{
+		{"table", func(out string) error {
+			if field(out, "Status") != "ACTIVE" || field(out, "Plan") != planID || field(out, "Entitled") != "true" {
+				return fmt.Errorf("fields Status %q, Plan %q, Entitled %q", field(out, "Status"), field(out, "Plan"), field(out, "Entitled"))
+			}
+			if !strings.HasPrefix(field(out, "Last payment"), "9.99 USD at ") {
+				return fmt.Errorf("Last payment %q", field(out, "Last payment"))
+			}
+			return nil
+		}},
+		{"json", func(out string) error {
+			sub := &paypalsdk.Subscription{}
+			if err := json.Unmarshal([]byte(out), sub); err != nil {
+				return err
+			}
+			if sub.ID != subID || sub.Status != paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE || sub.BillingInfo.LastPayment == nil {
+				return fmt.Errorf("subscription %+v", sub)
+			}
+			return nil
+		}},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run([]string{"-api-base", s.URL, "-o", tt.output, "subscription", "show", subID}, nil, &stdout, &stderr); code != 0 {
+			t.Fatalf("-o %s: exit code = %d, stderr: %s", tt.output, code, stderr.String())
+		}
+		if err := tt.check(stdout.String()); err != nil {
+			t.Errorf("-o %s: %v, output:\n%s", tt.output, err, stdout.String())
+		}
+	}
+}
+
+func TestRunPlanList(t *testing.T) {
+	s, planID, _ := newServer(t)
+	for _, output := range []string{"table", "json"} {
+		var stdout, stderr bytes.Buffer
+		if code := run([]string{"-api-base", s.URL, "-o", output, "plan", "list"}, nil, &stdout, &stderr); code != 0 {
+			t.Fatalf("-o %s: exit code = %d, stderr: %s", output, code, stderr.String())
+		}
+		var ids []string
+		if output == "json" {
+			list := &paypalsdk.PlanList{}
+			if err := json.Unmarshal(stdout.Bytes(), list); err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range list.Plans {
+				ids = append(ids, p.ID)
+			}
+		} else {
+			lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+			for _, line := range lines[1:] {
+				ids = append(ids, strings.Fields(line)[0])
+			}
+		}
+		if len(ids) != 1 || ids[0] != planID {
+			t.Errorf("-o %s: plans %v, want [%s]", output, ids, planID)
+		}
+	}
+}
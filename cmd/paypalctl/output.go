@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+// printer writes the result of a command, as indented JSON or as a table
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+// print writes v as JSON, or header and rows as a table
+func (p *printer) print(v interface{}, header []string, rows [][]string) error {
+	if p.json {
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// printFields writes v as JSON, or fields as a two-column table of names and values
+func (p *printer) printFields(v interface{}, fields [][2]string) error {
+	if p.json {
+		return p.print(v, nil, nil)
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	for _, f := range fields {
+		fmt.Fprintf(tw, "%s:\t%s\n", f[0], f[1])
+	}
+	return tw.Flush()
+}
+
+// formatTime 零值时间显示为 -
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatMoney(m *paypalsdk.Money) string {
+	if m == nil {
+		return "-"
+	}
+	return m.Value + " " + m.CurrencyCode
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
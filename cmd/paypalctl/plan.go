@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+func planListCmd(a *app, args []string) error {
+	fs := a.newFlagSet("plan list", "")
+	product := fs.String("product", "", "only the plans of this product ID")
+	page := fs.Int("page", 1, "page number")
+	pageSize := fs.Int("page-size", 20, "plans per page, at most 20")
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	list, err := a.client.ListPlans(*product, *page, *pageSize)
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, p := range list.Plans {
+		rows = append(rows, []string{p.ID, string(p.Status), p.ProductID, p.Name, formatTime(p.CreateTime)})
+	}
+	return a.out.print(list, []string{"ID", "STATUS", "PRODUCT", "NAME", "CREATED"}, rows)
+}
+
+// planCreateCmd creates a plan from a JSON file, or a plan of one regular billing cycle from flags,
+// optionally preceded by a free trial
+func planCreateCmd(a *app, args []string) error {
+	fs := a.newFlagSet("plan create", "")
+	file := fs.String("f", "", "JSON file of the plan, - for stdin. The other flags are ignored")
+	product := fs.String("product", "", "product ID")
+	name := fs.String("name", "", "plan name")
+	description := fs.String("description", "", "plan description")
+	price := fs.String("price", "", "price of a billing cycle, eg: 9.99")
+	currency := fs.String("currency", "USD", "currency code of the price")
+	interval := fs.String("interval", string(paypalsdk.E_FREQUENCY_INTERVAL_MONTH), "billing interval: DAY, WEEK, MONTH or YEAR")
+	intervalCount := fs.Int("interval-count", 1, "intervals per billing cycle")
+	cycles := fs.Int("cycles", 0, "number of billing cycles, 0 for unlimited")
+	trial := fs.Int("trial", 0, "intervals of free trial before the first billing cycle, 0 for none")
+	inactive := fs.Bool("inactive", false, "create the plan as CREATED instead of ACTIVE")
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	var plan *paypalsdk.Plan
+	if *file != "" {
+		var err error
+		if plan, err = readPlan(a, *file); err != nil {
+			return err
+		}
+	} else {
+		amount, err := paypalsdk.ParseMoney(*currency, *price)
+		if err != nil {
+			return fmt.Errorf("-price: %v", err)
+		}
+		frequency := &paypalsdk.Frequency{IntervalUnit: paypalsdk.E_FrequencyInterval(strings.ToUpper(*interval)), IntervalCount: *intervalCount}
+		plan = &paypalsdk.Plan{ProductID: *product, Name: *name, Description: *description}
+		if *trial > 0 {
+			plan.BillingCycles = append(plan.BillingCycles, &paypalsdk.BillingCycle{
+				PricingScheme: &paypalsdk.PricingScheme{FixedPrice: paypalsdk.NewMoney(amount.CurrencyCode, 0)},
+				Frequency:     &paypalsdk.Frequency{IntervalUnit: frequency.IntervalUnit, IntervalCount: *trial},
+				TenureType:    paypalsdk.E_TENURE_TYPE_TRIAL,
+				Sequence:      1,
+				TotalCycles:   1,
+			})
+		}
+		plan.BillingCycles = append(plan.BillingCycles, &paypalsdk.BillingCycle{
+			PricingScheme: &paypalsdk.PricingScheme{FixedPrice: amount},
+			Frequency:     frequency,
+			TenureType:    paypalsdk.E_TENURE_TYPE_REGULAR,
+			Sequence:      len(plan.BillingCycles) + 1,
+			TotalCycles:   *cycles,
+		})
+		if *inactive {
+			plan.Status = paypalsdk.E_PLAN_STATUS_CREATED
+		}
+	}
+
+	// 在本地校验, 错误信息比 PayPal 的 INVALID_REQUEST 更明确
+	if err := plan.Validate(); err != nil {
+		return err
+	}
+	created, err := a.client.CreatePlan(plan)
+	if err != nil {
+		return err
+	}
+	return a.out.printFields(created, [][2]string{
+		{"ID", created.ID},
+		{"Status", string(created.Status)},
+		{"Product", created.ProductID},
+		{"Name", created.Name},
+		{"Created", formatTime(created.CreateTime)},
+	})
+}
+
+func readPlan(a *app, file string) (*paypalsdk.Plan, error) {
+	var r io.Reader = a.stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	plan := &paypalsdk.Plan{}
+	if err := json.NewDecoder(r).Decode(plan); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return plan, nil
+}
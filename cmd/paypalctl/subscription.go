@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+func subscriptionShowCmd(a *app, args []string) error {
+	fs := a.newFlagSet("subscription show", "<subscription-id>")
+	args, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	return a.showSubscription(args[0])
+}
+
+func subscriptionCancelCmd(a *app, args []string) error {
+	return a.subscriptionAction(paypalsdk.E_SUBSCRIPTION_ACTION_CANCEL, (*paypalsdk.Client).CancelSubscription, args)
+}
+
+func subscriptionSuspendCmd(a *app, args []string) error {
+	return a.subscriptionAction(paypalsdk.E_SUBSCRIPTION_ACTION_SUSPEND, (*paypalsdk.Client).SuspendSubscription, args)
+}
+
+func subscriptionActivateCmd(a *app, args []string) error {
+	return a.subscriptionAction(paypalsdk.E_SUBSCRIPTION_ACTION_ACTIVATE, (*paypalsdk.Client).ActivateSubscription, args)
+}
+
+// subscriptionAction takes action on a subscription and shows it afterwards. The status is checked first,
+// so that an action PayPal would reject fails with the current status instead of UNPROCESSABLE_ENTITY
+func (a *app) subscriptionAction(action paypalsdk.E_SubscriptionAction, do func(c *paypalsdk.Client, id, reason string) error, args []string) error {
+	fs := a.newFlagSet("subscription "+string(action), "<subscription-id>")
+	reason := fs.String("reason", "", "reason of the action, required, 1 to 128 characters")
+	args, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *reason == "" {
+		fmt.Fprintln(a.stderr, "paypalctl: -reason is required")
+		fs.Usage()
+		return errUsage
+	}
+	a.client.SetPreflightChecks(true)
+	if err := do(a.client, args[0], *reason); err != nil {
+		return err
+	}
+	return a.showSubscription(args[0])
+}
+
+func (a *app) showSubscription(id string) error {
+	sub, err := a.client.ShowSubscriptionDetails(id)
+	if err != nil {
+		return err
+	}
+	fields := [][2]string{
+		{"ID", sub.ID},
+		{"Status", string(sub.Status)},
+		{"Status note", orDash(sub.StatusChangeNote)},
+		{"Status updated", formatTime(sub.StatusUpdateTime)},
+		{"Plan", sub.PlanID},
+		{"Quantity", orDash(sub.Quantity)},
+		{"Started", formatTime(sub.StartTime)},
+		{"Created", formatTime(sub.CreateTime)},
+		{"Updated", formatTime(sub.UpdateTime)},
+	}
+	if s := sub.Subscriber; s != nil {
+		name := "-"
+		if s.Name != nil {
+			name = s.Name.GivenName + " " + s.Name.Surname
+		}
+		fields = append(fields, [2]string{"Subscriber", name}, [2]string{"Email", orDash(s.EmailAddress)}, [2]string{"Payer ID", orDash(s.PayerId)})
+	}
+	if b := sub.BillingInfo; b != nil {
+		fields = append(fields,
+			[2]string{"Next billing", formatTime(b.NextBillingTime)},
+			[2]string{"Outstanding", formatMoney(b.OutstandingBalance)},
+			[2]string{"Failed payments", fmt.Sprint(b.FailedPaymentsCount)},
+		)
+		if p := b.LastPayment; p != nil {
+			fields = append(fields, [2]string{"Last payment", formatMoney(p.Amount) + " at " + p.Time})
+		}
+	}
+	var plan *paypalsdk.Plan
+	if sub.PaidThrough().IsZero() && sub.Status.IsTerminal() {
+		// 取消、过期后没有 next_billing_time, 根据最后一次付款和 plan 推算
+		if p, err := a.client.ShowPlanDetails(sub.PlanID); err == nil {
+			plan = p
+		}
+	}
+	fields = append(fields,
+		[2]string{"Paid through", formatTime(sub.PaidThroughFor(plan))},
+		[2]string{"Entitled", fmt.Sprint(sub.IsEntitled(time.Now(), plan))},
+	)
+	return a.out.printFields(sub, fields)
+}
+
+func subscriptionTransactionsCmd(a *app, args []string) error {
+	fs := a.newFlagSet("subscription transactions", "<subscription-id>")
+	now := time.Now().UTC()
+	start := fs.String("start", now.AddDate(0, 0, -30).Format(time.RFC3339), "start of the period, RFC 3339")
+	end := fs.String("end", now.Format(time.RFC3339), "end of the period, RFC 3339")
+	args, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	rsp, err := a.client.ListTransactionsForSubscription(args[0], *start, *end)
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, tx := range rsp.Transactions {
+		row := []string{tx.ID, string(tx.Status), formatTime(tx.Time), "-", "-", "-", orDash(tx.PayerEmail)}
+		if b := tx.AmountWithBreakdown; b != nil {
+			row[3], row[4], row[5] = formatMoney(&b.GrossAmount), formatMoney(&b.FeeAmount), formatMoney(&b.NetAmount)
+		}
+		rows = append(rows, row)
+	}
+	return a.out.print(rsp, []string{"ID", "STATUS", "TIME", "GROSS", "FEE", "NET", "PAYER"}, rows)
+}
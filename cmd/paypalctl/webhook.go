@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+func webhookListCmd(a *app, args []string) error {
+	fs := a.newFlagSet("webhook list", "")
+	anchor := fs.String("anchor", "APPLICATION", "APPLICATION or ACCOUNT")
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	list, err := a.client.ListWebhooks(strings.ToUpper(*anchor))
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, wh := range list.Webhooks {
+		rows = append(rows, webhookRow(wh, ""))
+	}
+	return a.out.print(list, []string{"ID", "URL", "EVENT TYPES"}, rows)
+}
+
+func webhookCreateCmd(a *app, args []string) error {
+	fs := a.newFlagSet("webhook create", "")
+	url := fs.String("url", "", "HTTPS URL receiving the events, required")
+	events := fs.String("events", "*", "comma-separated event types, * for every event")
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	if *url == "" {
+		fmt.Fprintln(a.stderr, "paypalctl: -url is required")
+		fs.Usage()
+		return errUsage
+	}
+	q := &paypalsdk.CreateWebhookReq{Url: *url, EventTypes: eventTypes(*events)}
+	wh, err := a.client.CreateWebhook(q)
+	if err != nil {
+		return err
+	}
+	return a.out.print(wh, []string{"ID", "URL", "EVENT TYPES"}, [][]string{webhookRow(wh, "")})
+}
+
+func webhookDeleteCmd(a *app, args []string) error {
+	fs := a.newFlagSet("webhook delete", "<webhook-id>")
+	args, err := a.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if err := a.client.DeleteWebhook(args[0]); err != nil {
+		return err
+	}
+	return a.out.print(map[string]string{"id": args[0], "result": "deleted"}, []string{"ID", "RESULT"}, [][]string{{args[0], "deleted"}})
+}
+
+// webhookSyncCmd makes the webhook of -url subscribe to exactly -events: it is created if missing,
+// and its event types are replaced if they differ. It is safe to run on every deploy
+func webhookSyncCmd(a *app, args []string) error {
+	fs := a.newFlagSet("webhook sync", "")
+	url := fs.String("url", "", "HTTPS URL receiving the events, required")
+	events := fs.String("events", "*", "comma-separated event types, * for every event")
+	dryRun := fs.Bool("dry-run", false, "print what would be done without doing it")
+	if _, err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	if *url == "" {
+		fmt.Fprintln(a.stderr, "paypalctl: -url is required")
+		fs.Usage()
+		return errUsage
+	}
+	q := &paypalsdk.CreateWebhookReq{Url: *url, EventTypes: eventTypes(*events)}
+	list, err := a.client.ListWebhooks("APPLICATION")
+	if err != nil {
+		return err
+	}
+
+	var (
+		wh     *paypalsdk.Webhook
+		result string
+	)
+	for _, w := range list.Webhooks {
+		if w.CreateWebhookReq != nil && w.Url == q.Url {
+			wh = w
+		}
+	}
+	switch {
+	case wh == nil:
+		result = "created"
+		wh = &paypalsdk.Webhook{CreateWebhookReq: q}
+		if !*dryRun {
+			if wh, err = a.client.CreateWebhook(q); err != nil {
+				return err
+			}
+		}
+	case eventTypeNames(wh.EventTypes) != eventTypeNames(q.EventTypes):
+		result = "updated"
+		if !*dryRun {
+			patches := []paypalsdk.Patch{{Op: paypalsdk.E_PATCH_OP_REPLACE, Path: "/event_types", Value: q.EventTypes}}
+			if wh, err = a.client.UpdateWebhook(wh.ID, patches); err != nil {
+				return err
+			}
+		} else {
+			wh.EventTypes = q.EventTypes
+		}
+	default:
+		result = "unchanged"
+	}
+	if *dryRun {
+		result += " (dry run)"
+	}
+	return a.out.print(map[string]interface{}{"webhook": wh, "result": result}, []string{"ID", "URL", "EVENT TYPES", "RESULT"}, [][]string{webhookRow(wh, result)})
+}
+
+func webhookRow(wh *paypalsdk.Webhook, result string) []string {
+	row := []string{orDash(wh.ID), "-", "-"}
+	if wh.CreateWebhookReq != nil {
+		row[1], row[2] = wh.Url, eventTypeNames(wh.EventTypes)
+	}
+	if result != "" {
+		row = append(row, result)
+	}
+	return row
+}
+
+// eventTypes parses a comma-separated list of event types
+func eventTypes(list string) []*paypalsdk.EventType {
+	var types []*paypalsdk.EventType
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			types = append(types, &paypalsdk.EventType{Name: name})
+		}
+	}
+	return types
+}
+
+// eventTypeNames returns the sorted names of types, comma-separated, to compare and print them
+func eventTypeNames(types []*paypalsdk.EventType) string {
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
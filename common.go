@@ -156,6 +156,43 @@ type FailedPaymentDetails struct {
 	NextPaymentRetryTime string `json:"next_payment_retry_time,omitempty"` // 只读
 }
 
+// https://developer.paypal.com/docs/api/reference/api-responses/#hateoas-links
+type E_LinkRel string
+
+const (
+	E_LINK_REL_SELF           E_LinkRel = "self"
+	E_LINK_REL_APPROVE        E_LinkRel = "approve"      // 将付款人重定向到该链接进行授权, 如订阅、setup token。
+	E_LINK_REL_PAYER_ACTION   E_LinkRel = "payer-action" // 订单(Orders v2)需要付款人操作的链接。
+	E_LINK_REL_EDIT           E_LinkRel = "edit"
+	E_LINK_REL_UPDATE         E_LinkRel = "update"
+	E_LINK_REL_REPLACE        E_LinkRel = "replace"
+	E_LINK_REL_DELETE         E_LinkRel = "delete"
+	E_LINK_REL_CREATE         E_LinkRel = "create"
+	E_LINK_REL_CAPTURE        E_LinkRel = "capture"
+	E_LINK_REL_REAUTHORIZE    E_LinkRel = "reauthorize"
+	E_LINK_REL_VOID           E_LinkRel = "void"
+	E_LINK_REL_REFUND         E_LinkRel = "refund"
+	E_LINK_REL_CANCEL         E_LinkRel = "cancel"
+	E_LINK_REL_SUSPEND        E_LinkRel = "suspend"
+	E_LINK_REL_ACTIVATE       E_LinkRel = "activate"
+	E_LINK_REL_RESEND         E_LinkRel = "resend"
+	E_LINK_REL_UP             E_LinkRel = "up"
+	E_LINK_REL_PARENT_PAYMENT E_LinkRel = "parent_payment"
+	E_LINK_REL_ACTION_URL     E_LinkRel = "action_url" // 卖家接入(partner referral)的注册/授权链接。
+	E_LINK_REL_FIRST          E_LinkRel = "first"
+	E_LINK_REL_PREV           E_LinkRel = "prev"
+	E_LINK_REL_NEXT           E_LinkRel = "next"
+	E_LINK_REL_LAST           E_LinkRel = "last"
+)
+
+// v1 接口(如 payments/sale、notifications)返回的链接
+type Link struct {
+	Href    string    `json:"href"`
+	Rel     E_LinkRel `json:"rel,omitempty"`
+	Method  string    `json:"method,omitempty"`
+	Enctype string    `json:"encType,omitempty"`
+}
+
 // https://developer.paypal.com/docs/api/subscriptions/v1/#definition-link_description
 type LinkDescription struct {
 	Href   string    `json:"href"`
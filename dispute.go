@@ -0,0 +1,418 @@
+package paypalsdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+)
+
+const (
+	K_DISPUTE_API = "/v1/customer/disputes"
+)
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-status
+type E_DisputeStatus string
+
+const (
+	E_DISPUTE_STATUS_OPEN                        E_DisputeStatus = "OPEN"                        // 等待另一方回复。
+	E_DISPUTE_STATUS_WAITING_FOR_BUYER_RESPONSE  E_DisputeStatus = "WAITING_FOR_BUYER_RESPONSE"  // 等待买家回复。
+	E_DISPUTE_STATUS_WAITING_FOR_SELLER_RESPONSE E_DisputeStatus = "WAITING_FOR_SELLER_RESPONSE" // 等待卖家回复, 超过 seller_response_due_date 将判买家胜诉。
+	E_DISPUTE_STATUS_UNDER_REVIEW                E_DisputeStatus = "UNDER_REVIEW"                // PayPal 审核中。
+	E_DISPUTE_STATUS_RESOLVED                    E_DisputeStatus = "RESOLVED"                    // 已解决。
+	E_DISPUTE_STATUS_OTHER                       E_DisputeStatus = "OTHER"
+)
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-dispute_reason
+type E_DisputeReason string
+
+const (
+	E_DISPUTE_REASON_MERCHANDISE_OR_SERVICE_NOT_RECEIVED     E_DisputeReason = "MERCHANDISE_OR_SERVICE_NOT_RECEIVED"     // 未收到商品或服务。
+	E_DISPUTE_REASON_MERCHANDISE_OR_SERVICE_NOT_AS_DESCRIBED E_DisputeReason = "MERCHANDISE_OR_SERVICE_NOT_AS_DESCRIBED" // 商品或服务与描述不符。
+	E_DISPUTE_REASON_UNAUTHORISED                            E_DisputeReason = "UNAUTHORISED"                            // 未经授权的付款。
+	E_DISPUTE_REASON_CREDIT_NOT_PROCESSED                    E_DisputeReason = "CREDIT_NOT_PROCESSED"                    // 退款未处理。
+	E_DISPUTE_REASON_DUPLICATE_TRANSACTION                   E_DisputeReason = "DUPLICATE_TRANSACTION"                   // 重复扣款。
+	E_DISPUTE_REASON_INCORRECT_AMOUNT                        E_DisputeReason = "INCORRECT_AMOUNT"                        // 扣款金额错误。
+	E_DISPUTE_REASON_PAYMENT_BY_OTHER_MEANS                  E_DisputeReason = "PAYMENT_BY_OTHER_MEANS"                  // 已通过其他方式付款。
+	E_DISPUTE_REASON_CANCELED_RECURRING_BILLING              E_DisputeReason = "CANCELED_RECURRING_BILLING"              // 已取消的订阅仍被扣款。
+	E_DISPUTE_REASON_PROBLEM_WITH_REMITTANCE                 E_DisputeReason = "PROBLEM_WITH_REMITTANCE"
+	E_DISPUTE_REASON_OTHER                                   E_DisputeReason = "OTHER"
+)
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-dispute_lifecycle_stage
+type E_DisputeLifeCycleStage string
+
+const (
+	E_DISPUTE_LIFE_CYCLE_STAGE_INQUIRY         E_DisputeLifeCycleStage = "INQUIRY"         // 买卖双方协商阶段。
+	E_DISPUTE_LIFE_CYCLE_STAGE_CHARGEBACK      E_DisputeLifeCycleStage = "CHARGEBACK"      // 信用卡拒付。
+	E_DISPUTE_LIFE_CYCLE_STAGE_PRE_ARBITRATION E_DisputeLifeCycleStage = "PRE_ARBITRATION" // 预仲裁。
+	E_DISPUTE_LIFE_CYCLE_STAGE_ARBITRATION     E_DisputeLifeCycleStage = "ARBITRATION"     // 仲裁。
+)
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-evidence_type
+type E_EvidenceType string
+
+const (
+	E_EVIDENCE_TYPE_PROOF_OF_FULFILLMENT           E_EvidenceType = "PROOF_OF_FULFILLMENT"           // 发货证明, 需附 tracking_info。
+	E_EVIDENCE_TYPE_PROOF_OF_REFUND                E_EvidenceType = "PROOF_OF_REFUND"                // 退款证明, 需附 refund_ids。
+	E_EVIDENCE_TYPE_PROOF_OF_DELIVERY_SIGNATURE    E_EvidenceType = "PROOF_OF_DELIVERY_SIGNATURE"    // 签收证明。
+	E_EVIDENCE_TYPE_PROOF_OF_RECEIPT_COPY          E_EvidenceType = "PROOF_OF_RECEIPT_COPY"          // 收据副本。
+	E_EVIDENCE_TYPE_RETURN_POLICY                  E_EvidenceType = "RETURN_POLICY"                  // 退货政策。
+	E_EVIDENCE_TYPE_BILLING_AGREEMENT              E_EvidenceType = "BILLING_AGREEMENT"              // 扣款协议, 用于订阅类争议。
+	E_EVIDENCE_TYPE_PROOF_OF_RESHIPMENT            E_EvidenceType = "PROOF_OF_RESHIPMENT"            // 补发证明。
+	E_EVIDENCE_TYPE_ITEM_DESCRIPTION               E_EvidenceType = "ITEM_DESCRIPTION"               // 商品描述。
+	E_EVIDENCE_TYPE_PAID_WITH_OTHER_METHOD         E_EvidenceType = "PAID_WITH_OTHER_METHOD"         // 已通过其他方式付款。
+	E_EVIDENCE_TYPE_COPY_OF_CONTRACT               E_EvidenceType = "COPY_OF_CONTRACT"               // 合同副本。
+	E_EVIDENCE_TYPE_PROOF_OF_REFUND_OUTSIDE_PAYPAL E_EvidenceType = "PROOF_OF_REFUND_OUTSIDE_PAYPAL" // PayPal 以外的退款证明。
+	E_EVIDENCE_TYPE_OTHER                          E_EvidenceType = "OTHER"
+)
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-transaction_info
+type DisputedTransaction struct {
+	BuyerTransactionID  string `json:"buyer_transaction_id,omitempty"`
+	SellerTransactionID string `json:"seller_transaction_id,omitempty"` // 对应 Sale.Id 或 Capture.ID
+	CreateTime          string `json:"create_time,omitempty"`
+	TransactionStatus   string `json:"transaction_status,omitempty"`
+	GrossAmount         *Money `json:"gross_amount,omitempty"`
+	InvoiceNumber       string `json:"invoice_number,omitempty"`
+	Custom              string `json:"custom,omitempty"`
+	Buyer               *struct {
+		Name string `json:"name,omitempty"`
+	} `json:"buyer,omitempty"`
+	Seller *struct {
+		Email      string `json:"email,omitempty"`
+		MerchantID string `json:"merchant_id,omitempty"`
+		Name       string `json:"name,omitempty"`
+	} `json:"seller,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-dispute_outcome
+type DisputeOutcome struct {
+	OutcomeCode    string `json:"outcome_code,omitempty"` // RESOLVED_BUYER_FAVOUR, RESOLVED_SELLER_FAVOUR, RESOLVED_WITH_PAYOUT, CANCELED_BY_BUYER, ACCEPTED, DENIED, NONE
+	AmountRefunded *Money `json:"amount_refunded,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-message
+type DisputeMessage struct {
+	PostedBy   string `json:"posted_by,omitempty"` // BUYER, SELLER, ARBITER
+	TimePosted string `json:"time_posted,omitempty"`
+	Content    string `json:"content,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-offer
+type DisputeOffer struct {
+	BuyerRequestedAmount *Money `json:"buyer_requested_amount,omitempty"`
+	SellerOfferedAmount  *Money `json:"seller_offered_amount,omitempty"`
+	OfferType            string `json:"offer_type,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-tracking_info
+type DisputeTrackingInfo struct {
+	CarrierName      string `json:"carrier_name"`
+	CarrierNameOther string `json:"carrier_name_other,omitempty"` // carrier_name 为 OTHER 时必填。
+	TrackingUrl      string `json:"tracking_url,omitempty"`
+	TrackingNumber   string `json:"tracking_number"`
+}
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-document
+type DisputeDocument struct {
+	Name string `json:"name,omitempty"`
+	Url  string `json:"url,omitempty"` // 只读
+}
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-evidence_info
+type EvidenceInfo struct {
+	TrackingInfo []*DisputeTrackingInfo `json:"tracking_info,omitempty"`
+	RefundIDs    []string               `json:"refund_ids,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-evidence
+type Evidence struct {
+	EvidenceType E_EvidenceType     `json:"evidence_type"`
+	EvidenceInfo *EvidenceInfo      `json:"evidence_info,omitempty"`
+	Documents    []*DisputeDocument `json:"documents,omitempty"` // 只读
+	Notes        string             `json:"notes,omitempty"`     // len<=2000
+	ItemID       string             `json:"item_id,omitempty"`
+	DateProvided string             `json:"date,omitempty"` // 只读
+}
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-dispute
+type Dispute struct {
+	DisputeID             string                  `json:"dispute_id,omitempty"`
+	CreateTime            string                  `json:"create_time,omitempty"`
+	UpdateTime            string                  `json:"update_time,omitempty"`
+	DisputedTransactions  []*DisputedTransaction  `json:"disputed_transactions,omitempty"`
+	Reason                E_DisputeReason         `json:"reason,omitempty"`
+	Status                E_DisputeStatus         `json:"status,omitempty"`
+	DisputeAmount         *Money                  `json:"dispute_amount,omitempty"`
+	DisputeOutcome        *DisputeOutcome         `json:"dispute_outcome,omitempty"`
+	DisputeLifeCycleStage E_DisputeLifeCycleStage `json:"dispute_life_cycle_stage,omitempty"`
+	DisputeChannel        string                  `json:"dispute_channel,omitempty"` // INTERNAL, EXTERNAL
+	Messages              []*DisputeMessage       `json:"messages,omitempty"`
+	SellerResponseDueDate string                  `json:"seller_response_due_date,omitempty"`
+	BuyerResponseDueDate  string                  `json:"buyer_response_due_date,omitempty"`
+	Offer                 *DisputeOffer           `json:"offer,omitempty"`
+	Evidences             []*Evidence             `json:"evidences,omitempty"`
+	Links                 []*LinkDescription      `json:"links,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes_list
+type ListDisputesReq struct {
+	StartTime             string // 创建时间下限, eg: 2020-03-01T00:00:00.000Z, 与 disputed_transaction_id 互斥。
+	DisputedTransactionID string // 按交易 ID 过滤
+	PageSize              int    // [1, 50] Default: 10.
+	NextPageToken         string // 上一页返回的 next 链接中的 next_page_token
+	DisputeState          string // REQUIRED_ACTION, REQUIRED_OTHER_PARTY_ACTION, UNDER_PAYPAL_REVIEW, RESOLVED, OPEN_INQUIRIES, APPEALABLE, 多个用逗号分隔
+	UpdateTimeBefore      string
+	UpdateTimeAfter       string
+}
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-dispute_search
+type DisputeList struct {
+	Items []*Dispute         `json:"items,omitempty"`
+	Links []*LinkDescription `json:"links,omitempty"`
+}
+
+// 争议操作(accept-claim、make-offer、provide-evidence 等)的返回, 只包含后续可执行操作的链接。
+type DisputeSubsequentAction struct {
+	Links []*LinkDescription `json:"links,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes-actions_accept-claim
+type AcceptDisputeClaimReq struct {
+	Note                  string                         `json:"note"`                          // len<=2000
+	AcceptClaimReason     string                         `json:"accept_claim_reason,omitempty"` // DID_NOT_SHIP_ITEM, TOO_TIME_CONSUMING, LOST_IN_MAIL, NOT_ABLE_TO_WIN, COMPANY_POLICY, REASON_NOT_SET
+	InvoiceID             string                         `json:"invoice_id,omitempty"`
+	ReturnShippingAddress *ShippingDetailAddressPortable `json:"return_shipping_address,omitempty"` // 需要买家退货时的退货地址。
+	AcceptClaimType       string                         `json:"accept_claim_type,omitempty"`       // REFUND, REFUND_WITH_RETURN, PARTIAL_REFUND, REFUND_WITH_RETURN_SHIPMENT_LABEL
+	RefundAmount          *Money                         `json:"refund_amount,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes-actions_make-offer
+type MakeDisputeOfferReq struct {
+	Note                  string                         `json:"note"` // len<=2000
+	OfferAmount           *Money                         `json:"offer_amount,omitempty"`
+	ReturnShippingAddress *ShippingDetailAddressPortable `json:"return_shipping_address,omitempty"`
+	InvoiceID             string                         `json:"invoice_id,omitempty"`
+	OfferType             string                         `json:"offer_type"` // REFUND, REFUND_WITH_RETURN, REFUND_WITH_REPLACEMENT, REPLACEMENT_WITHOUT_REFUND
+}
+
+// 上传的证据文件, 支持 JPG, GIF, PNG, PDF, 单个文件不超过 10MB, 总计不超过 50MB。
+type EvidenceFile struct {
+	FileName    string
+	ContentType string // eg: application/pdf, image/png
+	Content     io.Reader
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/customer/disputes?start_time=2020-03-01T00:00:00.000Z&page_size=10
+// List disputes
+// 分页查询争议, 下一页的 next_page_token 从返回的 rel=next 链接中获取。
+*/
+
+func (c *Client) ListDisputes(q *ListDisputesReq) (*DisputeList, error) {
+	v := url.Values{}
+	if q != nil {
+		if q.StartTime != "" {
+			v.Set("start_time", q.StartTime)
+		}
+		if q.DisputedTransactionID != "" {
+			v.Set("disputed_transaction_id", q.DisputedTransactionID)
+		}
+		if q.PageSize > 0 {
+			v.Set("page_size", fmt.Sprint(q.PageSize))
+		}
+		if q.NextPageToken != "" {
+			v.Set("next_page_token", q.NextPageToken)
+		}
+		if q.DisputeState != "" {
+			v.Set("dispute_state", q.DisputeState)
+		}
+		if q.UpdateTimeBefore != "" {
+			v.Set("update_time_before", q.UpdateTimeBefore)
+		}
+		if q.UpdateTimeAfter != "" {
+			v.Set("update_time_after", q.UpdateTimeAfter)
+		}
+	}
+	endpoint := fmt.Sprintf("%s%s", c.APIBase, K_DISPUTE_API)
+	if len(v) > 0 {
+		endpoint = fmt.Sprintf("%s?%s", endpoint, v.Encode())
+	}
+	req, err := c.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &DisputeList{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/customer/disputes/PP-D-27803
+// Show dispute details
+// 查询争议详情
+*/
+
+func (c *Client) ShowDisputeDetails(disputeID string) (*Dispute, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.APIBase, K_DISPUTE_API, disputeID), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &Dispute{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/customer/disputes/PP-D-27803/accept-claim
+// Accept claim
+// 触发webhook： CUSTOMER.DISPUTE.RESOLVED
+// 接受买家的索赔, 争议以买家胜诉结束, 款项退回买家。
+*/
+
+func (c *Client) AcceptDisputeClaim(disputeID string, q *AcceptDisputeClaimReq) (*DisputeSubsequentAction, error) {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/accept-claim", c.APIBase, K_DISPUTE_API, disputeID), q)
+	rsp := &DisputeSubsequentAction{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/customer/disputes/PP-D-27803/make-offer
+// Make offer to resolve dispute
+// 触发webhook： CUSTOMER.DISPUTE.UPDATED
+// 向买家提出和解方案, 只能在 INQUIRY 阶段进行。
+*/
+
+func (c *Client) MakeDisputeOffer(disputeID string, q *MakeDisputeOfferReq) (*DisputeSubsequentAction, error) {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/make-offer", c.APIBase, K_DISPUTE_API, disputeID), q)
+	rsp := &DisputeSubsequentAction{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/customer/disputes/PP-D-27803/provide-evidence
+// Content-Type: multipart/related
+// Provide evidence
+// 触发webhook： CUSTOMER.DISPUTE.UPDATED
+// 提交证据, 只能在状态为 WAITING_FOR_SELLER_RESPONSE 时进行。
+*/
+
+func (c *Client) ProvideDisputeEvidence(disputeID string, evidences []*Evidence, files []*EvidenceFile) (*DisputeSubsequentAction, error) {
+	input := map[string]interface{}{"evidences": evidences}
+	req, err := c.newMultipartRequest("POST", fmt.Sprintf("%s%s/%s/provide-evidence", c.APIBase, K_DISPUTE_API, disputeID), input, files)
+	rsp := &DisputeSubsequentAction{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/customer/disputes/PP-D-27803/appeal
+// Content-Type: multipart/related
+// Appeal dispute
+// 对已判决的争议提出申诉, 只能在 dispute_state 为 APPEALABLE 时进行。
+*/
+
+func (c *Client) AppealDispute(disputeID string, evidences []*Evidence, files []*EvidenceFile) (*DisputeSubsequentAction, error) {
+	input := map[string]interface{}{"evidences": evidences}
+	req, err := c.newMultipartRequest("POST", fmt.Sprintf("%s%s/%s/appeal", c.APIBase, K_DISPUTE_API, disputeID), input, files)
+	rsp := &DisputeSubsequentAction{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/customer/disputes/PP-D-27803/send-message
+// Send message about dispute to other party
+// 给买家发送消息
+*/
+
+func (c *Client) SendDisputeMessage(disputeID, message string) (*DisputeSubsequentAction, error) {
+	q := map[string]string{"message": message} // len<=2000
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/send-message", c.APIBase, K_DISPUTE_API, disputeID), q)
+	rsp := &DisputeSubsequentAction{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/customer/disputes/PP-D-27803/escalate
+// Escalate dispute to claim
+// 将争议升级为索赔, 交由 PayPal 裁决。
+*/
+
+func (c *Client) EscalateDispute(disputeID, note string) (*DisputeSubsequentAction, error) {
+	q := map[string]string{"note": note} // len<=2000
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/escalate", c.APIBase, K_DISPUTE_API, disputeID), q)
+	rsp := &DisputeSubsequentAction{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+// newMultipartRequest 构造 multipart/related 请求, 第一个 part 为 JSON 格式的 input, 其后为证据文件。
+func (c *Client) newMultipartRequest(method, url string, input interface{}, files []*EvidenceFile) (*http.Request, error) {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", `form-data; name="input"; filename="input.json"`)
+	h.Set("Content-Type", "application/json")
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.NewEncoder(part).Encode(input); err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		h := textproto.MIMEHeader{}
+		// 文件名可能包含引号、反斜杠或非 ASCII 字符, 由 mime 转义
+		h.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{"name": "evidence_file", "filename": f.FileName}))
+		if f.ContentType != "" {
+			h.Set("Content-Type", f.ContentType)
+		}
+		part, err := w.CreatePart(h)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.Copy(part, f.Content); err != nil {
+			return nil, err
+		}
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(c.context(), method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "multipart/related; boundary="+w.Boundary())
+	return req, nil
+}
@@ -0,0 +1,45 @@
+package paypalsdk
+
+import (
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+)
+
+func TestEvidenceFileName(t *testing.T) {
+	c, err := NewClient("id", "secret", WithSandbox())
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{`receipt.pdf`, `my "receipt".pdf`, `C:\docs\receipt.pdf`, `收据 2026.pdf`}
+	var files []*EvidenceFile
+	for _, name := range names {
+		files = append(files, &EvidenceFile{FileName: name, ContentType: "application/pdf", Content: strings.NewReader(name)})
+	}
+	req, err := c.newMultipartRequest("POST", c.APIBase+K_DISPUTE_API+"/PP-D-1/provide-evidence", map[string]string{"notes": "n"}, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := multipart.NewReader(req.Body, params["boundary"])
+	if part, err := r.NextPart(); err != nil || part.FormName() != "input" {
+		t.Fatalf("first part = %v, %v, want input", part, err)
+	}
+	for _, name := range names {
+		part, err := r.NextPart()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if part.FormName() != "evidence_file" || part.FileName() != name {
+			t.Errorf("part %q: name = %q, filename = %q", name, part.FormName(), part.FileName())
+		}
+		if data, _ := ioutil.ReadAll(part); string(data) != name {
+			t.Errorf("part %q: content = %q", name, data)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package paypalsdk
+
+import "time"
+
+/*
+订阅权益：
+根据订阅状态和 billing_info 判断订阅者当前是否可以使用服务, 业务代码不必自己解析 next_billing_time、failed_payments_count。
+- ACTIVE 且没有扣款失败: 已付款或在试用期内, 有权益。
+- ACTIVE 或因扣款失败 SUSPENDED: PayPal 在重试扣款, 在宽限期内(错过扣款日后 GracePeriod 内)有权益。
+- 手动 SUSPENDED、APPROVAL_PENDING、APPROVED: 没有权益。
+- CANCELLED、EXPIRED: 已付款的周期结束前有权益。
+
+注意: 取消、过期后 PayPal 不再返回 next_billing_time, PaidThrough 为零值。
+IsEntitled 需要传入订阅的 plan, 此时根据 last_payment 和 plan 的计费频率推算已付款周期的结束时间(PaidThroughFor);
+plan 为 nil 时不读 last_payment, 取消后立即没有权益。
+也可以在订阅仍为 ACTIVE 时保存 PaidThrough, 之后调用 IsEntitledThrough, 不必查询 plan。
+*/
+
+// DefaultGracePeriod 是错过扣款日后仍保留权益的时间, PayPal 默认在 5 天后重试扣款
+const DefaultGracePeriod = 7 * 24 * time.Hour
+
+// EntitlementPolicy decides whether a subscriber is entitled to the service
+type EntitlementPolicy struct {
+	// GracePeriod is how long a subscriber keeps access after a failed payment, 0 means no grace
+	GracePeriod time.Duration
+	// EntitleApproved grants access to APPROVED subscriptions, which the buyer approved but the merchant has not activated yet
+	EntitleApproved bool
+}
+
+// DefaultEntitlementPolicy is used by the entitlement methods of Subscription
+var DefaultEntitlementPolicy = EntitlementPolicy{GracePeriod: DefaultGracePeriod}
+
+// IsEntitled reports whether the subscriber of s has access at now. plan must be the plan of s:
+// cancelled and expired subscriptions are entitled until s.PaidThroughFor(plan), computed from last_payment
+// and the billing frequency of plan when PayPal no longer returns next_billing_time.
+// With a nil plan last_payment is not read, and a cancelled subscription usually loses access at once
+func (p EntitlementPolicy) IsEntitled(s *Subscription, now time.Time, plan *Plan) bool {
+	return p.IsEntitledThrough(s, s.PaidThroughFor(plan), now)
+}
+
+// IsEntitledThrough is IsEntitled, except that cancelled and expired subscriptions are entitled until paidThrough,
+// the end of the period paid for as stored by the caller. A zero paidThrough falls back to s.PaidThrough()
+func (p EntitlementPolicy) IsEntitledThrough(s *Subscription, paidThrough time.Time, now time.Time) bool {
+	switch s.Status {
+	case E_SUBSCRIPTION_STATUS_ACTIVE:
+		return s.failedPayments() == 0 || p.InGracePeriod(s, now)
+	case E_SUBSCRIPTION_STATUS_SUSPENDED:
+		return p.InGracePeriod(s, now)
+	case E_SUBSCRIPTION_STATUS_APPROVAL:
+		return p.EntitleApproved
+	case E_SUBSCRIPTION_STATUS_CANCELLED, E_SUBSCRIPTION_STATUS_EXPIRED:
+		if paidThrough.IsZero() {
+			paidThrough = s.PaidThrough()
+		}
+		return now.Before(paidThrough)
+	}
+	return false
+}
+
+// InGracePeriod reports whether payments of s are failing and now is within the grace period after the missed payment
+func (p EntitlementPolicy) InGracePeriod(s *Subscription, now time.Time) bool {
+	if s.Status != E_SUBSCRIPTION_STATUS_ACTIVE && s.Status != E_SUBSCRIPTION_STATUS_SUSPENDED {
+		return false
+	}
+	if s.failedPayments() == 0 {
+		return false
+	}
+	paidThrough := s.PaidThrough()
+	return !paidThrough.IsZero() && now.Before(paidThrough.Add(p.GracePeriod))
+}
+
+// IsEntitled reports whether the subscriber has access at now, according to DefaultEntitlementPolicy.
+// plan must be the plan of s, it is needed to keep access of cancelled subscriptions through the period paid for,
+// see EntitlementPolicy.IsEntitled
+func (s *Subscription) IsEntitled(now time.Time, plan *Plan) bool {
+	return DefaultEntitlementPolicy.IsEntitled(s, now, plan)
+}
+
+// InGracePeriod reports whether payments are failing but the subscriber keeps access, according to DefaultEntitlementPolicy
+func (s *Subscription) InGracePeriod(now time.Time) bool {
+	return DefaultEntitlementPolicy.InGracePeriod(s, now)
+}
+
+// PaidThrough returns the end of the period paid for, or of the free trial:
+// the next billing time, or the time of the missed payment if payments are failing.
+// It is zero if unknown, eg: PayPal drops next_billing_time when a subscription is cancelled.
+// It does not read last_payment, see PaidThroughFor
+func (s *Subscription) PaidThrough() time.Time {
+	if s.BillingInfo == nil {
+		return time.Time{}
+	}
+	if s.failedPayments() > 0 && s.BillingInfo.LastFailedPayment != nil {
+		if t, err := time.Parse(time.RFC3339, s.BillingInfo.LastFailedPayment.Time); err == nil {
+			return t
+		}
+	}
+	return s.BillingInfo.NextBillingTime
+}
+
+// PaidThroughFor is PaidThrough, except that when next_billing_time is missing, eg: after cancellation,
+// the end of the period paid for is computed from last_payment and the frequency of the billing cycle of plan it paid for.
+// plan must be the plan of s, it is zero if there is no last payment
+func (s *Subscription) PaidThroughFor(plan *Plan) time.Time {
+	if t := s.PaidThrough(); !t.IsZero() || plan == nil {
+		return t
+	}
+	if s.BillingInfo == nil || s.BillingInfo.LastPayment == nil {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, s.BillingInfo.LastPayment.Time)
+	if err != nil {
+		return time.Time{}
+	}
+	cycle := s.lastBilledCycle(plan)
+	if cycle == nil || cycle.Frequency == nil {
+		return time.Time{}
+	}
+	return cycle.Frequency.Next(t)
+}
+
+// lastBilledCycle returns the billing cycle of plan the last payment was for: the last cycle with completed cycles,
+// or the last regular cycle if cycle_executions is missing
+func (s *Subscription) lastBilledCycle(plan *Plan) *BillingCycle {
+	sequence := 0
+	for _, e := range s.BillingInfo.CycleExecutions {
+		if e.CyclesCompleted > 0 && e.Sequence > sequence {
+			sequence = e.Sequence
+		}
+	}
+	var last *BillingCycle
+	for _, c := range plan.BillingCycles {
+		if sequence > 0 && c.Sequence == sequence {
+			return c
+		}
+		if c.TenureType == E_TENURE_TYPE_REGULAR {
+			last = c
+		}
+	}
+	return last
+}
+
+// Next returns the time a billing cycle of frequency f started at from ends
+func (f *Frequency) Next(from time.Time) time.Time {
+	n := f.IntervalCount
+	if n <= 0 {
+		n = 1
+	}
+	switch f.IntervalUnit {
+	case E_FREQUENCY_INTERVAL_DAY:
+		return from.AddDate(0, 0, n)
+	case E_FREQUENCY_INTERVAL_WEEK:
+		return from.AddDate(0, 0, 7*n)
+	case E_FREQUENCY_INTERVAL_YEAR:
+		return from.AddDate(n, 0, 0)
+	}
+	return from.AddDate(0, n, 0)
+}
+
+func (s *Subscription) failedPayments() int {
+	if s.BillingInfo == nil {
+		return 0
+	}
+	return s.BillingInfo.FailedPaymentsCount
+}
@@ -0,0 +1,149 @@
+package paypalsdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEntitlement(t *testing.T) {
+	due := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	grace := 7 * 24 * time.Hour
+	paying := &BillingInfo{NextBillingTime: due}
+	failing := &BillingInfo{
+		NextBillingTime:     due.AddDate(0, 0, 5),
+		FailedPaymentsCount: 1,
+		LastFailedPayment:   &FailedPaymentDetails{Time: due.Format(time.RFC3339)},
+	}
+
+	tests := []struct {
+		name        string
+		status      E_SubscriptionStatus
+		billing     *BillingInfo
+		policy      EntitlementPolicy
+		now         time.Time
+		want        bool
+		wantGrace   bool
+		paidThrough time.Time
+	}{
+		{"active", E_SUBSCRIPTION_STATUS_ACTIVE, paying, DefaultEntitlementPolicy, due.Add(-time.Hour), true, false, due},
+		{"active past next billing", E_SUBSCRIPTION_STATUS_ACTIVE, paying, DefaultEntitlementPolicy, due.Add(time.Hour), true, false, due},
+		{"active without billing info", E_SUBSCRIPTION_STATUS_ACTIVE, nil, DefaultEntitlementPolicy, due, true, false, time.Time{}},
+		{"active failing in grace", E_SUBSCRIPTION_STATUS_ACTIVE, failing, DefaultEntitlementPolicy, due.Add(grace - time.Second), true, true, due},
+		{"active failing at grace end", E_SUBSCRIPTION_STATUS_ACTIVE, failing, DefaultEntitlementPolicy, due.Add(grace), false, false, due},
+		{"active failing no grace", E_SUBSCRIPTION_STATUS_ACTIVE, failing, EntitlementPolicy{}, due, false, false, due},
+		{"active failing before due", E_SUBSCRIPTION_STATUS_ACTIVE, failing, EntitlementPolicy{}, due.Add(-time.Second), true, true, due},
+		{"suspended failing in grace", E_SUBSCRIPTION_STATUS_SUSPENDED, failing, DefaultEntitlementPolicy, due.Add(time.Hour), true, true, due},
+		{"suspended failing after grace", E_SUBSCRIPTION_STATUS_SUSPENDED, failing, DefaultEntitlementPolicy, due.Add(grace + time.Hour), false, false, due},
+		{"suspended by merchant", E_SUBSCRIPTION_STATUS_SUSPENDED, paying, DefaultEntitlementPolicy, due.Add(-time.Hour), false, false, due},
+		{"approval pending", E_SUBSCRIPTION_STATUS_APPROVAL_PENDING, nil, DefaultEntitlementPolicy, due, false, false, time.Time{}},
+		{"approved", E_SUBSCRIPTION_STATUS_APPROVAL, nil, DefaultEntitlementPolicy, due, false, false, time.Time{}},
+		{"approved entitled", E_SUBSCRIPTION_STATUS_APPROVAL, nil, EntitlementPolicy{EntitleApproved: true}, due, true, false, time.Time{}},
+		{"cancelled before paid through", E_SUBSCRIPTION_STATUS_CANCELLED, paying, DefaultEntitlementPolicy, due.Add(-time.Second), true, false, due},
+		{"cancelled at paid through", E_SUBSCRIPTION_STATUS_CANCELLED, paying, DefaultEntitlementPolicy, due, false, false, due},
+		{"cancelled without next billing", E_SUBSCRIPTION_STATUS_CANCELLED, &BillingInfo{}, DefaultEntitlementPolicy, due, false, false, time.Time{}},
+		{"cancelled failing", E_SUBSCRIPTION_STATUS_CANCELLED, failing, DefaultEntitlementPolicy, due.Add(time.Hour), false, false, due},
+		{"expired before paid through", E_SUBSCRIPTION_STATUS_EXPIRED, paying, DefaultEntitlementPolicy, due.Add(-time.Second), true, false, due},
+		{"expired", E_SUBSCRIPTION_STATUS_EXPIRED, nil, DefaultEntitlementPolicy, due, false, false, time.Time{}},
+	}
+	for _, tt := range tests {
+		s := &Subscription{Status: tt.status, BillingInfo: tt.billing}
+		if got := tt.policy.IsEntitled(s, tt.now, nil); got != tt.want {
+			t.Errorf("%s: IsEntitled = %v, want %v", tt.name, got, tt.want)
+		}
+		if got := tt.policy.InGracePeriod(s, tt.now); got != tt.wantGrace {
+			t.Errorf("%s: InGracePeriod = %v, want %v", tt.name, got, tt.wantGrace)
+		}
+		if got := s.PaidThrough(); !got.Equal(tt.paidThrough) {
+			t.Errorf("%s: PaidThrough = %v, want %v", tt.name, got, tt.paidThrough)
+		}
+	}
+}
+
+func TestIsEntitledThrough(t *testing.T) {
+	stored := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		status      E_SubscriptionStatus
+		paidThrough time.Time
+		now         time.Time
+		want        bool
+	}{
+		{E_SUBSCRIPTION_STATUS_CANCELLED, stored, stored.Add(-time.Second), true},
+		{E_SUBSCRIPTION_STATUS_CANCELLED, stored, stored, false},
+		{E_SUBSCRIPTION_STATUS_CANCELLED, time.Time{}, stored.Add(-time.Second), false},
+		{E_SUBSCRIPTION_STATUS_EXPIRED, stored, stored.Add(-time.Hour), true},
+		// 只有取消、过期的订阅使用保存的时间
+		{E_SUBSCRIPTION_STATUS_SUSPENDED, stored, stored.Add(-time.Hour), false},
+		{E_SUBSCRIPTION_STATUS_APPROVAL_PENDING, stored, stored.Add(-time.Hour), false},
+	}
+	for _, tt := range tests {
+		s := &Subscription{Status: tt.status, BillingInfo: &BillingInfo{}}
+		if got := DefaultEntitlementPolicy.IsEntitledThrough(s, tt.paidThrough, tt.now); got != tt.want {
+			t.Errorf("IsEntitledThrough(%s, %v, %v) = %v, want %v", tt.status, tt.paidThrough, tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestPaidThroughFor(t *testing.T) {
+	paid := time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC)
+	plan := &Plan{BillingCycles: []*BillingCycle{
+		{TenureType: E_TENURE_TYPE_TRIAL, Sequence: 1, Frequency: &Frequency{IntervalUnit: E_FREQUENCY_INTERVAL_WEEK, IntervalCount: 2}},
+		{TenureType: E_TENURE_TYPE_REGULAR, Sequence: 2, Frequency: &Frequency{IntervalUnit: E_FREQUENCY_INTERVAL_MONTH}},
+	}}
+	lastPayment := &LastPaymentDetails{Time: paid.Format(time.RFC3339)}
+	executions := func(trial, regular int) []*CycleExecutions {
+		return []*CycleExecutions{
+			{TenureType: E_TENURE_TYPE_TRIAL, Sequence: 1, CyclesCompleted: trial},
+			{TenureType: E_TENURE_TYPE_REGULAR, Sequence: 2, CyclesCompleted: regular},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		billing *BillingInfo
+		plan    *Plan
+		want    time.Time
+	}{
+		{"next billing time", &BillingInfo{NextBillingTime: paid, LastPayment: lastPayment}, plan, paid},
+		{"no plan", &BillingInfo{LastPayment: lastPayment}, nil, time.Time{}},
+		{"no billing info", nil, plan, time.Time{}},
+		{"no last payment", &BillingInfo{CycleExecutions: executions(1, 1)}, plan, time.Time{}},
+		{"bad last payment time", &BillingInfo{LastPayment: &LastPaymentDetails{Time: "yesterday"}}, plan, time.Time{}},
+		{"regular cycle", &BillingInfo{LastPayment: lastPayment, CycleExecutions: executions(1, 3)}, plan, paid.AddDate(0, 1, 0)},
+		{"trial cycle", &BillingInfo{LastPayment: lastPayment, CycleExecutions: executions(1, 0)}, plan, paid.AddDate(0, 0, 14)},
+		{"without cycle executions", &BillingInfo{LastPayment: lastPayment}, plan, paid.AddDate(0, 1, 0)},
+	}
+	for _, tt := range tests {
+		s := &Subscription{Status: E_SUBSCRIPTION_STATUS_CANCELLED, BillingInfo: tt.billing}
+		if got := s.PaidThroughFor(tt.plan); !got.Equal(tt.want) {
+			t.Errorf("%s: PaidThroughFor = %v, want %v", tt.name, got, tt.want)
+		}
+		// 取消后直到推算出的时间都有权益
+		if tt.want.IsZero() {
+			if s.IsEntitled(paid, tt.plan) {
+				t.Errorf("%s: IsEntitled = true, want false", tt.name)
+			}
+			continue
+		}
+		if !s.IsEntitled(tt.want.Add(-time.Second), tt.plan) || s.IsEntitled(tt.want, tt.plan) {
+			t.Errorf("%s: IsEntitled is not true until %v", tt.name, tt.want)
+		}
+	}
+}
+
+func TestFrequencyNext(t *testing.T) {
+	from := time.Date(2026, 1, 15, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		f    Frequency
+		want time.Time
+	}{
+		{Frequency{IntervalUnit: E_FREQUENCY_INTERVAL_DAY, IntervalCount: 3}, from.AddDate(0, 0, 3)},
+		{Frequency{IntervalUnit: E_FREQUENCY_INTERVAL_WEEK}, from.AddDate(0, 0, 7)},
+		{Frequency{IntervalUnit: E_FREQUENCY_INTERVAL_MONTH, IntervalCount: 6}, from.AddDate(0, 6, 0)},
+		{Frequency{IntervalUnit: E_FREQUENCY_INTERVAL_YEAR}, from.AddDate(1, 0, 0)},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%+v.Next = %v, want %v", tt.f, got, tt.want)
+		}
+	}
+}
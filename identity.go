@@ -0,0 +1,190 @@
+package paypalsdk
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	K_IDENTITY_USERINFO_API = "/v1/identity/oauth2/userinfo"
+
+	kAuthorizeURLLive    = "https://www.paypal.com/signin/authorize"
+	kAuthorizeURLSandbox = "https://www.sandbox.paypal.com/signin/authorize"
+)
+
+/*
+Log In with PayPal 流程：
+1. 将用户重定向到 AuthorizeURL 返回的地址, 用户登录并同意授权后, PayPal 带着 code 和 state 回调 redirectURI。
+2. 校验 state 后, 调用 GrantNewAccessTokenFromAuthCode 用 code 换取用户的 access_token 和 refresh_token。
+3. 调用 GetUserInfo 获取用户信息, 用 payer_id 关联本地账户。
+4. access_token 过期后, 调用 GrantNewAccessTokenFromRefreshToken 刷新。
+
+这里的 access_token 代表用户, 与 Client.Token(代表商户应用) 相互独立, 不会替换 Client.Token。
+*/
+
+// https://developer.paypal.com/docs/log-in-with-paypal/integrate/reference/#scope-attributes
+const (
+	E_IDENTITY_SCOPE_OPENID           = "openid"
+	E_IDENTITY_SCOPE_PROFILE          = "profile"
+	E_IDENTITY_SCOPE_EMAIL            = "email"
+	E_IDENTITY_SCOPE_ADDRESS          = "address"
+	E_IDENTITY_SCOPE_PHONE            = "phone"
+	E_IDENTITY_SCOPE_PAYPAL_ATTRIBUTE = "https://uri.paypal.com/services/paypalattributes" // payer_id, verified_account 等
+)
+
+// https://developer.paypal.com/docs/api/identity/v1/#definition-token_response
+type IdentityTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"` // 只在 authorization_code 授权时返回
+	IDToken      string `json:"id_token,omitempty"`      // 包含 openid scope 时返回
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"` // 秒
+	Scope        string `json:"scope,omitempty"`
+	Nonce        string `json:"nonce,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/identity/v1/#definition-address
+type UserInfoAddress struct {
+	StreetAddress string `json:"street_address,omitempty"`
+	Locality      string `json:"locality,omitempty"` // 城市
+	Region        string `json:"region,omitempty"`   // 州、省
+	PostalCode    string `json:"postal_code,omitempty"`
+	Country       string `json:"country,omitempty"` // eg: US
+}
+
+// https://developer.paypal.com/docs/api/identity/v1/#definition-email
+type UserInfoEmail struct {
+	Value     string `json:"value"`
+	Primary   bool   `json:"primary,omitempty"`
+	Confirmed bool   `json:"confirmed,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/identity/v1/#definition-user_info
+type UserInfo struct {
+	UserID          string           `json:"user_id"` // https://www.paypal.com/webapps/auth/identity/user/...
+	Sub             string           `json:"sub,omitempty"`
+	Name            string           `json:"name,omitempty"`
+	GivenName       string           `json:"given_name,omitempty"`
+	FamilyName      string           `json:"family_name,omitempty"`
+	MiddleName      string           `json:"middle_name,omitempty"`
+	PayerID         string           `json:"payer_id,omitempty"` // 需要 paypalattributes scope, 与订阅中的 Subscriber.PayerId 一致
+	Address         *UserInfoAddress `json:"address,omitempty"`
+	VerifiedAccount bool             `json:"verified_account,omitempty"`
+	Emails          []*UserInfoEmail `json:"emails,omitempty"`
+	Email           string           `json:"email,omitempty"`
+	PhoneNumber     string           `json:"phone_number,omitempty"`
+	Locale          string           `json:"locale,omitempty"`
+	Zoneinfo        string           `json:"zoneinfo,omitempty"`
+}
+
+// PrimaryEmail 返回用户的主邮箱
+func (u *UserInfo) PrimaryEmail() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return u.Email
+}
+
+// AuthorizeURL 返回 Log In with PayPal 的授权地址。
+// state 用于防止 CSRF, 回调时需校验; nonce 会原样出现在 id_token 中, 用于防止重放, 可为空。
+// APIBase 的 host 为 *.sandbox.paypal.com 时(如 api.sandbox.paypal.com、api-m.sandbox.paypal.com)使用 sandbox 的授权地址,
+// 其他情况使用正式环境。
+func (c *Client) AuthorizeURL(redirectURI string, scopes []string, state, nonce string) string {
+	v := url.Values{}
+	v.Set("flowEntry", "static")
+	v.Set("client_id", c.ClientID)
+	v.Set("response_type", "code")
+	v.Set("scope", strings.Join(scopes, " "))
+	v.Set("redirect_uri", redirectURI)
+	if state != "" {
+		v.Set("state", state)
+	}
+	if nonce != "" {
+		v.Set("nonce", nonce)
+	}
+
+	base := kAuthorizeURLLive
+	if isSandbox(c.APIBase) {
+		base = kAuthorizeURLSandbox
+	}
+	return fmt.Sprintf("%s?%s", base, v.Encode())
+}
+
+// isSandbox reports whether apiBase is on a host of the PayPal sandbox
+func isSandbox(apiBase string) bool {
+	u, err := url.Parse(apiBase)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == "sandbox.paypal.com" || strings.HasSuffix(host, ".sandbox.paypal.com")
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/oauth2/token
+// grant_type=authorization_code
+// 用回调中的 code 换取用户的 access_token 和 refresh_token, code 只能使用一次。
+*/
+
+func (c *Client) GrantNewAccessTokenFromAuthCode(code, redirectURI string) (*IdentityTokenResponse, error) {
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	if redirectURI != "" {
+		v.Set("redirect_uri", redirectURI)
+	}
+	return c.grantIdentityToken(v)
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/oauth2/token
+// grant_type=refresh_token
+// 用 refresh_token 刷新用户的 access_token
+*/
+
+func (c *Client) GrantNewAccessTokenFromRefreshToken(refreshToken string) (*IdentityTokenResponse, error) {
+	v := url.Values{}
+	v.Set("grant_type", "refresh_token")
+	v.Set("refresh_token", refreshToken)
+	return c.grantIdentityToken(v)
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/identity/oauth2/userinfo?schema=paypalv1.1
+// Show user profile information
+// 用用户的 access_token 查询用户信息, 返回的字段取决于授权的 scope。
+*/
+
+func (c *Client) GetUserInfo(accessToken string) (*UserInfo, error) {
+	req, err := http.NewRequestWithContext(c.context(), "GET", fmt.Sprintf("%s%s?schema=paypalv1.1", c.APIBase, K_IDENTITY_USERINFO_API), nil)
+	if err != nil {
+		return nil, err
+	}
+	// 使用用户的 token 而不是 Client.Token
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	rsp := &UserInfo{}
+	err = c.Send(req, rsp)
+	return rsp, err
+}
+
+func (c *Client) grantIdentityToken(v url.Values) (*IdentityTokenResponse, error) {
+	buf := bytes.NewBufferString(v.Encode())
+	req, err := http.NewRequestWithContext(c.context(), "POST", fmt.Sprintf("%s%s", c.APIBase, kGetAccessTokenAPI), buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-type", "application/x-www-form-urlencoded")
+
+	rsp := &IdentityTokenResponse{}
+	err = c.SendWithBasicAuth(req, rsp)
+	return rsp, err
+}
@@ -0,0 +1,41 @@
+package paypalsdk
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAuthorizeURL(t *testing.T) {
+	tests := []struct {
+		option  ClientOption
+		apiBase string // 不为空时直接设置 APIBase, 不经过 WithAPIBase
+		want    string
+	}{
+		{WithSandbox(), "", kAuthorizeURLSandbox + "?"},
+		{WithLive(), "", kAuthorizeURLLive + "?"},
+		{WithAPIBase("https://api-m.sandbox.paypal.com"), "", kAuthorizeURLSandbox + "?"},
+		{WithAPIBase("https://api.sandbox.paypal.com/"), "", kAuthorizeURLSandbox + "?"},
+		{WithSandbox(), "https://api.sandbox.paypal.com/", kAuthorizeURLSandbox + "?"},
+		{WithSandbox(), "https://API-M.Sandbox.PayPal.com:443", kAuthorizeURLSandbox + "?"},
+		{WithAPIBase("https://api-m.paypal.com"), "", kAuthorizeURLLive + "?"},
+		{WithAPIBase("https://api.paypal.com/"), "", kAuthorizeURLLive + "?"},
+		{WithAPIBase("https://sandbox-proxy.example.com"), "", kAuthorizeURLLive + "?"},
+		{WithAPIBase("https://api.sandbox.paypal.com.example.com"), "", kAuthorizeURLLive + "?"},
+	}
+	for _, tt := range tests {
+		c, err := NewClient("id", "secret", tt.option)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.apiBase != "" {
+			c.APIBase = tt.apiBase
+		}
+		got := c.AuthorizeURL("https://example.com/callback", []string{"openid", "email"}, "s", "")
+		if !strings.HasPrefix(got, tt.want) {
+			t.Errorf("AuthorizeURL with APIBase %s = %s, want prefix %s", c.APIBase, got, tt.want)
+		}
+		if !strings.Contains(got, "scope=openid+email") || !strings.Contains(got, "state=s") || strings.Contains(got, "nonce=") {
+			t.Errorf("AuthorizeURL query = %s", got)
+		}
+	}
+}
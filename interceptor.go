@@ -0,0 +1,93 @@
+package paypalsdk
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+/*
+拦截器：
+Send 发出的每个请求都经过拦截器链, 可以在不修改 Send 的情况下添加请求头、统计、熔断、签名或故障注入。
+调用顺序(外层先执行)：
+
+	RetryInterceptor -> Client.Interceptors[0] -> ... -> Client.Interceptors[n-1] -> LoggingInterceptor -> http.Client.Do
+
+用户的拦截器位于重试之内, 每次重试都会再次经过。
+*/
+
+// RoundTripFunc sends a request and returns its response, like http.RoundTripper
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Interceptor wraps next, eg:
+//
+//	func(next paypalsdk.RoundTripFunc) paypalsdk.RoundTripFunc {
+//		return func(req *http.Request) (*http.Response, error) {
+//			req.Header.Set("X-Trace-Id", traceID)
+//			return next(req)
+//		}
+//	}
+type Interceptor func(next RoundTripFunc) RoundTripFunc
+
+// WithInterceptors appends interceptors to the chain, see Client.Use
+func WithInterceptors(interceptors ...Interceptor) ClientOption {
+	return func(c *Client) {
+		c.Use(interceptors...)
+	}
+}
+
+// Use appends interceptors to the chain, the first one is the outermost
+func (c *Client) Use(interceptors ...Interceptor) {
+	// 不与 OnBehalfOf 得到的副本共享底层数组
+	c.Interceptors = append(c.Interceptors[:len(c.Interceptors):len(c.Interceptors)], interceptors...)
+}
+
+// roundTrip builds the interceptor chain around c.Client.Do
+func (c *Client) roundTrip() RoundTripFunc {
+	rt := RoundTripFunc(c.Client.Do)
+	rt = LoggingInterceptor(c.logger())(rt)
+	for i := len(c.Interceptors) - 1; i >= 0; i-- {
+		rt = c.Interceptors[i](rt)
+	}
+	if c.Retry != nil {
+		rt = RetryInterceptor(c.Retry)(rt)
+	}
+	return rt
+}
+
+// LoggingInterceptor logs every request and its response to l, except for the access token call
+func LoggingInterceptor(l Logger) Interceptor {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			rsp, err := next(req)
+			if err != nil || req.URL.Path == kGetAccessTokenAPI {
+				return rsp, err
+			}
+
+			data, err := ioutil.ReadAll(rsp.Body)
+			rsp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			rsp.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+			var buf = &bytes.Buffer{}
+			buf.WriteString("\n=========== Begin ============")
+			buf.WriteString("\n【请求信息】")
+			buf.WriteString(fmt.Sprintf("\n%s %d %s", req.Method, rsp.StatusCode, req.URL.String()))
+			for key := range req.Header {
+				buf.WriteString(fmt.Sprintf("\n%s: %s", key, req.Header.Get(key)))
+			}
+			buf.WriteString("\n【返回信息】")
+			for key := range rsp.Header {
+				buf.WriteString(fmt.Sprintf("\n%s: %s", key, rsp.Header.Get(key)))
+			}
+			buf.WriteString(fmt.Sprintf("\n%s", string(data)))
+			buf.WriteString("\n===========  End  ============")
+
+			l.Println(buf.String())
+			return rsp, nil
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package paypalsdk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// recorder is a Logger appending to the trace of an interceptor test
+type recorder struct {
+	trace *[]string
+}
+
+func (r recorder) Println(v ...interface{}) {
+	*r.trace = append(*r.trace, "log")
+}
+
+func tracing(trace *[]string, name string) Interceptor {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			*trace = append(*trace, name+">")
+			rsp, err := next(req)
+			*trace = append(*trace, "<"+name)
+			return rsp, err
+		}
+	}
+}
+
+func TestInterceptorOrder(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var trace []string
+	c, err := NewClient("id", "secret",
+		WithAPIBase(srv.URL),
+		WithLogger(recorder{&trace}),
+		WithRetry(1, 0),
+		WithInterceptors(tracing(&trace, "a")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Use(tracing(&trace, "b"))
+
+	req, err := c.NewRequest("GET", srv.URL+"/v1/billing/plans", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Send(req, nil); err != nil {
+		t.Fatal(err)
+	}
+	// 重试在最外层, 每次重试都经过用户的拦截器; 日志在最内层
+	want := strings.Fields("a> b> log <b <a a> b> log <b <a")
+	if !reflect.DeepEqual(trace, want) {
+		t.Errorf("trace = %v, want %v", trace, want)
+	}
+}
+
+func TestUseDoesNotShareInterceptors(t *testing.T) {
+	var trace []string
+	c, err := NewClient("id", "secret", WithSandbox(), WithInterceptors(tracing(&trace, "a"), tracing(&trace, "b")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Interceptors = c.Interceptors[:1]
+	other := *c
+	other.Use(tracing(&trace, "c"))
+	c.Use(tracing(&trace, "d"))
+	if len(other.Interceptors) != 2 || len(c.Interceptors) != 2 {
+		t.Fatalf("interceptors = %d and %d, want 2 and 2", len(other.Interceptors), len(c.Interceptors))
+	}
+	trace = nil
+	other.Interceptors[1](func(*http.Request) (*http.Response, error) { return nil, nil })(nil)
+	if !reflect.DeepEqual(trace, []string{"c>", "<c"}) {
+		t.Errorf("copy of the client runs %v, want its own interceptor c", trace)
+	}
+}
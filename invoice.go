@@ -0,0 +1,734 @@
+package paypalsdk
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/url"
+	"strings"
+)
+
+const (
+	K_INVOICE_API          = "/v2/invoicing/invoices"
+	K_INVOICE_TEMPLATE_API = "/v2/invoicing/templates"
+)
+
+/*
+发票流程：
+1. GenerateNextInvoiceNumber 获取下一个可用的发票号。
+2. CreateDraftInvoice 创建草稿(DRAFT)。
+3. SendInvoice 发送给付款人(SENT / SCHEDULED)。
+4. 付款人通过 PayPal 付款，或线下付款后调用 RecordInvoicePayment 记录(PAID / MARKED_AS_PAID)。
+*/
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-invoice_status
+type E_InvoiceStatus string
+
+const (
+	E_INVOICE_STATUS_DRAFT              E_InvoiceStatus = "DRAFT"              // 草稿，尚未发送。
+	E_INVOICE_STATUS_SENT               E_InvoiceStatus = "SENT"               // 已发送给付款人。
+	E_INVOICE_STATUS_SCHEDULED          E_InvoiceStatus = "SCHEDULED"          // 已设定在未来的发票日期发送。
+	E_INVOICE_STATUS_PAID               E_InvoiceStatus = "PAID"               // 付款人已通过 PayPal 付款。
+	E_INVOICE_STATUS_MARKED_AS_PAID     E_InvoiceStatus = "MARKED_AS_PAID"     // 商户已记录线下付款。
+	E_INVOICE_STATUS_CANCELLED          E_InvoiceStatus = "CANCELLED"          // 已取消。
+	E_INVOICE_STATUS_REFUNDED           E_InvoiceStatus = "REFUNDED"           // 已全额退款。
+	E_INVOICE_STATUS_PARTIALLY_PAID     E_InvoiceStatus = "PARTIALLY_PAID"     // 已部分付款。
+	E_INVOICE_STATUS_PARTIALLY_REFUNDED E_InvoiceStatus = "PARTIALLY_REFUNDED" // 已部分退款。
+	E_INVOICE_STATUS_MARKED_AS_REFUNDED E_InvoiceStatus = "MARKED_AS_REFUNDED" // 商户已记录线下退款。
+	E_INVOICE_STATUS_UNPAID             E_InvoiceStatus = "UNPAID"             // 未付款。
+	E_INVOICE_STATUS_PAYMENT_PENDING    E_InvoiceStatus = "PAYMENT_PENDING"    // 付款处理中。
+)
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-payment_term_type
+type E_InvoicePaymentTermType string
+
+const (
+	E_INVOICE_PAYMENT_TERM_DUE_ON_RECEIPT        E_InvoicePaymentTermType = "DUE_ON_RECEIPT"
+	E_INVOICE_PAYMENT_TERM_DUE_ON_DATE_SPECIFIED E_InvoicePaymentTermType = "DUE_ON_DATE_SPECIFIED"
+	E_INVOICE_PAYMENT_TERM_NET_10                E_InvoicePaymentTermType = "NET_10"
+	E_INVOICE_PAYMENT_TERM_NET_15                E_InvoicePaymentTermType = "NET_15"
+	E_INVOICE_PAYMENT_TERM_NET_30                E_InvoicePaymentTermType = "NET_30"
+	E_INVOICE_PAYMENT_TERM_NET_45                E_InvoicePaymentTermType = "NET_45"
+	E_INVOICE_PAYMENT_TERM_NET_60                E_InvoicePaymentTermType = "NET_60"
+	E_INVOICE_PAYMENT_TERM_NET_90                E_InvoicePaymentTermType = "NET_90"
+	E_INVOICE_PAYMENT_TERM_NO_DUE_DATE           E_InvoicePaymentTermType = "NO_DUE_DATE"
+)
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-payment_method
+type E_InvoicePaymentMethod string
+
+const (
+	E_INVOICE_PAYMENT_METHOD_BANK_TRANSFER E_InvoicePaymentMethod = "BANK_TRANSFER"
+	E_INVOICE_PAYMENT_METHOD_CASH          E_InvoicePaymentMethod = "CASH"
+	E_INVOICE_PAYMENT_METHOD_CHECK         E_InvoicePaymentMethod = "CHECK"
+	E_INVOICE_PAYMENT_METHOD_CREDIT_CARD   E_InvoicePaymentMethod = "CREDIT_CARD"
+	E_INVOICE_PAYMENT_METHOD_DEBIT_CARD    E_InvoicePaymentMethod = "DEBIT_CARD"
+	E_INVOICE_PAYMENT_METHOD_PAYPAL        E_InvoicePaymentMethod = "PAYPAL"
+	E_INVOICE_PAYMENT_METHOD_WIRE_TRANSFER E_InvoicePaymentMethod = "WIRE_TRANSFER"
+	E_INVOICE_PAYMENT_METHOD_OTHER         E_InvoicePaymentMethod = "OTHER"
+)
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-phone_detail
+type InvoicePhone struct {
+	CountryCode     string `json:"country_code"`               // 1<=len<=3
+	NationalNumber  string `json:"national_number"`            // 1<=len<=14
+	ExtensionNumber string `json:"extension_number,omitempty"` // len<=15
+	PhoneType       string `json:"phone_type,omitempty"`       // FAX, HOME, MOBILE, OTHER, PAGER
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-invoice_payment_term
+type InvoicePaymentTerm struct {
+	TermType E_InvoicePaymentTermType `json:"term_type,omitempty"`
+	DueDate  string                   `json:"due_date,omitempty"` // eg: 2020-03-31, term_type 为 DUE_ON_DATE_SPECIFIED 时必填。
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-metadata
+type InvoiceMetadata struct {
+	CreateTime       string `json:"create_time,omitempty"`        // 只读
+	CreatedBy        string `json:"created_by,omitempty"`         // 只读
+	LastUpdateTime   string `json:"last_update_time,omitempty"`   // 只读
+	LastUpdatedBy    string `json:"last_updated_by,omitempty"`    // 只读
+	CancelTime       string `json:"cancel_time,omitempty"`        // 只读
+	CancelledBy      string `json:"cancellled_by,omitempty"`      // 只读, PayPal 文档中即为此拼写
+	FirstSentTime    string `json:"first_sent_time,omitempty"`    // 只读
+	LastSentTime     string `json:"last_sent_time,omitempty"`     // 只读
+	LastSentBy       string `json:"last_sent_by,omitempty"`       // 只读
+	CreatedByFlow    string `json:"created_by_flow,omitempty"`    // 只读
+	RecipientViewUrl string `json:"recipient_view_url,omitempty"` // 只读, 付款人查看发票的链接。
+	InvoicerViewUrl  string `json:"invoicer_view_url,omitempty"`  // 只读
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-invoice_detail
+type InvoiceDetail struct {
+	Reference          string              `json:"reference,omitempty"`            // len<=120, 如采购订单号。
+	CurrencyCode       string              `json:"currency_code"`                  // len=3, eg: USD ……
+	Note               string              `json:"note,omitempty"`                 // len<=4000, 给付款人的备注。
+	TermsAndConditions string              `json:"terms_and_conditions,omitempty"` // len<=4000
+	Memo               string              `json:"memo,omitempty"`                 // len<=500, 仅商户可见。
+	InvoiceNumber      string              `json:"invoice_number,omitempty"`       // len<=25, 不传则自动生成。
+	InvoiceDate        string              `json:"invoice_date,omitempty"`         // eg: 2020-03-31
+	PaymentTerm        *InvoicePaymentTerm `json:"payment_term,omitempty"`
+	Metadata           *InvoiceMetadata    `json:"metadata,omitempty"` // 只读
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-invoicer_info
+type InvoicerInfo struct {
+	BusinessName    string                         `json:"business_name,omitempty"` // len<=300
+	Name            *Name                          `json:"name,omitempty"`
+	Address         *ShippingDetailAddressPortable `json:"address,omitempty"`
+	Phones          []*InvoicePhone                `json:"phones,omitempty"`
+	EmailAddress    string                         `json:"email_address,omitempty"`
+	Website         string                         `json:"website,omitempty"`
+	TaxID           string                         `json:"tax_id,omitempty"`
+	LogoUrl         string                         `json:"logo_url,omitempty"`
+	AdditionalNotes string                         `json:"additional_notes,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-billing_info
+type InvoiceBillingInfo struct {
+	BusinessName   string                         `json:"business_name,omitempty"`
+	Name           *Name                          `json:"name,omitempty"`
+	Address        *ShippingDetailAddressPortable `json:"address,omitempty"`
+	Phones         []*InvoicePhone                `json:"phones,omitempty"`
+	EmailAddress   string                         `json:"email_address,omitempty"`
+	AdditionalInfo string                         `json:"additional_info,omitempty"` // len<=40
+	Language       string                         `json:"language,omitempty"`        // eg: en-US
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-contact_information
+type InvoiceShippingInfo struct {
+	BusinessName string                         `json:"business_name,omitempty"`
+	Name         *Name                          `json:"name,omitempty"`
+	Address      *ShippingDetailAddressPortable `json:"address,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-recipient_info
+type InvoiceRecipientInfo struct {
+	BillingInfo  *InvoiceBillingInfo  `json:"billing_info,omitempty"`
+	ShippingInfo *InvoiceShippingInfo `json:"shipping_info,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-tax
+type InvoiceTax struct {
+	Name    string `json:"name"`             // len<=100
+	Percent string `json:"percent"`          // 税率百分比, eg: 7.25
+	Amount  *Money `json:"amount,omitempty"` // 只读
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-discount
+type InvoiceDiscount struct {
+	Percent string `json:"percent,omitempty"` // 百分比折扣, 与 amount 二选一。
+	Amount  *Money `json:"amount,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-item
+type InvoiceItem struct {
+	ID            string           `json:"id,omitempty"`          // 只读
+	Name          string           `json:"name"`                  // len<=200
+	Description   string           `json:"description,omitempty"` // len<=1000
+	Quantity      string           `json:"quantity"`              // len<=14, 数字, 最多 5 位小数
+	UnitAmount    *Money           `json:"unit_amount"`
+	Tax           *InvoiceTax      `json:"tax,omitempty"`
+	ItemDate      string           `json:"item_date,omitempty"` // eg: 2020-03-31
+	Discount      *InvoiceDiscount `json:"discount,omitempty"`
+	UnitOfMeasure string           `json:"unit_of_measure,omitempty"` // QUANTITY, HOURS, AMOUNT
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-partial_payment
+type InvoicePartialPayment struct {
+	AllowPartialPayment bool   `json:"allow_partial_payment,omitempty"`
+	MinimumAmountDue    *Money `json:"minimum_amount_due,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-configuration
+type InvoiceConfiguration struct {
+	TaxCalculatedAfterDiscount bool                   `json:"tax_calculated_after_discount,omitempty"` // Default: true.
+	TaxInclusive               bool                   `json:"tax_inclusive,omitempty"`                 // Default: false.
+	AllowTip                   bool                   `json:"allow_tip,omitempty"`                     // Default: false.
+	PartialPayment             *InvoicePartialPayment `json:"partial_payment,omitempty"`
+	TemplateID                 string                 `json:"template_id,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-shipping_cost
+type InvoiceShippingCost struct {
+	Amount *Money      `json:"amount,omitempty"`
+	Tax    *InvoiceTax `json:"tax,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-custom_amount
+type InvoiceCustomAmount struct {
+	Label  string `json:"label"` // len<=50
+	Amount *Money `json:"amount,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-aggregated_discount
+type InvoiceAggregatedDiscount struct {
+	InvoiceDiscount *InvoiceDiscount `json:"invoice_discount,omitempty"`
+	ItemDiscount    *Money           `json:"item_discount,omitempty"` // 只读
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-amount_with_breakdown
+type InvoiceAmountBreakdown struct {
+	ItemTotal *Money                     `json:"item_total,omitempty"` // 只读
+	Discount  *InvoiceAggregatedDiscount `json:"discount,omitempty"`
+	TaxTotal  *Money                     `json:"tax_total,omitempty"` // 只读
+	Shipping  *InvoiceShippingCost       `json:"shipping,omitempty"`
+	Custom    *InvoiceCustomAmount       `json:"custom,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-amount_summary_detail
+type InvoiceAmountSummary struct {
+	CurrencyCode string                  `json:"currency_code,omitempty"`
+	Value        string                  `json:"value,omitempty"` // 只读
+	Breakdown    *InvoiceAmountBreakdown `json:"breakdown,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-payment_detail
+type InvoicePaymentDetail struct {
+	Type         string                 `json:"type,omitempty"`         // 只读, PAYPAL, EXTERNAL
+	PaymentID    string                 `json:"payment_id,omitempty"`   // 只读
+	PaymentDate  string                 `json:"payment_date,omitempty"` // eg: 2020-03-31
+	Method       E_InvoicePaymentMethod `json:"method"`
+	Note         string                 `json:"note,omitempty"`   // len<=2000
+	Amount       *Money                 `json:"amount,omitempty"` // 不传则记为全部应付金额。
+	ShippingInfo *InvoiceShippingInfo   `json:"shipping_info,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-payments
+type InvoicePayments struct {
+	PaidAmount   *Money                  `json:"paid_amount,omitempty"` // 只读
+	Transactions []*InvoicePaymentDetail `json:"transactions,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-refund_detail
+type InvoiceRefundDetail struct {
+	Type       string                 `json:"type,omitempty"`        // 只读, PAYPAL, EXTERNAL
+	RefundID   string                 `json:"refund_id,omitempty"`   // 只读
+	RefundDate string                 `json:"refund_date,omitempty"` // eg: 2020-03-31
+	Amount     *Money                 `json:"amount,omitempty"`
+	Method     E_InvoicePaymentMethod `json:"method"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-refunds
+type InvoiceRefunds struct {
+	RefundAmount *Money                 `json:"refund_amount,omitempty"` // 只读
+	Transactions []*InvoiceRefundDetail `json:"transactions,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-invoice
+type Invoice struct {
+	ID                   string                  `json:"id,omitempty"`        // 只读
+	ParentID             string                  `json:"parent_id,omitempty"` // 只读
+	Status               E_InvoiceStatus         `json:"status,omitempty"`    // 只读
+	Detail               *InvoiceDetail          `json:"detail"`
+	Invoicer             *InvoicerInfo           `json:"invoicer,omitempty"`
+	PrimaryRecipients    []*InvoiceRecipientInfo `json:"primary_recipients,omitempty"`
+	AdditionalRecipients []*InvoiceEmailAddress  `json:"additional_recipients,omitempty"` // 抄送
+	Items                []*InvoiceItem          `json:"items,omitempty"`
+	Configuration        *InvoiceConfiguration   `json:"configuration,omitempty"`
+	Amount               *InvoiceAmountSummary   `json:"amount,omitempty"`
+	DueAmount            *Money                  `json:"due_amount,omitempty"` // 只读
+	Gratuity             *Money                  `json:"gratuity,omitempty"`   // 只读, 小费
+	Payments             *InvoicePayments        `json:"payments,omitempty"`   // 只读
+	Refunds              *InvoiceRefunds         `json:"refunds,omitempty"`    // 只读
+	Links                []*LinkDescription      `json:"links,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-email_address
+type InvoiceEmailAddress struct {
+	EmailAddress string `json:"email_address"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-notification
+type InvoiceNotification struct {
+	Subject              string   `json:"subject,omitempty"`               // len<=4000
+	Note                 string   `json:"note,omitempty"`                  // len<=4000
+	SendToInvoicer       bool     `json:"send_to_invoicer"`                // Default: false. 是否抄送给商户自己。
+	SendToRecipient      bool     `json:"send_to_recipient"`               // Default: true. false 时 PayPal 不发邮件，由商户自行分享链接。
+	AdditionalRecipients []string `json:"additional_recipients,omitempty"` // 抄送邮箱, len<=100
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-invoices
+type InvoiceList struct {
+	Items      []*Invoice         `json:"items,omitempty"`
+	TotalItems int                `json:"total_items,omitempty"`
+	TotalPages int                `json:"total_pages,omitempty"`
+	Links      []*LinkDescription `json:"links,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-amount_range
+type InvoiceAmountRange struct {
+	LowerAmount *Money `json:"lower_amount"`
+	UpperAmount *Money `json:"upper_amount"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-date_range
+type InvoiceDateRange struct {
+	Start string `json:"start"` // eg: 2020-03-01
+	End   string `json:"end"`   // eg: 2020-03-31
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-search_data
+type SearchInvoiceReq struct {
+	RecipientEmail        string              `json:"recipient_email,omitempty"`
+	RecipientFirstName    string              `json:"recipient_first_name,omitempty"`
+	RecipientLastName     string              `json:"recipient_last_name,omitempty"`
+	RecipientBusinessName string              `json:"recipient_business_name,omitempty"`
+	InvoiceNumber         string              `json:"invoice_number,omitempty"`
+	Status                []E_InvoiceStatus   `json:"status,omitempty"`
+	Reference             string              `json:"reference,omitempty"`
+	CurrencyCode          string              `json:"currency_code,omitempty"`
+	Memo                  string              `json:"memo,omitempty"`
+	TotalAmountRange      *InvoiceAmountRange `json:"total_amount_range,omitempty"`
+	InvoiceDateRange      *InvoiceDateRange   `json:"invoice_date_range,omitempty"`
+	DueDateRange          *InvoiceDateRange   `json:"due_date_range,omitempty"`
+	PaymentDateRange      *InvoiceDateRange   `json:"payment_date_range,omitempty"`
+	CreationDateRange     *InvoiceDateRange   `json:"creation_date_range,omitempty"`
+	Archived              *bool               `json:"archived,omitempty"`
+	Fields                []string            `json:"fields,omitempty"` // 返回的字段, 如 items, payments, refunds, additional_recipients, attachments
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#invoices_generate-qr-code
+type InvoiceQRCodeReq struct {
+	Width  int    `json:"width,omitempty"`  // [150, 500] Default: 500.
+	Height int    `json:"height,omitempty"` // [150, 500] Default: 500.
+	Action string `json:"action,omitempty"` // pay, details. Default: pay.
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-template_info
+type InvoiceTemplateInfo struct {
+	Detail               *InvoiceDetail          `json:"detail,omitempty"`
+	Invoicer             *InvoicerInfo           `json:"invoicer,omitempty"`
+	PrimaryRecipients    []*InvoiceRecipientInfo `json:"primary_recipients,omitempty"`
+	AdditionalRecipients []*InvoiceEmailAddress  `json:"additional_recipients,omitempty"`
+	Items                []*InvoiceItem          `json:"items,omitempty"`
+	Configuration        *InvoiceConfiguration   `json:"configuration,omitempty"`
+	Amount               *InvoiceAmountSummary   `json:"amount,omitempty"`
+	DueAmount            *Money                  `json:"due_amount,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-template_item_setting
+type InvoiceTemplateItemSetting struct {
+	FieldName         string `json:"field_name,omitempty"` // items.date, items.discount, items.tax, items.description, items.quantity
+	DisplayPreference *struct {
+		Hidden bool `json:"hidden"`
+	} `json:"display_preference,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-template_settings
+type InvoiceTemplateSettings struct {
+	TemplateItemSettings     []*InvoiceTemplateItemSetting `json:"template_item_settings,omitempty"`
+	TemplateSubtotalSettings []*InvoiceTemplateItemSetting `json:"template_subtotal_settings,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-template
+type InvoiceTemplate struct {
+	ID               string                   `json:"id,omitempty"` // 只读
+	Name             string                   `json:"name"`         // 1<=len<=500
+	DefaultTemplate  bool                     `json:"default_template,omitempty"`
+	TemplateInfo     *InvoiceTemplateInfo     `json:"template_info,omitempty"`
+	Settings         *InvoiceTemplateSettings `json:"settings,omitempty"`
+	UnitOfMeasure    string                   `json:"unit_of_measure,omitempty"`   // QUANTITY, HOURS, AMOUNT
+	StandardTemplate bool                     `json:"standard_template,omitempty"` // 只读, PayPal 预置模板
+	Links            []*LinkDescription       `json:"links,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/invoicing/v2/#definition-templates
+type InvoiceTemplateList struct {
+	Addresses []*ShippingDetailAddressPortable `json:"addresses,omitempty"`
+	Emails    string                           `json:"emails,omitempty"`
+	Phones    []*InvoicePhone                  `json:"phones,omitempty"`
+	Templates []*InvoiceTemplate               `json:"templates,omitempty"`
+	Links     []*LinkDescription               `json:"links,omitempty"`
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v2/invoicing/generate-next-invoice-number
+// Generate invoice number
+// 生成下一个发票号
+*/
+
+func (c *Client) GenerateNextInvoiceNumber() (string, error) {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/invoicing/generate-next-invoice-number"), nil)
+	if err != nil {
+		return "", err
+	}
+	rsp := &struct {
+		InvoiceNumber string `json:"invoice_number"`
+	}{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp.InvoiceNumber, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v2/invoicing/invoices
+// Create draft invoice
+// 触发webhook： INVOICING.INVOICE.CREATED
+// 创建草稿发票
+*/
+
+func (c *Client) CreateDraftInvoice(q *Invoice) (*Invoice, error) {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, K_INVOICE_API), q)
+	rsp := &Invoice{}
+	if err != nil {
+		return rsp, err
+	}
+	req.Header.Add("Prefer", "return=representation")
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v2/invoicing/invoices/INV2-Z56S-5LLA-Q52L-CPZ5
+// Show invoice details
+// 查询发票详情
+*/
+
+func (c *Client) ShowInvoiceDetails(invoiceID string) (*Invoice, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.APIBase, K_INVOICE_API, invoiceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &Invoice{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v2/invoicing/invoices?page=1&page_size=10&total_required=true
+// List invoices
+// 分页查询发票, page 从 1 开始, pageSize 取值 [1, 100], 为 0 时使用 PayPal 默认值。
+*/
+
+func (c *Client) ListInvoices(page, pageSize int) (*InvoiceList, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s?%s", c.APIBase, K_INVOICE_API, invoicePageQuery(page, pageSize)), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &InvoiceList{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v2/invoicing/search-invoices?page=1&page_size=10&total_required=true
+// Search for invoices
+// 按条件搜索发票
+*/
+
+func (c *Client) SearchInvoices(q *SearchInvoiceReq, page, pageSize int) (*InvoiceList, error) {
+	if q == nil {
+		q = &SearchInvoiceReq{}
+	}
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s?%s", c.APIBase, "/v2/invoicing/search-invoices", invoicePageQuery(page, pageSize)), q)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &InvoiceList{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v2/invoicing/invoices/INV2-Z56S-5LLA-Q52L-CPZ5/send
+// Send invoice
+// 触发webhook： INVOICING.INVOICE.CREATED (SCHEDULED 时不触发)
+// 发送发票, 发票日期在未来时状态为 SCHEDULED, 否则为 SENT。返回付款人查看发票的链接。
+*/
+
+func (c *Client) SendInvoice(invoiceID string, q *InvoiceNotification) (*LinkDescription, error) {
+	if q == nil {
+		q = &InvoiceNotification{SendToRecipient: true}
+	}
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/send", c.APIBase, K_INVOICE_API, invoiceID), q)
+	rsp := &LinkDescription{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v2/invoicing/invoices/INV2-Z56S-5LLA-Q52L-CPZ5/remind
+// returns 204 No Content
+// Send invoice reminder
+// 发送付款提醒
+*/
+
+func (c *Client) RemindInvoice(invoiceID string, q *InvoiceNotification) error {
+	if q == nil {
+		q = &InvoiceNotification{SendToRecipient: true}
+	}
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/remind", c.APIBase, K_INVOICE_API, invoiceID), q)
+	if err != nil {
+		return err
+	}
+	err = c.SendWithAuth(req, nil)
+	return err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v2/invoicing/invoices/INV2-Z56S-5LLA-Q52L-CPZ5/cancel
+// returns 204 No Content
+// Cancel sent invoice
+// 触发webhook： INVOICING.INVOICE.CANCELLED
+// 取消已发送的发票
+*/
+
+func (c *Client) CancelInvoice(invoiceID string, q *InvoiceNotification) error {
+	if q == nil {
+		q = &InvoiceNotification{SendToRecipient: true}
+	}
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/cancel", c.APIBase, K_INVOICE_API, invoiceID), q)
+	if err != nil {
+		return err
+	}
+	err = c.SendWithAuth(req, nil)
+	return err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v2/invoicing/invoices/INV2-Z56S-5LLA-Q52L-CPZ5/payments
+// Record payment for invoice
+// 触发webhook： INVOICING.INVOICE.PAID
+// 记录线下付款, 返回 payment_id 用于删除该记录。
+*/
+
+func (c *Client) RecordInvoicePayment(invoiceID string, q *InvoicePaymentDetail) (string, error) {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/payments", c.APIBase, K_INVOICE_API, invoiceID), q)
+	if err != nil {
+		return "", err
+	}
+	rsp := &struct {
+		PaymentID string `json:"payment_id"`
+	}{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp.PaymentID, err
+}
+
+/*
+// DELETE https://api.sandbox.paypal.com/v2/invoicing/invoices/INV2-Z56S-5LLA-Q52L-CPZ5/payments/EXTR-86F38350LX4353815
+// returns 204 No Content
+// Delete external payment
+// 删除线下付款记录
+*/
+
+func (c *Client) DeleteInvoicePayment(invoiceID, paymentID string) error {
+	req, err := c.NewRequest("DELETE", fmt.Sprintf("%s%s/%s/payments/%s", c.APIBase, K_INVOICE_API, invoiceID, paymentID), nil)
+	if err != nil {
+		return err
+	}
+	err = c.SendWithAuth(req, nil)
+	return err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v2/invoicing/invoices/INV2-Z56S-5LLA-Q52L-CPZ5/refunds
+// Record refund for invoice
+// 触发webhook： INVOICING.INVOICE.REFUNDED
+// 记录线下退款, 返回 refund_id 用于删除该记录。
+*/
+
+func (c *Client) RecordInvoiceRefund(invoiceID string, q *InvoiceRefundDetail) (string, error) {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/refunds", c.APIBase, K_INVOICE_API, invoiceID), q)
+	if err != nil {
+		return "", err
+	}
+	rsp := &struct {
+		RefundID string `json:"refund_id"`
+	}{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp.RefundID, err
+}
+
+/*
+// DELETE https://api.sandbox.paypal.com/v2/invoicing/invoices/INV2-Z56S-5LLA-Q52L-CPZ5/refunds/EXTR-2LG703375E477444T
+// returns 204 No Content
+// Delete external refund
+// 删除线下退款记录
+*/
+
+func (c *Client) DeleteInvoiceRefund(invoiceID, refundID string) error {
+	req, err := c.NewRequest("DELETE", fmt.Sprintf("%s%s/%s/refunds/%s", c.APIBase, K_INVOICE_API, invoiceID, refundID), nil)
+	if err != nil {
+		return err
+	}
+	err = c.SendWithAuth(req, nil)
+	return err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v2/invoicing/invoices/INV2-Z56S-5LLA-Q52L-CPZ5/generate-qr-code
+// Generate QR code
+// 生成发票二维码, 返回 base64 编码的 PNG 图片。
+*/
+
+func (c *Client) GenerateInvoiceQRCode(invoiceID string, q *InvoiceQRCodeReq) (string, error) {
+	if q == nil {
+		q = &InvoiceQRCodeReq{}
+	}
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/generate-qr-code", c.APIBase, K_INVOICE_API, invoiceID), q)
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	if err = c.SendWithAuth(req, buf); err != nil {
+		return "", err
+	}
+	return parseInvoiceQRCode(buf.Bytes())
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v2/invoicing/templates?fields=all&page=1&page_size=20
+// List templates
+// 查询发票模板
+*/
+
+func (c *Client) ListInvoiceTemplates(page, pageSize int) (*InvoiceTemplateList, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s?fields=all&%s", c.APIBase, K_INVOICE_TEMPLATE_API, invoicePageQuery(page, pageSize)), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &InvoiceTemplateList{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v2/invoicing/templates
+// Create template
+// 创建发票模板
+*/
+
+func (c *Client) CreateInvoiceTemplate(q *InvoiceTemplate) (*InvoiceTemplate, error) {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, K_INVOICE_TEMPLATE_API), q)
+	rsp := &InvoiceTemplate{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v2/invoicing/templates/TEMP-19V05281TU309413B
+// Show template details
+// 查询发票模板详情
+*/
+
+func (c *Client) ShowInvoiceTemplate(templateID string) (*InvoiceTemplate, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.APIBase, K_INVOICE_TEMPLATE_API, templateID), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &InvoiceTemplate{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// PUT https://api.sandbox.paypal.com/v2/invoicing/templates/TEMP-19V05281TU309413B
+// Fully update template
+// 全量更新发票模板
+*/
+
+func (c *Client) UpdateInvoiceTemplate(templateID string, q *InvoiceTemplate) (*InvoiceTemplate, error) {
+	req, err := c.NewRequest("PUT", fmt.Sprintf("%s%s/%s", c.APIBase, K_INVOICE_TEMPLATE_API, templateID), q)
+	rsp := &InvoiceTemplate{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// DELETE https://api.sandbox.paypal.com/v2/invoicing/templates/TEMP-19V05281TU309413B
+// returns 204 No Content
+// Delete template
+// 删除发票模板
+*/
+
+func (c *Client) DeleteInvoiceTemplate(templateID string) error {
+	req, err := c.NewRequest("DELETE", fmt.Sprintf("%s%s/%s", c.APIBase, K_INVOICE_TEMPLATE_API, templateID), nil)
+	if err != nil {
+		return err
+	}
+	err = c.SendWithAuth(req, nil)
+	return err
+}
+
+func invoicePageQuery(page, pageSize int) string {
+	v := url.Values{}
+	v.Set("total_required", "true")
+	if page > 0 {
+		v.Set("page", fmt.Sprint(page))
+	}
+	if pageSize > 0 {
+		v.Set("page_size", fmt.Sprint(pageSize))
+	}
+	return v.Encode()
+}
+
+// parseInvoiceQRCode 二维码接口返回 multipart/related 格式, 取第一个 part 的内容。
+func parseInvoiceQRCode(data []byte) (string, error) {
+	body := bytes.TrimSpace(data)
+	if !bytes.HasPrefix(body, []byte("--")) {
+		return string(body), nil
+	}
+	boundary := string(body[2:])
+	if i := strings.IndexAny(boundary, "\r\n"); i >= 0 {
+		boundary = boundary[:i]
+	}
+	part, err := multipart.NewReader(bytes.NewReader(body), boundary).NextPart()
+	if err != nil {
+		return "", err
+	}
+	img, err := ioutil.ReadAll(part)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(img)), nil
+}
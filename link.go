@@ -0,0 +1,113 @@
+package paypalsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// FindLink 返回 links 中第一个 rel 匹配的链接, 找不到返回 nil
+func FindLink(links []*LinkDescription, rel E_LinkRel) *LinkDescription {
+	for _, l := range links {
+		if l != nil && l.Rel == rel {
+			return l
+		}
+	}
+	return nil
+}
+
+// LinkDescription 将 v1 接口的 Link 转换为 LinkDescription, 以便使用 Client.Follow
+func (l *Link) LinkDescription() *LinkDescription {
+	return &LinkDescription{Href: l.Href, Rel: l.Rel, Method: l.Method}
+}
+
+// findV1Link 返回 v1 links 中第一个 rel 匹配的链接, 找不到返回 nil
+func findV1Link(links []*Link, rel E_LinkRel) *LinkDescription {
+	for _, l := range links {
+		if l != nil && l.Rel == rel {
+			return l.LinkDescription()
+		}
+	}
+	return nil
+}
+
+func (s *Subscription) FindLink(rel E_LinkRel) *LinkDescription {
+	return FindLink(s.Links, rel)
+}
+
+// ApproveURL 返回付款人同意订阅的链接, 创建订阅后将付款人重定向到该链接。
+// 订阅已被批准或不在 APPROVAL_PENDING 状态时返回空字符串。
+func (s *Subscription) ApproveURL() string {
+	if l := s.FindLink(E_LINK_REL_APPROVE); l != nil {
+		return l.Href
+	}
+	return ""
+}
+
+func (w *Webhook) FindLink(rel E_LinkRel) *LinkDescription {
+	if w.Links == nil {
+		return nil
+	}
+	for i := range *w.Links {
+		if (*w.Links)[i].Rel == rel {
+			return &(*w.Links)[i]
+		}
+	}
+	return nil
+}
+
+func (r *ListTransactionRsp) FindLink(rel E_LinkRel) *LinkDescription {
+	return FindLink(r.Links, rel)
+}
+
+func (s *Sale) FindLink(rel E_LinkRel) *LinkDescription {
+	return findV1Link(s.Links, rel)
+}
+
+func (e *Event) FindLink(rel E_LinkRel) *LinkDescription {
+	return findV1Link(e.Links, rel)
+}
+
+// Follow 按链接声明的 method 请求链接, 结果解析到 out 中, out 为 nil 时忽略返回内容。
+// 链接的 scheme 和 host 必须与 c.APIBase 相同, 否则返回错误: 链接可能来自未验签的 webhook 推送,
+// 不能把 access token 发给其他地址。
+// 适用于不需要请求体的链接, 如 self、next、resend 等, 例如翻页：
+//
+//	next := &paypalsdk.ListTransactionRsp{}
+//	err := c.Follow(ctx, rsp.FindLink(paypalsdk.E_LINK_REL_NEXT), next)
+func (c *Client) Follow(ctx context.Context, link *LinkDescription, out interface{}) error {
+	if link == nil || link.Href == "" {
+		return errors.New("paypalsdk: link to follow is empty")
+	}
+	method := link.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	if err := c.checkLinkHost(link.Href); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, link.Href, nil)
+	if err != nil {
+		return err
+	}
+	return c.SendWithAuth(req, out)
+}
+
+// checkLinkHost 检查链接是否指向 c.APIBase 的 scheme 和 host
+func (c *Client) checkLinkHost(href string) error {
+	u, err := url.Parse(href)
+	if err != nil {
+		return err
+	}
+	base, err := url.Parse(c.APIBase)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(u.Scheme, base.Scheme) || !strings.EqualFold(u.Host, base.Host) {
+		return fmt.Errorf("paypalsdk: link %s is not on %s, refusing to send the access token to it", href, c.APIBase)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package paypalsdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFollowChecksHost(t *testing.T) {
+	var authorization string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	c, err := NewClient("id", "secret", WithAPIBase(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetAccessToken("token")
+
+	tests := []struct {
+		href    string
+		wantErr bool
+	}{
+		{srv.URL + "/v1/notifications/webhooks-events/WH-1", false},
+		{"https://attacker.example.com/steal", true},
+		{"ftp" + srv.URL[len("http"):] + "/v1", true},
+		{"http://evil" + srv.URL[len("http://"):] + "/v1", true},
+		{"://bad", true},
+	}
+	for _, tt := range tests {
+		authorization = ""
+		err := c.Follow(context.Background(), &LinkDescription{Href: tt.href, Method: "GET"}, nil)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Follow(%s) error = %v, want error %v", tt.href, err, tt.wantErr)
+		}
+		if tt.wantErr && authorization != "" {
+			t.Errorf("Follow(%s) sent the access token", tt.href)
+		}
+	}
+	if err := c.Follow(context.Background(), nil, nil); err == nil {
+		t.Error("Follow(nil) error = nil")
+	}
+}
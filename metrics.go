@@ -0,0 +1,55 @@
+package paypalsdk
+
+import (
+	"fmt"
+	"time"
+)
+
+/*
+统计：
+设置 Client.Metrics 后, 每次调用、刷新 token、重试、webhook 验签和处理都会上报, Prometheus 的实现见 prometheus 子包。
+*/
+
+// Metrics receives operational metrics of a Client, implementations must be safe for concurrent use
+type Metrics interface {
+	// ObserveCall is called once per API call, statusClass is 2xx, 4xx, 5xx or error if there is no response
+	ObserveCall(operation, statusClass string, duration time.Duration)
+	// ObserveRetries is called after a call which has been retried
+	ObserveRetries(operation string, retries int)
+	// ObserveTokenRefresh is called after every attempt to get an access token, err is nil if it succeeded
+	ObserveTokenRefresh(err error)
+	// ObserveWebhookVerification is called after verifying the signature of a webhook delivery
+	ObserveWebhookVerification(verified bool, err error)
+	// ObserveWebhookHandled is called after an event is handled by the EventHandler of WebhookHandler
+	ObserveWebhookHandled(eventType string, err error)
+}
+
+// WithMetrics makes the client report metrics to m
+func WithMetrics(m Metrics) ClientOption {
+	return func(c *Client) {
+		c.Metrics = m
+	}
+}
+
+// StatusClass returns 2xx, 4xx, 5xx etc. of statusCode, or error if statusCode is 0
+func StatusClass(statusCode int) string {
+	if statusCode <= 0 {
+		return "error"
+	}
+	return fmt.Sprintf("%dxx", statusCode/100)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveCall(string, string, time.Duration) {}
+func (nopMetrics) ObserveRetries(string, int)                {}
+func (nopMetrics) ObserveTokenRefresh(error)                 {}
+func (nopMetrics) ObserveWebhookVerification(bool, error)    {}
+func (nopMetrics) ObserveWebhookHandled(string, error)       {}
+
+func (c *Client) metrics() Metrics {
+	if c.Metrics != nil {
+		return c.Metrics
+	}
+	return nopMetrics{}
+}
@@ -0,0 +1,294 @@
+package paypalsdk
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+/*
+金额计算：
+Money.Value 是字符串, 不要转成 float64 计算。先用 MinorUnits 转成以最小货币单位(如美分)表示的整数,
+或直接使用 Add、Sub、Mul、Allocate、Compare 等方法, 结果按币种的小数位数格式化回 PayPal 要求的字符串。
+*/
+
+var (
+	ErrCurrencyMismatch = errors.New("paypalsdk: currency code of money mismatch")
+	ErrMoneyOverflow    = errors.New("paypalsdk: money amount overflows")
+)
+
+// ISO-4217 中小数位数不为 2 的币种, 以 PayPal 的要求为准(HUF、TWD 在 PayPal 中不支持小数)。
+// https://developer.paypal.com/docs/reports/reference/paypal-supported-currencies/
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "HUF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "TWD": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// CurrencyExponent 返回币种的小数位数, 如 USD 为 2, JPY 为 0
+func CurrencyExponent(currencyCode string) int {
+	if e, ok := currencyExponents[strings.ToUpper(currencyCode)]; ok {
+		return e
+	}
+	return 2
+}
+
+// NewMoney 用最小货币单位表示的金额创建 Money, 如 NewMoney("USD", 1999) 为 19.99 USD
+func NewMoney(currencyCode string, minorUnits int64) *Money {
+	return &Money{
+		CurrencyCode: strings.ToUpper(currencyCode),
+		Value:        formatMinorUnits(minorUnits, CurrencyExponent(currencyCode)),
+	}
+}
+
+// ParseMoney 校验 value 并按币种的小数位数规范化, 如 ParseMoney("USD", "5") 的 Value 为 "5.00"
+func ParseMoney(currencyCode, value string) (*Money, error) {
+	minor, err := parseMinorUnits(value, CurrencyExponent(currencyCode))
+	if err != nil {
+		return nil, err
+	}
+	return NewMoney(currencyCode, minor), nil
+}
+
+// MinorUnits 返回以最小货币单位表示的金额, 如 19.99 USD 返回 1999
+func (m *Money) MinorUnits() (int64, error) {
+	return parseMinorUnits(m.Value, CurrencyExponent(m.CurrencyCode))
+}
+
+func (m *Money) String() string {
+	return fmt.Sprintf("%s %s", m.Value, m.CurrencyCode)
+}
+
+func (m *Money) IsZero() bool {
+	minor, err := m.MinorUnits()
+	return err == nil && minor == 0
+}
+
+func (m *Money) Add(o *Money) (*Money, error) {
+	a, b, err := m.operands(o)
+	if err != nil {
+		return nil, err
+	}
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+		return nil, ErrMoneyOverflow
+	}
+	return NewMoney(m.CurrencyCode, a+b), nil
+}
+
+func (m *Money) Sub(o *Money) (*Money, error) {
+	a, b, err := m.operands(o)
+	if err != nil {
+		return nil, err
+	}
+	if (b < 0 && a > math.MaxInt64+b) || (b > 0 && a < math.MinInt64+b) {
+		return nil, ErrMoneyOverflow
+	}
+	return NewMoney(m.CurrencyCode, a-b), nil
+}
+
+// Mul 返回单价乘以数量的金额, 如订阅的 quantity
+func (m *Money) Mul(quantity int64) (*Money, error) {
+	a, err := m.MinorUnits()
+	if err != nil {
+		return nil, err
+	}
+	if a != 0 && quantity != 0 {
+		r := a * quantity
+		if r/quantity != a || (a == -1 && quantity == math.MinInt64) || (quantity == -1 && a == math.MinInt64) {
+			return nil, ErrMoneyOverflow
+		}
+		return NewMoney(m.CurrencyCode, r), nil
+	}
+	return NewMoney(m.CurrencyCode, 0), nil
+}
+
+// Compare 比较两个金额, m < o 返回 -1, m == o 返回 0, m > o 返回 1
+func (m *Money) Compare(o *Money) (int, error) {
+	a, b, err := m.operands(o)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case a < b:
+		return -1, nil
+	case a > b:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Allocate 按 ratios 的比例拆分金额, 各部分之和严格等于原金额, 除不尽的最小货币单位依次分给前面的部分。
+// 如 10.00 USD 按 1:1:1 拆分为 3.34、3.33、3.33
+func (m *Money) Allocate(ratios ...int64) ([]*Money, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("paypalsdk: ratios are required to allocate money")
+	}
+	var total int64
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, errors.New("paypalsdk: ratios to allocate money can not be negative")
+		}
+		if total > math.MaxInt64-r {
+			return nil, ErrMoneyOverflow
+		}
+		total += r
+	}
+	if total == 0 {
+		return nil, errors.New("paypalsdk: sum of ratios to allocate money can not be zero")
+	}
+	a, err := m.MinorUnits()
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]int64, len(ratios))
+	remainder := a
+	for i, r := range ratios {
+		share, ok := mulDiv(a, r, total)
+		if !ok {
+			return nil, ErrMoneyOverflow
+		}
+		parts[i] = share
+		remainder -= share
+	}
+	unit := int64(1)
+	if remainder < 0 {
+		unit = -1
+	}
+	for i := 0; remainder != 0; i = (i + 1) % len(parts) {
+		if ratios[i] == 0 {
+			continue
+		}
+		parts[i] += unit
+		remainder -= unit
+	}
+
+	result := make([]*Money, len(parts))
+	for i, p := range parts {
+		result[i] = NewMoney(m.CurrencyCode, p)
+	}
+	return result, nil
+}
+
+// Split 将金额平均拆分为 n 份, 见 Allocate
+func (m *Money) Split(n int) ([]*Money, error) {
+	if n <= 0 {
+		return nil, errors.New("paypalsdk: money can only be split into a positive number of parts")
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// ToAmount 转换为 v1 接口使用的 Amount
+func (m *Money) ToAmount() *Amount {
+	return &Amount{Currency: m.CurrencyCode, Total: m.Value}
+}
+
+// ToCurrency 转换为 v1 接口使用的 Currency
+func (m *Money) ToCurrency() *Currency {
+	return &Currency{Currency: m.CurrencyCode, Value: m.Value}
+}
+
+// MoneyFromAmount 将 v1 接口的 Amount 转换为 Money, 并规范化小数位数
+func MoneyFromAmount(a *Amount) (*Money, error) {
+	return ParseMoney(a.Currency, a.Total)
+}
+
+// MoneyFromCurrency 将 v1 接口的 Currency 转换为 Money, 并规范化小数位数
+func MoneyFromCurrency(c *Currency) (*Money, error) {
+	return ParseMoney(c.Currency, c.Value)
+}
+
+func (m *Money) operands(o *Money) (int64, int64, error) {
+	if !strings.EqualFold(m.CurrencyCode, o.CurrencyCode) {
+		return 0, 0, ErrCurrencyMismatch
+	}
+	a, err := m.MinorUnits()
+	if err != nil {
+		return 0, 0, err
+	}
+	b, err := o.MinorUnits()
+	if err != nil {
+		return 0, 0, err
+	}
+	return a, b, nil
+}
+
+// parseMinorUnits 将 "123.45" 形式的金额精确转换为最小货币单位, 不经过浮点数
+func parseMinorUnits(value string, exponent int) (int64, error) {
+	s := strings.TrimSpace(value)
+	if s == "" {
+		return 0, nil
+	}
+	neg := false
+	if s[0] == '-' || s[0] == '+' {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if intPart == "" && fracPart == "" {
+		return 0, fmt.Errorf("paypalsdk: invalid money value %q", value)
+	}
+	// 超出币种小数位数的部分只允许是 0
+	if len(fracPart) > exponent {
+		if strings.Trim(fracPart[exponent:], "0") != "" {
+			return 0, fmt.Errorf("paypalsdk: money value %q has more than %d decimal places", value, exponent)
+		}
+		fracPart = fracPart[:exponent]
+	}
+	fracPart += strings.Repeat("0", exponent-len(fracPart))
+
+	digits := intPart + fracPart
+	for _, ch := range digits {
+		if ch < '0' || ch > '9' {
+			return 0, fmt.Errorf("paypalsdk: invalid money value %q", value)
+		}
+	}
+	if digits == "" {
+		return 0, nil
+	}
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, ErrMoneyOverflow
+	}
+	if neg {
+		minor = -minor
+	}
+	return minor, nil
+}
+
+func formatMinorUnits(minor int64, exponent int) string {
+	sign := ""
+	u := uint64(minor)
+	if minor < 0 {
+		sign = "-"
+		u = uint64(-(minor + 1)) + 1
+	}
+	digits := strconv.FormatUint(u, 10)
+	if exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+// mulDiv 返回 a*b/c (向零取整), 结果溢出 int64 时返回 false
+func mulDiv(a, b, c int64) (int64, bool) {
+	r := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+	r.Quo(r, big.NewInt(c))
+	if !r.IsInt64() {
+		return 0, false
+	}
+	return r.Int64(), true
+}
@@ -0,0 +1,193 @@
+package paypalsdk
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseMinorUnits(t *testing.T) {
+	tests := []struct {
+		currency string
+		value    string
+		want     int64
+		wantErr  bool
+	}{
+		{"USD", "19.99", 1999, false},
+		{"USD", "5", 500, false},
+		{"USD", "5.", 500, false},
+		{"USD", ".5", 50, false},
+		{"USD", "0.10", 10, false},
+		{"USD", "1.500", 150, false},
+		{"USD", " 7.25 ", 725, false},
+		{"USD", "-3.01", -301, false},
+		{"USD", "+3.01", 301, false},
+		{"USD", "", 0, false},
+		{"usd", "1.00", 100, false},
+		{"JPY", "1500", 1500, false},
+		{"JPY", "1500.0", 1500, false},
+		{"KWD", "1.234", 1234, false},
+		{"USD", "1.001", 0, true},
+		{"JPY", "1.5", 0, true},
+		{"USD", "1,00", 0, true},
+		{"USD", "1e3", 0, true},
+		{"USD", "-", 0, true},
+		{"USD", ".", 0, true},
+		{"USD", "1.2.3", 0, true},
+		{"USD", "92233720368547758.07", math.MaxInt64, false},
+		{"USD", "92233720368547758.08", 0, true},
+	}
+	for _, tt := range tests {
+		m := &Money{CurrencyCode: tt.currency, Value: tt.value}
+		got, err := m.MinorUnits()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("MinorUnits(%q %s) error = %v, want error %v", tt.value, tt.currency, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("MinorUnits(%q %s) = %d, want %d", tt.value, tt.currency, got, tt.want)
+		}
+	}
+	if _, err := (&Money{CurrencyCode: "USD", Value: "92233720368547758.08"}).MinorUnits(); err != ErrMoneyOverflow {
+		t.Errorf("MinorUnits of an overflowing value error = %v, want ErrMoneyOverflow", err)
+	}
+}
+
+func TestNewMoney(t *testing.T) {
+	tests := []struct {
+		currency string
+		minor    int64
+		want     string
+	}{
+		{"USD", 1999, "19.99"},
+		{"USD", 5, "0.05"},
+		{"USD", 0, "0.00"},
+		{"USD", -5, "-0.05"},
+		{"usd", 100, "1.00"},
+		{"JPY", 1500, "1500"},
+		{"KWD", 1, "0.001"},
+		{"USD", math.MinInt64, "-92233720368547758.08"},
+	}
+	for _, tt := range tests {
+		m := NewMoney(tt.currency, tt.minor)
+		if m.Value != tt.want || m.CurrencyCode != strings.ToUpper(tt.currency) {
+			t.Errorf("NewMoney(%s, %d) = %s, want %s %s", tt.currency, tt.minor, m, tt.want, strings.ToUpper(tt.currency))
+		}
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	usd := func(v string) *Money { return &Money{CurrencyCode: "USD", Value: v} }
+	max := NewMoney("USD", math.MaxInt64)
+	min := NewMoney("USD", math.MinInt64)
+
+	tests := []struct {
+		name    string
+		op      func() (*Money, error)
+		want    string
+		wantErr error
+	}{
+		{"add", func() (*Money, error) { return usd("1.10").Add(usd("2.2")) }, "3.30", nil},
+		{"add negative", func() (*Money, error) { return usd("1.10").Add(usd("-2.20")) }, "-1.10", nil},
+		{"add overflow", func() (*Money, error) { return max.Add(usd("0.01")) }, "", ErrMoneyOverflow},
+		{"add underflow", func() (*Money, error) { return min.Add(usd("-0.01")) }, "", ErrMoneyOverflow},
+		{"add currency mismatch", func() (*Money, error) { return usd("1").Add(&Money{CurrencyCode: "EUR", Value: "1"}) }, "", ErrCurrencyMismatch},
+		{"sub", func() (*Money, error) { return usd("10.00").Sub(usd("0.59")) }, "9.41", nil},
+		{"sub overflow", func() (*Money, error) { return max.Sub(usd("-0.01")) }, "", ErrMoneyOverflow},
+		{"sub underflow", func() (*Money, error) { return min.Sub(usd("0.01")) }, "", ErrMoneyOverflow},
+		{"mul", func() (*Money, error) { return usd("9.99").Mul(3) }, "29.97", nil},
+		{"mul zero", func() (*Money, error) { return max.Mul(0) }, "0.00", nil},
+		{"mul overflow", func() (*Money, error) { return max.Mul(2) }, "", ErrMoneyOverflow},
+		{"mul min by -1", func() (*Money, error) { return min.Mul(-1) }, "", ErrMoneyOverflow},
+		{"mul -1 by min", func() (*Money, error) { return usd("-0.01").Mul(math.MinInt64) }, "", ErrMoneyOverflow},
+	}
+	for _, tt := range tests {
+		got, err := tt.op()
+		if err != tt.wantErr {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.Value != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, got.Value, tt.want)
+		}
+	}
+
+	if c, err := usd("1.00").Compare(usd("1")); err != nil || c != 0 {
+		t.Errorf("Compare(1.00, 1) = %d, %v", c, err)
+	}
+	if c, err := usd("0.99").Compare(usd("1")); err != nil || c != -1 {
+		t.Errorf("Compare(0.99, 1) = %d, %v", c, err)
+	}
+	if _, err := usd("1").Compare(&Money{CurrencyCode: "JPY", Value: "1"}); err != ErrCurrencyMismatch {
+		t.Errorf("Compare of different currencies error = %v", err)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		currency string
+		value    string
+		ratios   []int64
+		want     []string
+		wantErr  bool
+	}{
+		{"USD", "10.00", []int64{1, 1, 1}, []string{"3.34", "3.33", "3.33"}, false},
+		{"USD", "0.05", []int64{3, 7}, []string{"0.02", "0.03"}, false},
+		{"USD", "100.00", []int64{70, 30}, []string{"70.00", "30.00"}, false},
+		{"USD", "0.02", []int64{1, 1, 1}, []string{"0.01", "0.01", "0.00"}, false},
+		{"USD", "1.00", []int64{0, 1, 1}, []string{"0.00", "0.50", "0.50"}, false},
+		{"USD", "0.01", []int64{0, 1, 1}, []string{"0.00", "0.01", "0.00"}, false},
+		{"USD", "-10.00", []int64{1, 1, 1}, []string{"-3.34", "-3.33", "-3.33"}, false},
+		{"JPY", "100", []int64{1, 2}, []string{"34", "66"}, false},
+		{"USD", "92233720368547758.07", []int64{math.MaxInt64 / 2, math.MaxInt64 / 2}, []string{"46116860184273879.04", "46116860184273879.03"}, false},
+		{"USD", "1.00", []int64{math.MaxInt64, 1}, nil, true},
+		{"USD", "1.00", nil, nil, true},
+		{"USD", "1.00", []int64{0, 0}, nil, true},
+		{"USD", "1.00", []int64{1, -1}, nil, true},
+		{"USD", "bad", []int64{1}, nil, true},
+	}
+	for _, tt := range tests {
+		m := &Money{CurrencyCode: tt.currency, Value: tt.value}
+		parts, err := m.Allocate(tt.ratios...)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Allocate(%s, %v) error = %v, want error %v", m, tt.ratios, err, tt.wantErr)
+			continue
+		}
+		if len(parts) != len(tt.want) {
+			t.Errorf("Allocate(%s, %v) = %v, want %v", m, tt.ratios, parts, tt.want)
+			continue
+		}
+		sum := NewMoney(tt.currency, 0)
+		for i, p := range parts {
+			if p.Value != tt.want[i] {
+				t.Errorf("Allocate(%s, %v)[%d] = %s, want %s", m, tt.ratios, i, p.Value, tt.want[i])
+			}
+			if sum, err = sum.Add(p); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if len(parts) > 0 {
+			if c, _ := sum.Compare(m); c != 0 {
+				t.Errorf("Allocate(%s, %v) parts sum to %s", m, tt.ratios, sum)
+			}
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	parts, err := (&Money{CurrencyCode: "USD", Value: "100.00"}).Split(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"33.34", "33.33", "33.33"}
+	for i, p := range parts {
+		if p.Value != want[i] {
+			t.Errorf("Split(3)[%d] = %s, want %s", i, p.Value, want[i])
+		}
+	}
+	for _, n := range []int{0, -1} {
+		if _, err := (&Money{CurrencyCode: "USD", Value: "1"}).Split(n); err == nil {
+			t.Errorf("Split(%d) error = nil", n)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package paypalsdk
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout is the overall timeout of a call, including reading the response body
+	DefaultTimeout = 30 * time.Second
+	// DefaultLocale is sent as Accept-Language unless changed by WithLocale
+	DefaultLocale = "en_US"
+
+	kEnvClientID = "PAYPAL_CLIENT_ID"
+	kEnvSecret   = "PAYPAL_SECRET"
+	kEnvMode     = "PAYPAL_MODE" // sandbox or live
+)
+
+// Logger is where requests and responses are logged, *log.Logger and *logrus.Logger implement it
+type Logger interface {
+	Println(v ...interface{})
+}
+
+// ClientOption configures a Client created by NewClient
+type ClientOption func(*Client)
+
+// WithSandbox makes the client call https://api.sandbox.paypal.com, this is the default
+func WithSandbox() ClientOption {
+	return WithAPIBase(APIBaseSandBox)
+}
+
+// WithLive makes the client call https://api.paypal.com
+func WithLive() ClientOption {
+	return WithAPIBase(APIBaseLive)
+}
+
+// WithAPIBase makes the client call a custom base URL, eg: a fake server in tests
+func WithAPIBase(APIBase string) ClientOption {
+	return func(c *Client) {
+		c.APIBase = strings.TrimRight(APIBase, "/")
+	}
+}
+
+// WithHTTPClient replaces the default *http.Client
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.Client = client
+	}
+}
+
+// WithTimeout changes the overall timeout of a call, default is DefaultTimeout.
+// It is applied to the *http.Client given by WithHTTPClient as well, without changing the caller's copy
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger makes the client log requests and responses to l instead of the package logger
+func WithLogger(l Logger) ClientOption {
+	return func(c *Client) {
+		c.Logger = l
+	}
+}
+
+// WithRetry retries a failed call up to maxRetries times, see RetryPolicy
+func WithRetry(maxRetries int, backoff time.Duration) ClientOption {
+	return func(c *Client) {
+		c.Retry = &RetryPolicy{MaxRetries: maxRetries, Backoff: backoff}
+	}
+}
+
+// WithUserAgent sets the User-Agent header of every call
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithLocale sets the Accept-Language header of every call, eg: zh_CN. Default is DefaultLocale
+func WithLocale(locale string) ClientOption {
+	return func(c *Client) {
+		c.Locale = locale
+	}
+}
+
+// NewClientFromEnv creates a Client from PAYPAL_CLIENT_ID, PAYPAL_SECRET and PAYPAL_MODE.
+// PAYPAL_MODE is sandbox or live, default is sandbox. opts are applied after the environment
+func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
+	var env ClientOption
+	switch mode := strings.ToLower(os.Getenv(kEnvMode)); mode {
+	case "", "sandbox":
+		env = WithSandbox()
+	case "live":
+		env = WithLive()
+	default:
+		return nil, fmt.Errorf("paypalsdk: %s must be sandbox or live, got %q", kEnvMode, mode)
+	}
+	if os.Getenv(kEnvClientID) == "" || os.Getenv(kEnvSecret) == "" {
+		return nil, errors.New("paypalsdk: " + kEnvClientID + " and " + kEnvSecret + " are required to create a Client")
+	}
+	return NewClient(os.Getenv(kEnvClientID), os.Getenv(kEnvSecret), append([]ClientOption{env}, opts...)...)
+}
+
+// newHTTPClient returns the default *http.Client, unlike http.DefaultClient no step of a call can hang forever
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: DefaultTimeout,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   10 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			MaxIdleConns:          100,
+			MaxIdleConnsPerHost:   10,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: DefaultTimeout,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+}
@@ -0,0 +1,210 @@
+// Package paypalotel adds OpenTelemetry tracing to paypalsdk.
+//
+// Every API call becomes a client span named after the operation, eg: paypal.subscriptions.create:
+//
+//	c, err := paypalsdk.NewClient(clientID, secret, paypalsdk.WithCallHooks(paypalotel.NewHook()))
+//
+// The span is a child of the span in the request context, use Client.WithContext to join the caller's trace:
+//
+//	sub, err := c.WithContext(ctx).CreateSubscription(req)
+//
+// Webhook deliveries become server spans, and event processing spans link to the delivery:
+//
+//	http.Handle("/paypal/webhook", paypalotel.WebhookMiddleware(c.WebhookHandler(webhookID, handle)))
+//
+//	func handle(ctx context.Context, e *paypalsdk.Event) error {
+//		ctx, span := paypalotel.StartEventSpan(ctx, e)
+//		defer span.End()
+//		...
+//	}
+package paypalotel
+
+import (
+	"context"
+	"net/http"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/YYRise/PayPal-GO-SDK/otel"
+
+	SpanWebhookDeliver = "paypal.webhook.deliver"
+	SpanWebhookProcess = "paypal.webhook.process"
+
+	AttrDebugID           = attribute.Key("paypal.debug_id")
+	AttrRetryCount        = attribute.Key("paypal.retry_count")
+	AttrTokenRefreshed    = attribute.Key("paypal.token_refreshed")
+	AttrTransmissionID    = attribute.Key("paypal.webhook.transmission_id")
+	AttrEventID           = attribute.Key("paypal.webhook.event_id")
+	AttrEventType         = attribute.Key("paypal.webhook.event_type")
+	AttrEventResourceType = attribute.Key("paypal.webhook.resource_type")
+
+	attrHTTPMethod     = attribute.Key("http.request.method")
+	attrHTTPRoute      = attribute.Key("http.route")
+	attrHTTPStatusCode = attribute.Key("http.response.status_code")
+	attrURLPath        = attribute.Key("url.path")
+)
+
+type config struct {
+	tracer      trace.Tracer
+	propagators propagation.TextMapPropagator
+}
+
+// Option configures the tracer provider and propagators, default are the global ones
+type Option func(*config)
+
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracer = tp.Tracer(instrumentationName)
+	}
+}
+
+func WithPropagators(p propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = p
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.tracer == nil {
+		c.tracer = otel.GetTracerProvider().Tracer(instrumentationName)
+	}
+	if c.propagators == nil {
+		c.propagators = otel.GetTextMapPropagator()
+	}
+	return c
+}
+
+// Instrument makes every call of c traced
+func Instrument(c *paypalsdk.Client, opts ...Option) {
+	c.AddCallHooks(NewHook(opts...))
+}
+
+// NewHook returns a paypalsdk.CallHook which starts a client span for every API call.
+// The span covers retries and the token refresh, which has its own child span named paypal.oauth2.token
+func NewHook(opts ...Option) paypalsdk.CallHook {
+	return &hook{cfg: newConfig(opts)}
+}
+
+type hook struct {
+	cfg *config
+}
+
+func (h *hook) BeforeCall(ctx context.Context, call *paypalsdk.Call) context.Context {
+	ctx, _ = h.cfg.tracer.Start(ctx, call.Operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attrHTTPMethod.String(call.Method),
+			attrHTTPRoute.String(call.Route),
+		),
+	)
+	return ctx
+}
+
+func (h *hook) AfterCall(ctx context.Context, call *paypalsdk.Call) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		AttrRetryCount.Int(call.Retries),
+		AttrTokenRefreshed.Bool(call.TokenRefreshed),
+	)
+	if call.StatusCode != 0 {
+		span.SetAttributes(attrHTTPStatusCode.Int(call.StatusCode))
+	}
+	if call.DebugID != "" {
+		span.SetAttributes(AttrDebugID.String(call.DebugID))
+	}
+	if call.Err != nil {
+		span.RecordError(call.Err)
+		span.SetStatus(codes.Error, call.Err.Error())
+	}
+	span.End()
+}
+
+type deliveryContextKey struct{}
+
+// WebhookMiddleware starts a server span for every webhook delivery, continuing the trace found in the request headers if any.
+// Spans started by StartEventSpan with the request context link to it
+func WebhookMiddleware(next http.Handler, opts ...Option) http.Handler {
+	cfg := newConfig(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := cfg.propagators.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := cfg.tracer.Start(ctx, SpanWebhookDeliver,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attrHTTPMethod.String(r.Method),
+				attrURLPath.String(r.URL.Path),
+				AttrTransmissionID.String(r.Header.Get("Paypal-Transmission-Id")),
+			),
+		)
+		defer span.End()
+
+		ctx = context.WithValue(ctx, deliveryContextKey{}, span.SpanContext())
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r.WithContext(ctx))
+
+		span.SetAttributes(attrHTTPStatusCode.Int(sw.status))
+		if sw.status >= http.StatusInternalServerError {
+			// PayPal 会重新投递
+			span.SetStatus(codes.Error, http.StatusText(sw.status))
+		}
+	})
+}
+
+// StartEventSpan starts a span for processing e, linked to the delivery span of WebhookMiddleware or ExtractDelivery in ctx.
+// The caller must end the span
+func StartEventSpan(ctx context.Context, e *paypalsdk.Event, opts ...Option) (context.Context, trace.Span) {
+	cfg := newConfig(opts)
+	startOpts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			AttrEventID.String(e.Id),
+			AttrEventType.String(e.EventType),
+			AttrEventResourceType.String(string(e.ResourceType)),
+		),
+	}
+	if sc, ok := ctx.Value(deliveryContextKey{}).(trace.SpanContext); ok && sc.IsValid() {
+		startOpts = append(startOpts, trace.WithLinks(trace.Link{SpanContext: sc}))
+	}
+	return cfg.tracer.Start(ctx, SpanWebhookProcess, startOpts...)
+}
+
+// InjectDelivery writes the delivery span in ctx to carrier, so that an event processed asynchronously,
+// eg: through a message queue, can still link to its delivery with ExtractDelivery
+func InjectDelivery(ctx context.Context, carrier propagation.TextMapCarrier, opts ...Option) {
+	sc, ok := ctx.Value(deliveryContextKey{}).(trace.SpanContext)
+	if !ok || !sc.IsValid() {
+		return
+	}
+	cfg := newConfig(opts)
+	cfg.propagators.Inject(trace.ContextWithRemoteSpanContext(context.Background(), sc), carrier)
+}
+
+// ExtractDelivery returns ctx carrying the delivery span written by InjectDelivery
+func ExtractDelivery(ctx context.Context, carrier propagation.TextMapCarrier, opts ...Option) context.Context {
+	cfg := newConfig(opts)
+	sc := trace.SpanContextFromContext(cfg.propagators.Extract(context.Background(), carrier))
+	if !sc.IsValid() {
+		return ctx
+	}
+	return context.WithValue(ctx, deliveryContextKey{}, sc)
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
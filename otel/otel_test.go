@@ -0,0 +1,181 @@
+package paypalotel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTracerProvider() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	sr := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)), sr
+}
+
+func findSpan(t *testing.T, sr *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	var found []sdktrace.ReadOnlySpan
+	for _, s := range sr.Ended() {
+		if s.Name() == name {
+			found = append(found, s)
+		}
+	}
+	if len(found) != 1 {
+		t.Fatalf("%d spans named %s, want 1", len(found), name)
+	}
+	return found[0]
+}
+
+func attr(s sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range s.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func newClient(t *testing.T, tp trace.TracerProvider, h http.HandlerFunc) *paypalsdk.Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	c, err := paypalsdk.NewClient("id", "secret", paypalsdk.WithAPIBase(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	Instrument(c, WithTracerProvider(tp))
+	c.SetAccessToken("token")
+	return c
+}
+
+func TestCallSpan(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		withCtx    bool
+		wantStatus codes.Code
+	}{
+		{"joins the caller's trace", http.StatusOK, true, codes.Unset},
+		{"error", http.StatusUnprocessableEntity, true, codes.Error},
+		{"root without WithContext", http.StatusOK, false, codes.Unset},
+	}
+	for _, tt := range tests {
+		tp, sr := newTracerProvider()
+		c := newClient(t, tp, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Paypal-Debug-Id", "debug-1")
+			w.WriteHeader(tt.status)
+			w.Write([]byte(`{"id":"I-1","name":"UNPROCESSABLE_ENTITY"}`))
+		})
+
+		ctx, parent := tp.Tracer("test").Start(context.Background(), "caller")
+		if tt.withCtx {
+			c = c.WithContext(ctx)
+		}
+		_, err := c.ShowSubscriptionDetails("I-1")
+		parent.End()
+		if (err != nil) != (tt.status != http.StatusOK) {
+			t.Fatalf("%s: ShowSubscriptionDetails error = %v", tt.name, err)
+		}
+
+		span := findSpan(t, sr, "paypal.subscriptions.get")
+		if tt.withCtx {
+			if span.Parent().SpanID() != parent.SpanContext().SpanID() || span.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+				t.Errorf("%s: span parent = %v, want the caller's span %v", tt.name, span.Parent(), parent.SpanContext())
+			}
+		} else if span.Parent().IsValid() {
+			t.Errorf("%s: span parent = %v, want a root span", tt.name, span.Parent())
+		}
+		if span.SpanKind() != trace.SpanKindClient {
+			t.Errorf("%s: span kind = %v", tt.name, span.SpanKind())
+		}
+		if got := attr(span, attrHTTPRoute).AsString(); got != "/v1/billing/subscriptions/{id}" {
+			t.Errorf("%s: route = %q", tt.name, got)
+		}
+		if got := attr(span, attrHTTPStatusCode).AsInt64(); got != int64(tt.status) {
+			t.Errorf("%s: status code = %d, want %d", tt.name, got, tt.status)
+		}
+		if got := attr(span, AttrDebugID).AsString(); got != "debug-1" {
+			t.Errorf("%s: debug_id = %q", tt.name, got)
+		}
+		if span.Status().Code != tt.wantStatus {
+			t.Errorf("%s: span status = %v, want %v", tt.name, span.Status().Code, tt.wantStatus)
+		}
+	}
+}
+
+func TestTokenSpan(t *testing.T) {
+	tp, sr := newTracerProvider()
+	c := newClient(t, tp, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"access_token":"A21AA","expires_in":32400}`))
+	})
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "caller")
+	if _, err := c.WithContext(ctx).GetAccessToken(); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	span := findSpan(t, sr, "paypal.oauth2.token")
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("token span parent = %v, want the caller's span", span.Parent())
+	}
+	if c.Token.Token != "A21AA" {
+		t.Errorf("token refreshed by WithContext copy not kept, Token = %q", c.Token.Token)
+	}
+}
+
+func TestWebhookSpans(t *testing.T) {
+	tp, sr := newTracerProvider()
+	prop := propagation.TraceContext{}
+	opts := []Option{WithTracerProvider(tp), WithPropagators(prop)}
+
+	var processCtx context.Context
+	h := WebhookMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := StartEventSpan(r.Context(), &paypalsdk.Event{Id: "WH-1", EventType: "BILLING.SUBSCRIPTION.ACTIVATED"}, opts...)
+		processCtx = ctx
+		span.End()
+		w.WriteHeader(http.StatusInternalServerError)
+	}), opts...)
+
+	ctx, upstream := tp.Tracer("test").Start(context.Background(), "upstream")
+	req := httptest.NewRequest("POST", "/paypal/webhook", nil)
+	req.Header.Set("Paypal-Transmission-Id", "T-1")
+	prop.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	upstream.End()
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	deliver := findSpan(t, sr, SpanWebhookDeliver)
+	if deliver.Parent().SpanID() != upstream.SpanContext().SpanID() || !deliver.Parent().IsRemote() {
+		t.Errorf("delivery span parent = %v, want the remote span in the headers", deliver.Parent())
+	}
+	if deliver.SpanKind() != trace.SpanKindServer || deliver.Status().Code != codes.Error {
+		t.Errorf("delivery span kind = %v, status = %v", deliver.SpanKind(), deliver.Status().Code)
+	}
+	if got := attr(deliver, AttrTransmissionID).AsString(); got != "T-1" {
+		t.Errorf("transmission_id = %q", got)
+	}
+
+	process := findSpan(t, sr, SpanWebhookProcess)
+	if len(process.Links()) != 1 || process.Links()[0].SpanContext.SpanID() != deliver.SpanContext().SpanID() {
+		t.Errorf("process span links = %v, want the delivery span", process.Links())
+	}
+	if got := attr(process, AttrEventType).AsString(); got != "BILLING.SUBSCRIPTION.ACTIVATED" {
+		t.Errorf("event_type = %q", got)
+	}
+
+	// 异步处理时通过 InjectDelivery/ExtractDelivery 传递投递的 span
+	carrier := propagation.MapCarrier{}
+	InjectDelivery(processCtx, carrier, opts...)
+	_, span := StartEventSpan(ExtractDelivery(context.Background(), carrier, opts...), &paypalsdk.Event{Id: "WH-1"}, opts...)
+	span.End()
+	if links := span.(sdktrace.ReadOnlySpan).Links(); len(links) != 1 || links[0].SpanContext.SpanID() != deliver.SpanContext().SpanID() {
+		t.Errorf("span of an extracted delivery links = %v, want the delivery span", links)
+	}
+}
@@ -0,0 +1,233 @@
+package paypalsdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+const (
+	K_PARTNER_REFERRAL_API = "/v2/customer/partner-referrals"
+	K_PARTNER_API          = "/v1/customer/partners"
+)
+
+/*
+平台(partner)代卖家(seller)收款流程：
+1. CreatePartnerReferral 创建推荐链接, 卖家通过 rel=action_url 链接注册或登录 PayPal 并授权给平台。
+2. 卖家授权后 PayPal 回调 partner_config_override.return_url, 带上 merchantIdInPayPal。
+   也可以通过 FindSellerByTrackingID 用 tracking_id 查询卖家的 merchant_id。
+3. ShowSellerStatus 确认卖家 payments_receivable、primary_email_confirmed 后即可代卖家调用 API:
+	client.OnBehalfOf(merchantID).CreateSubscription(...)
+*/
+
+// https://developer.paypal.com/docs/api/partner-referrals/v2/#definition-operation
+type E_PartnerReferralFeature string
+
+const (
+	E_PARTNER_REFERRAL_FEATURE_PAYMENT                     E_PartnerReferralFeature = "PAYMENT"
+	E_PARTNER_REFERRAL_FEATURE_REFUND                      E_PartnerReferralFeature = "REFUND"
+	E_PARTNER_REFERRAL_FEATURE_PARTNER_FEE                 E_PartnerReferralFeature = "PARTNER_FEE"
+	E_PARTNER_REFERRAL_FEATURE_DELAY_FUNDS_DISBURSEMENT    E_PartnerReferralFeature = "DELAY_FUNDS_DISBURSEMENT"
+	E_PARTNER_REFERRAL_FEATURE_READ_SELLER_DISPUTE         E_PartnerReferralFeature = "READ_SELLER_DISPUTE"
+	E_PARTNER_REFERRAL_FEATURE_UPDATE_SELLER_DISPUTE       E_PartnerReferralFeature = "UPDATE_SELLER_DISPUTE"
+	E_PARTNER_REFERRAL_FEATURE_ACCESS_MERCHANT_INFORMATION E_PartnerReferralFeature = "ACCESS_MERCHANT_INFORMATION"
+	E_PARTNER_REFERRAL_FEATURE_VAULT                       E_PartnerReferralFeature = "VAULT"
+	E_PARTNER_REFERRAL_FEATURE_BILLING_AGREEMENT           E_PartnerReferralFeature = "BILLING_AGREEMENT"
+	E_PARTNER_REFERRAL_FEATURE_FUTURE_PAYMENT              E_PartnerReferralFeature = "FUTURE_PAYMENT"
+	E_PARTNER_REFERRAL_FEATURE_TRACKING_SHIPMENT_READWRITE E_PartnerReferralFeature = "TRACKING_SHIPMENT_READWRITE"
+)
+
+// https://developer.paypal.com/docs/api/partner-referrals/v2/#definition-third_party_details
+type PartnerThirdPartyDetails struct {
+	Features []E_PartnerReferralFeature `json:"features"`
+}
+
+// https://developer.paypal.com/docs/api/partner-referrals/v2/#definition-rest_api_integration
+type PartnerRestAPIIntegration struct {
+	IntegrationMethod string                    `json:"integration_method"` // PAYPAL, BRAINTREE
+	IntegrationType   string                    `json:"integration_type"`   // FIRST_PARTY, THIRD_PARTY
+	ThirdPartyDetails *PartnerThirdPartyDetails `json:"third_party_details,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/partner-referrals/v2/#definition-operation
+type PartnerReferralOperation struct {
+	Operation                string                           `json:"operation"` // API_INTEGRATION, BANK_ADDITION, BILLING_AGREEMENT, CONTEXTUAL_MARKETING_CONSENT
+	APIIntegrationPreference *PartnerAPIIntegrationPreference `json:"api_integration_preference,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/partner-referrals/v2/#definition-integration_details
+type PartnerAPIIntegrationPreference struct {
+	RestAPIIntegration *PartnerRestAPIIntegration `json:"rest_api_integration,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/partner-referrals/v2/#definition-legal_consent
+type PartnerLegalConsent struct {
+	Type    string `json:"type"` // SHARE_DATA_CONSENT
+	Granted bool   `json:"granted"`
+}
+
+// https://developer.paypal.com/docs/api/partner-referrals/v2/#definition-partner_config_override
+type PartnerConfigOverride struct {
+	PartnerLogoUrl       string `json:"partner_logo_url,omitempty"`
+	ReturnUrl            string `json:"return_url,omitempty"` // 卖家完成授权后的回调地址
+	ReturnUrlDescription string `json:"return_url_description,omitempty"`
+	ActionRenewalUrl     string `json:"action_renewal_url,omitempty"` // action_url 过期后的续期地址
+	ShowAddCreditCard    bool   `json:"show_add_credit_card,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/partner-referrals/v2/#definition-referral_data
+type PartnerReferralData struct {
+	Email                 string                      `json:"email,omitempty"`
+	PreferredLanguageCode string                      `json:"preferred_language_code,omitempty"` // eg: en-US
+	TrackingID            string                      `json:"tracking_id,omitempty"`             // len<=127, 平台侧的卖家 ID
+	PartnerConfigOverride *PartnerConfigOverride      `json:"partner_config_override,omitempty"`
+	Operations            []*PartnerReferralOperation `json:"operations"`
+	Products              []string                    `json:"products,omitempty"` // EXPRESS_CHECKOUT, PPCP, PAYMENT_METHODS, ADVANCED_VAULTING
+	LegalConsents         []*PartnerLegalConsent      `json:"legal_consents"`
+}
+
+// NewPartnerReferralReq 返回以 REST API 第三方集成方式接入、授权 features 的推荐请求
+func NewPartnerReferralReq(trackingID, returnUrl string, features ...E_PartnerReferralFeature) *PartnerReferralData {
+	op := &PartnerReferralOperation{
+		Operation: "API_INTEGRATION",
+		APIIntegrationPreference: &PartnerAPIIntegrationPreference{
+			RestAPIIntegration: &PartnerRestAPIIntegration{
+				IntegrationMethod: "PAYPAL",
+				IntegrationType:   "THIRD_PARTY",
+				ThirdPartyDetails: &PartnerThirdPartyDetails{Features: features},
+			},
+		},
+	}
+	return &PartnerReferralData{
+		TrackingID:            trackingID,
+		PartnerConfigOverride: &PartnerConfigOverride{ReturnUrl: returnUrl},
+		Operations:            []*PartnerReferralOperation{op},
+		Products:              []string{"EXPRESS_CHECKOUT"},
+		LegalConsents:         []*PartnerLegalConsent{{Type: "SHARE_DATA_CONSENT", Granted: true}},
+	}
+}
+
+// https://developer.paypal.com/docs/api/partner-referrals/v2/#partner-referrals_create
+type PartnerReferral struct {
+	PartnerReferralID string               `json:"partner_referral_id,omitempty"`
+	SubmitterPayerID  string               `json:"submitter_payer_id,omitempty"`
+	ReferralData      *PartnerReferralData `json:"referral_data,omitempty"`
+	Links             []*LinkDescription   `json:"links,omitempty"` // rel=action_url 为卖家的注册/授权链接
+}
+
+// https://developer.paypal.com/docs/api/partner-referrals/v1/#definition-capability
+type SellerCapability struct {
+	Name   string `json:"name"`   // eg: CUSTOM_CARD_PROCESSING
+	Status string `json:"status"` // ACTIVE, INACTIVE
+}
+
+// https://developer.paypal.com/docs/api/partner-referrals/v1/#definition-product
+type SellerProduct struct {
+	Name          string   `json:"name"`                     // eg: EXPRESS_CHECKOUT
+	VettingStatus string   `json:"vetting_status,omitempty"` // SUBSCRIBED, APPROVED, PENDING, DENIED, ...
+	Capabilities  []string `json:"capabilities,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/partner-referrals/v1/#definition-oauth_integration
+type SellerOauthIntegration struct {
+	IntegrationType   string `json:"integration_type,omitempty"`
+	IntegrationMethod string `json:"integration_method,omitempty"`
+	OauthThirdParty   []*struct {
+		PartnerClientID  string   `json:"partner_client_id,omitempty"`
+		MerchantClientID string   `json:"merchant_client_id,omitempty"`
+		Scopes           []string `json:"scopes,omitempty"`
+	} `json:"oauth_third_party,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/partner-referrals/v1/#merchant-integration_status
+type SellerStatus struct {
+	MerchantID            string                    `json:"merchant_id"`
+	TrackingID            string                    `json:"tracking_id,omitempty"`
+	Products              []*SellerProduct          `json:"products,omitempty"`
+	Capabilities          []*SellerCapability       `json:"capabilities,omitempty"`
+	PaymentsReceivable    bool                      `json:"payments_receivable"`     // false 时卖家账户受限, 无法收款
+	PrimaryEmailConfirmed bool                      `json:"primary_email_confirmed"` // false 时卖家需要先确认邮箱
+	PrimaryEmail          string                    `json:"primary_email,omitempty"`
+	OauthIntegrations     []*SellerOauthIntegration `json:"oauth_integrations,omitempty"`
+}
+
+// CanReceivePayments 卖家是否已完成接入并可以收款
+func (s *SellerStatus) CanReceivePayments() bool {
+	return s.PaymentsReceivable && s.PrimaryEmailConfirmed && len(s.OauthIntegrations) > 0
+}
+
+// AuthAssertion 返回代卖家调用 API 时使用的 PayPal-Auth-Assertion, 即不签名的 JWT(alg=none)。
+// clientID 为平台的 client id, merchantPayerID 为卖家的 merchant_id(payer_id)。
+func AuthAssertion(clientID, merchantPayerID string) string {
+	header, _ := json.Marshal(map[string]string{"alg": "none"})
+	payload, _ := json.Marshal(map[string]string{"iss": clientID, "payer_id": merchantPayerID})
+	enc := base64.RawURLEncoding
+	return fmt.Sprintf("%s.%s.", enc.EncodeToString(header), enc.EncodeToString(payload))
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v2/customer/partner-referrals
+// Create partner referral
+// 创建卖家推荐链接
+*/
+
+func (c *Client) CreatePartnerReferral(q *PartnerReferralData) (*PartnerReferral, error) {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, K_PARTNER_REFERRAL_API), q)
+	rsp := &PartnerReferral{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v2/customer/partner-referrals/ZjcyODU4ZWYtYTA1OC00ODIwLTk2M2EtOTZkZWQ4NmQwYzI3
+// Show referral data
+// 查询推荐数据, referralID 为创建时 rel=self 链接的最后一段。
+*/
+
+func (c *Client) ShowPartnerReferral(referralID string) (*PartnerReferral, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.APIBase, K_PARTNER_REFERRAL_API, referralID), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &PartnerReferral{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/customer/partners/{partner_id}/merchant-integrations/{merchant_id}
+// Show seller status
+// 查询卖家接入状态, partnerID 为平台自己的 merchant_id, 需使用平台自身的 client 而不是 OnBehalfOf 返回的 client。
+*/
+
+func (c *Client) ShowSellerStatus(partnerID, merchantID string) (*SellerStatus, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s/merchant-integrations/%s", c.APIBase, K_PARTNER_API, partnerID, merchantID), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &SellerStatus{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/customer/partners/{partner_id}/merchant-integrations?tracking_id=xxx
+// List seller tracking information
+// 用 tracking_id 查询卖家的 merchant_id
+*/
+
+func (c *Client) FindSellerByTrackingID(partnerID, trackingID string) (*SellerStatus, error) {
+	v := url.Values{}
+	v.Set("tracking_id", trackingID)
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s/merchant-integrations?%s", c.APIBase, K_PARTNER_API, partnerID, v.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &SellerStatus{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
@@ -0,0 +1,286 @@
+package paypalsdk
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	K_PAYMENT_AUTHORIZATION_API = "/v2/payments/authorizations"
+	K_PAYMENT_CAPTURE_API       = "/v2/payments/captures"
+	K_PAYMENT_REFUND_API        = "/v2/payments/refunds"
+)
+
+// https://developer.paypal.com/docs/api/payments/v2/#definition-authorization_status
+type E_AuthorizationStatus string
+
+const (
+	E_AUTHORIZATION_STATUS_CREATED            E_AuthorizationStatus = "CREATED"            // 授权已创建，可以捕获。
+	E_AUTHORIZATION_STATUS_CAPTURED           E_AuthorizationStatus = "CAPTURED"           // 授权金额已全部捕获。
+	E_AUTHORIZATION_STATUS_DENIED             E_AuthorizationStatus = "DENIED"             // PayPal 无法授权付款。
+	E_AUTHORIZATION_STATUS_EXPIRED            E_AuthorizationStatus = "EXPIRED"            // 授权已过期，无法捕获。
+	E_AUTHORIZATION_STATUS_PARTIALLY_CAPTURED E_AuthorizationStatus = "PARTIALLY_CAPTURED" // 授权金额已部分捕获。
+	E_AUTHORIZATION_STATUS_VOIDED             E_AuthorizationStatus = "VOIDED"             // 授权已作废，无法再捕获。
+	E_AUTHORIZATION_STATUS_PENDING            E_AuthorizationStatus = "PENDING"            // 授权待处理，见 status_details。
+)
+
+// https://developer.paypal.com/docs/api/payments/v2/#definition-capture_status
+type E_CaptureStatus string
+
+const (
+	E_CAPTURE_STATUS_COMPLETED          E_CaptureStatus = "COMPLETED"          // 资金已存入收款人的 PayPal 账户。
+	E_CAPTURE_STATUS_DECLINED           E_CaptureStatus = "DECLINED"           // 资金无法捕获。
+	E_CAPTURE_STATUS_PARTIALLY_REFUNDED E_CaptureStatus = "PARTIALLY_REFUNDED" // 已部分退款给付款人。
+	E_CAPTURE_STATUS_PENDING            E_CaptureStatus = "PENDING"            // 资金尚未存入收款人账户，见 status_details。
+	E_CAPTURE_STATUS_REFUNDED           E_CaptureStatus = "REFUNDED"           // 已全额退款给付款人。
+	E_CAPTURE_STATUS_FAILED             E_CaptureStatus = "FAILED"             // 资金无法捕获。
+)
+
+// https://developer.paypal.com/docs/api/payments/v2/#definition-refund_status
+type E_RefundStatus string
+
+const (
+	E_REFUND_STATUS_CANCELLED E_RefundStatus = "CANCELLED" // 退款已取消。
+	E_REFUND_STATUS_FAILED    E_RefundStatus = "FAILED"    // 退款失败。
+	E_REFUND_STATUS_PENDING   E_RefundStatus = "PENDING"   // 退款处理中，见 status_details。
+	E_REFUND_STATUS_COMPLETED E_RefundStatus = "COMPLETED" // 资金已退回付款人账户。
+)
+
+// https://developer.paypal.com/docs/api/payments/v2/#definition-status_details
+type StatusDetails struct {
+	Reason string `json:"reason,omitempty"` // 只读, 状态为 PENDING/DENIED 等时的原因。
+}
+
+// https://developer.paypal.com/docs/api/payments/v2/#definition-seller_protection
+type SellerProtection struct {
+	Status            string   `json:"status,omitempty"`             // ELIGIBLE, PARTIALLY_ELIGIBLE, NOT_ELIGIBLE
+	DisputeCategories []string `json:"dispute_categories,omitempty"` // ITEM_NOT_RECEIVED, UNAUTHORIZED_TRANSACTION
+}
+
+// https://developer.paypal.com/docs/api/payments/v2/#definition-seller_receivable_breakdown
+type SellerReceivableBreakdown struct {
+	GrossAmount      *Money         `json:"gross_amount"`
+	PaypalFee        *Money         `json:"paypal_fee,omitempty"`
+	NetAmount        *Money         `json:"net_amount,omitempty"`
+	ReceivableAmount *Money         `json:"receivable_amount,omitempty"`
+	PlatformFees     []*PlatformFee `json:"platform_fees,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payments/v2/#definition-seller_payable_breakdown
+type SellerPayableBreakdown struct {
+	GrossAmount         *Money         `json:"gross_amount"`
+	PaypalFee           *Money         `json:"paypal_fee,omitempty"`
+	NetAmount           *Money         `json:"net_amount,omitempty"`
+	PlatformFees        []*PlatformFee `json:"platform_fees,omitempty"`
+	TotalRefundedAmount *Money         `json:"total_refunded_amount,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payments/v2/#definition-platform_fee
+type PlatformFee struct {
+	Amount *Money `json:"amount"`
+}
+
+// https://developer.paypal.com/docs/api/payments/v2/#definition-authorization
+type Authorization struct {
+	ID               string                `json:"id,omitempty"`
+	Status           E_AuthorizationStatus `json:"status,omitempty"`
+	StatusDetails    *StatusDetails        `json:"status_details,omitempty"`
+	Amount           *Money                `json:"amount,omitempty"`
+	InvoiceID        string                `json:"invoice_id,omitempty"`
+	CustomID         string                `json:"custom_id,omitempty"`
+	SellerProtection *SellerProtection     `json:"seller_protection,omitempty"`
+	ExpirationTime   time.Time             `json:"expiration_time,omitempty"` // 只读
+	CreateTime       time.Time             `json:"create_time,omitempty"`     // 只读
+	UpdateTime       time.Time             `json:"update_time,omitempty"`     // 只读
+	Links            []*LinkDescription    `json:"links,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payments/v2/#definition-capture
+type Capture struct {
+	ID                        string                     `json:"id,omitempty"`
+	Status                    E_CaptureStatus            `json:"status,omitempty"`
+	StatusDetails             *StatusDetails             `json:"status_details,omitempty"`
+	Amount                    *Money                     `json:"amount,omitempty"`
+	InvoiceID                 string                     `json:"invoice_id,omitempty"`
+	CustomID                  string                     `json:"custom_id,omitempty"`
+	SellerProtection          *SellerProtection          `json:"seller_protection,omitempty"`
+	FinalCapture              bool                       `json:"final_capture,omitempty"`
+	SellerReceivableBreakdown *SellerReceivableBreakdown `json:"seller_receivable_breakdown,omitempty"`
+	DisbursementMode          string                     `json:"disbursement_mode,omitempty"` // INSTANT, DELAYED. Default: INSTANT.
+	CreateTime                time.Time                  `json:"create_time,omitempty"`       // 只读
+	UpdateTime                time.Time                  `json:"update_time,omitempty"`       // 只读
+	Links                     []*LinkDescription         `json:"links,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payments/v2/#definition-refund
+type Refund struct {
+	ID                     string                  `json:"id,omitempty"`
+	Status                 E_RefundStatus          `json:"status,omitempty"`
+	StatusDetails          *StatusDetails          `json:"status_details,omitempty"`
+	Amount                 *Money                  `json:"amount,omitempty"`
+	InvoiceID              string                  `json:"invoice_id,omitempty"`
+	NoteToPayer            string                  `json:"note_to_payer,omitempty"`
+	SellerPayableBreakdown *SellerPayableBreakdown `json:"seller_payable_breakdown,omitempty"`
+	CreateTime             time.Time               `json:"create_time,omitempty"` // 只读
+	UpdateTime             time.Time               `json:"update_time,omitempty"` // 只读
+	Links                  []*LinkDescription      `json:"links,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payments/v2/#authorizations_capture
+type CaptureAuthorizationReq struct {
+	Amount         *Money `json:"amount,omitempty"` // 不传则捕获全部授权金额。
+	InvoiceID      string `json:"invoice_id,omitempty"`
+	FinalCapture   bool   `json:"final_capture,omitempty"`   // 是否为最后一次捕获，为 true 时剩余授权金额将被释放。
+	NoteToPayer    string `json:"note_to_payer,omitempty"`   // len<=255
+	SoftDescriptor string `json:"soft_descriptor,omitempty"` // len<=22, 出现在付款人卡对账单上。
+}
+
+// https://developer.paypal.com/docs/api/payments/v2/#authorizations_reauthorize
+type ReauthorizeReq struct {
+	Amount *Money `json:"amount,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payments/v2/#captures_refund
+type RefundCaptureReq struct {
+	Amount      *Money `json:"amount,omitempty"` // 不传则全额退款。
+	InvoiceID   string `json:"invoice_id,omitempty"`
+	NoteToPayer string `json:"note_to_payer,omitempty"` // len<=255
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v2/payments/authorizations/0VF52814937998046
+// Show details for authorized payment
+// 查询授权详情
+*/
+
+func (c *Client) ShowAuthorization(authID string) (*Authorization, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.APIBase, K_PAYMENT_AUTHORIZATION_API, authID), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &Authorization{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v2/payments/authorizations/0VF52814937998046/capture
+// Capture authorized payment
+// 触发webhook： PAYMENT.CAPTURE.COMPLETED
+// 捕获授权金额, requestID 非空时作为 PayPal-Request-Id 保证幂等。
+*/
+
+func (c *Client) CaptureAuthorization(authID string, q *CaptureAuthorizationReq, requestID string) (*Capture, error) {
+	if q == nil {
+		q = &CaptureAuthorizationReq{}
+	}
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/capture", c.APIBase, K_PAYMENT_AUTHORIZATION_API, authID), q)
+	rsp := &Capture{}
+	if err != nil {
+		return rsp, err
+	}
+	req.Header.Add("Prefer", "return=representation")
+	if requestID != "" {
+		req.Header.Set("PayPal-Request-Id", requestID)
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v2/payments/authorizations/0VF52814937998046/reauthorize
+// Reauthorize authorized payment
+// 重新授权, 只能在原授权的 3 天有效期过后、29 天内进行。
+*/
+
+func (c *Client) ReauthorizeAuthorization(authID string, q *ReauthorizeReq, requestID string) (*Authorization, error) {
+	if q == nil {
+		q = &ReauthorizeReq{}
+	}
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/reauthorize", c.APIBase, K_PAYMENT_AUTHORIZATION_API, authID), q)
+	rsp := &Authorization{}
+	if err != nil {
+		return rsp, err
+	}
+	req.Header.Add("Prefer", "return=representation")
+	if requestID != "" {
+		req.Header.Set("PayPal-Request-Id", requestID)
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v2/payments/authorizations/0VF52814937998046/void
+// Void authorized payment
+// 触发webhook： PAYMENT.AUTHORIZATION.VOIDED
+// 作废授权, 已全部捕获的授权无法作废。
+*/
+
+func (c *Client) VoidAuthorization(authID string) (*Authorization, error) {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/void", c.APIBase, K_PAYMENT_AUTHORIZATION_API, authID), nil)
+	rsp := &Authorization{}
+	if err != nil {
+		return rsp, err
+	}
+	// 不加该 header 时 PayPal 返回 204 No Content
+	req.Header.Add("Prefer", "return=representation")
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v2/payments/captures/2GG279541U471931P
+// Show captured payment details
+// 查询捕获详情
+*/
+
+func (c *Client) ShowCapture(captureID string) (*Capture, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.APIBase, K_PAYMENT_CAPTURE_API, captureID), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &Capture{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v2/payments/captures/2GG279541U471931P/refund
+// Refund captured payment
+// 触发webhook： PAYMENT.CAPTURE.REFUNDED
+// 退款, q.Amount 为空时全额退款, 否则部分退款。requestID 非空时作为 PayPal-Request-Id 保证幂等。
+*/
+
+func (c *Client) RefundCapture(captureID string, q *RefundCaptureReq, requestID string) (*Refund, error) {
+	if q == nil {
+		q = &RefundCaptureReq{}
+	}
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/refund", c.APIBase, K_PAYMENT_CAPTURE_API, captureID), q)
+	rsp := &Refund{}
+	if err != nil {
+		return rsp, err
+	}
+	req.Header.Add("Prefer", "return=representation")
+	if requestID != "" {
+		req.Header.Set("PayPal-Request-Id", requestID)
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v2/payments/refunds/1JU08902781691411
+// Show refund details
+// 查询退款详情
+*/
+
+func (c *Client) ShowRefund(refundID string) (*Refund, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.APIBase, K_PAYMENT_REFUND_API, refundID), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &Refund{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
@@ -0,0 +1,184 @@
+package paypalsdk
+
+import "fmt"
+
+const (
+	K_PAYOUT_API      = "/v1/payments/payouts"
+	K_PAYOUT_ITEM_API = "/v1/payments/payouts-item"
+)
+
+// https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#definition-recipient_type
+type E_PayoutRecipientType string
+
+const (
+	E_PAYOUT_RECIPIENT_TYPE_EMAIL     E_PayoutRecipientType = "EMAIL"     // receiver 为收款人邮箱。
+	E_PAYOUT_RECIPIENT_TYPE_PHONE     E_PayoutRecipientType = "PHONE"     // receiver 为收款人手机号。
+	E_PAYOUT_RECIPIENT_TYPE_PAYPAL_ID E_PayoutRecipientType = "PAYPAL_ID" // receiver 为收款人的 PayPal 账户 ID (payer_id)。
+)
+
+// https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#definition-batch_enum
+type E_PayoutBatchStatus string
+
+const (
+	E_PAYOUT_BATCH_STATUS_DENIED     E_PayoutBatchStatus = "DENIED"     // 批次被拒绝，不会处理任何条目。
+	E_PAYOUT_BATCH_STATUS_PENDING    E_PayoutBatchStatus = "PENDING"    // 批次已接收，等待处理。
+	E_PAYOUT_BATCH_STATUS_PROCESSING E_PayoutBatchStatus = "PROCESSING" // 批次处理中。
+	E_PAYOUT_BATCH_STATUS_SUCCESS    E_PayoutBatchStatus = "SUCCESS"    // 批次已处理完成，各条目状态见 transaction_status。
+	E_PAYOUT_BATCH_STATUS_CANCELED   E_PayoutBatchStatus = "CANCELED"   // 批次已取消。
+)
+
+// https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#definition-payout_transaction_status
+type E_PayoutItemStatus string
+
+const (
+	E_PAYOUT_ITEM_STATUS_SUCCESS   E_PayoutItemStatus = "SUCCESS"   // 资金已到账。
+	E_PAYOUT_ITEM_STATUS_FAILED    E_PayoutItemStatus = "FAILED"    // 付款失败。
+	E_PAYOUT_ITEM_STATUS_PENDING   E_PayoutItemStatus = "PENDING"   // 付款处理中。
+	E_PAYOUT_ITEM_STATUS_UNCLAIMED E_PayoutItemStatus = "UNCLAIMED" // 收款人尚未领取, 30 天未领取将退回。
+	E_PAYOUT_ITEM_STATUS_RETURNED  E_PayoutItemStatus = "RETURNED"  // 收款人 30 天内未领取，资金已退回。
+	E_PAYOUT_ITEM_STATUS_ONHOLD    E_PayoutItemStatus = "ONHOLD"    // 付款被 PayPal 冻结审核中。
+	E_PAYOUT_ITEM_STATUS_BLOCKED   E_PayoutItemStatus = "BLOCKED"   // 付款被拦截。
+	E_PAYOUT_ITEM_STATUS_REFUNDED  E_PayoutItemStatus = "REFUNDED"  // 收款人已退款给付款人。
+	E_PAYOUT_ITEM_STATUS_REVERSED  E_PayoutItemStatus = "REVERSED"  // 付款已撤销。
+)
+
+// https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#definition-sender_batch_header
+type SenderBatchHeader struct {
+	SenderBatchID string                `json:"sender_batch_id,omitempty"` // len<=256, 商户生成的批次 ID, 30 天内重复使用将被拒绝, 用于保证幂等。
+	RecipientType E_PayoutRecipientType `json:"recipient_type,omitempty"`  // 条目未指定 recipient_type 时使用。
+	EmailSubject  string                `json:"email_subject,omitempty"`   // len<=255
+	EmailMessage  string                `json:"email_message,omitempty"`   // len<=1000
+}
+
+// https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#definition-payout_item
+type PayoutItem struct {
+	RecipientType   E_PayoutRecipientType `json:"recipient_type,omitempty"`
+	Amount          *Currency             `json:"amount"`
+	Note            string                `json:"note,omitempty"`             // len<=4000
+	Receiver        string                `json:"receiver"`                   // 邮箱、手机号或 PayPal ID, 取决于 recipient_type。
+	SenderItemID    string                `json:"sender_item_id,omitempty"`   // len<=63, 商户生成的条目 ID。
+	RecipientWallet string                `json:"recipient_wallet,omitempty"` // PAYPAL, VENMO. Default: PAYPAL.
+}
+
+// https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts_post
+type CreatePayoutReq struct {
+	SenderBatchHeader *SenderBatchHeader `json:"sender_batch_header"`
+	Items             []*PayoutItem      `json:"items"` // 1<=len<=15000
+}
+
+// https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#definition-payout_batch_header
+type PayoutBatchHeader struct {
+	PayoutBatchID     string              `json:"payout_batch_id,omitempty"`
+	BatchStatus       E_PayoutBatchStatus `json:"batch_status,omitempty"`
+	TimeCreated       string              `json:"time_created,omitempty"`   // 只读
+	TimeCompleted     string              `json:"time_completed,omitempty"` // 只读
+	TimeClosed        string              `json:"time_closed,omitempty"`    // 只读
+	SenderBatchHeader *SenderBatchHeader  `json:"sender_batch_header,omitempty"`
+	FundingSource     string              `json:"funding_source,omitempty"` // BALANCE
+	Amount            *Currency           `json:"amount,omitempty"`
+	Fees              *Currency           `json:"fees,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#definition-error
+type PayoutItemError struct {
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message,omitempty"`
+	DebugID string `json:"debug_id,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#definition-payout_item_detail
+type PayoutItemDetail struct {
+	PayoutItemID      string             `json:"payout_item_id,omitempty"`
+	TransactionID     string             `json:"transaction_id,omitempty"`
+	ActivityID        string             `json:"activity_id,omitempty"`
+	TransactionStatus E_PayoutItemStatus `json:"transaction_status,omitempty"`
+	PayoutItemFee     *Currency          `json:"payout_item_fee,omitempty"`
+	PayoutBatchID     string             `json:"payout_batch_id,omitempty"`
+	SenderBatchID     string             `json:"sender_batch_id,omitempty"`
+	PayoutItem        *PayoutItem        `json:"payout_item,omitempty"`
+	TimeProcessed     string             `json:"time_processed,omitempty"` // 只读
+	Errors            *PayoutItemError   `json:"errors,omitempty"`
+	Links             []*Link            `json:"links,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#definition-payout_batch
+type PayoutBatch struct {
+	BatchHeader *PayoutBatchHeader  `json:"batch_header"`
+	Items       []*PayoutItemDetail `json:"items,omitempty"`
+	TotalItems  int                 `json:"total_items,omitempty"`
+	TotalPages  int                 `json:"total_pages,omitempty"`
+	Links       []*Link             `json:"links,omitempty"`
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/payments/payouts
+// Create batch payout
+// 触发webhook： PAYMENT.PAYOUTSBATCH.PROCESSING / PAYMENT.PAYOUTSBATCH.SUCCESS / PAYMENT.PAYOUTSBATCH.DENIED
+// 批量付款, 以 sender_batch_id 保证幂等, 返回的 batch_header 中只有 payout_batch_id 和 batch_status。
+*/
+
+func (c *Client) CreatePayout(q *CreatePayoutReq) (*PayoutBatch, error) {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, K_PAYOUT_API), q)
+	rsp := &PayoutBatch{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/payments/payouts/FYXMPQTX4JC9N?page=1&page_size=1000&total_required=true
+// Show payout batch details
+// 查询批次详情, page 从 1 开始, pageSize 取值 [1, 1000], 为 0 时使用 PayPal 默认值。
+*/
+
+func (c *Client) ShowPayoutBatch(batchID string, page, pageSize int) (*PayoutBatch, error) {
+	url := fmt.Sprintf("%s%s/%s?total_required=true", c.APIBase, K_PAYOUT_API, batchID)
+	if page > 0 {
+		url = fmt.Sprintf("%s&page=%d", url, page)
+	}
+	if pageSize > 0 {
+		url = fmt.Sprintf("%s&page_size=%d", url, pageSize)
+	}
+	req, err := c.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &PayoutBatch{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/payments/payouts-item/8AELMXH8UB2P8
+// Show payout item details
+// 查询付款条目详情
+*/
+
+func (c *Client) ShowPayoutItem(itemID string) (*PayoutItemDetail, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.APIBase, K_PAYOUT_ITEM_API, itemID), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &PayoutItemDetail{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/payments/payouts-item/8AELMXH8UB2P8/cancel
+// Cancel unclaimed payout item
+// 触发webhook： PAYMENT.PAYOUTS-ITEM.CANCELED
+// 取消未领取的付款条目, 只有 UNCLAIMED 状态可以取消, 资金退回付款人账户。
+*/
+
+func (c *Client) CancelPayoutItem(itemID string) (*PayoutItemDetail, error) {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/cancel", c.APIBase, K_PAYOUT_ITEM_API, itemID), nil)
+	rsp := &PayoutItemDetail{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
@@ -0,0 +1,67 @@
+// Package paypalmock provides mocks of the interfaces of paypalsdk (SubscriptionsAPI, WebhooksAPI, ...) to stub
+// PayPal per call in service tests:
+//
+//	m := &paypalmock.SubscriptionsAPI{
+//		ShowSubscriptionDetailsFunc: func(subID string) (*paypalsdk.Subscription, error) {
+//			return &paypalsdk.Subscription{ID: subID, Status: paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE}, nil
+//		},
+//	}
+//	svc := NewBillingService(m) // takes a paypalsdk.SubscriptionsAPI
+//	...
+//	calls := m.CallsTo("ShowSubscriptionDetails")
+//
+// A method whose Func is not set returns an error wrapping ErrNotStubbed. The mocks are generated from api.go,
+// run go generate after changing the interfaces.
+package paypalmock
+
+//go:generate go run ./internal/gen
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrNotStubbed = errors.New("paypalmock: method not stubbed")
+
+func notStubbed(method string) error {
+	return fmt.Errorf("%w: %s", ErrNotStubbed, method)
+}
+
+// Call is a call received by a mock
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// calls records the calls of a mock, it is safe for concurrent use
+type calls struct {
+	mu   sync.Mutex
+	list []Call
+}
+
+func (c *calls) record(method string, args []interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.list = append(c.list, Call{Method: method, Args: args})
+}
+
+// Calls returns the calls received so far in order
+func (c *calls) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call(nil), c.list...)
+}
+
+// CallsTo returns the calls of method received so far in order
+func (c *calls) CallsTo(method string) []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var list []Call
+	for _, call := range c.list {
+		if call.Method == method {
+			list = append(list, call)
+		}
+	}
+	return list
+}
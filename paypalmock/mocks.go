@@ -0,0 +1,889 @@
+// Code generated by paypalmock/internal/gen from api.go. DO NOT EDIT.
+
+package paypalmock
+
+import (
+	"net/http"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+// SubscriptionsAPI is a mock of paypalsdk.SubscriptionsAPI, set the Func field of each method called
+type SubscriptionsAPI struct {
+	CreateSubscriptionFunc              func(q *paypalsdk.CreateSubscriptionReq) (*paypalsdk.Subscription, error)
+	UpdateSubscriptionFunc              func(subId string, op paypalsdk.E_PatchOp) (*paypalsdk.Subscription, error)
+	ShowSubscriptionDetailsFunc         func(subID string) (*paypalsdk.Subscription, error)
+	ActivateSubscriptionFunc            func(subId string, reason string) error
+	SuspendSubscriptionFunc             func(subId string, reason string) error
+	CancelSubscriptionFunc              func(subID string, reason string) error
+	ListTransactionsForSubscriptionFunc func(subID string, startTime string, endTime string) (*paypalsdk.ListTransactionRsp, error)
+
+	calls
+}
+
+var _ paypalsdk.SubscriptionsAPI = (*SubscriptionsAPI)(nil)
+
+func (m *SubscriptionsAPI) CreateSubscription(q *paypalsdk.CreateSubscriptionReq) (*paypalsdk.Subscription, error) {
+	m.record("CreateSubscription", []interface{}{q})
+	if m.CreateSubscriptionFunc == nil {
+		return nil, notStubbed("SubscriptionsAPI.CreateSubscription")
+	}
+	return m.CreateSubscriptionFunc(q)
+}
+
+func (m *SubscriptionsAPI) UpdateSubscription(subId string, op paypalsdk.E_PatchOp) (*paypalsdk.Subscription, error) {
+	m.record("UpdateSubscription", []interface{}{subId, op})
+	if m.UpdateSubscriptionFunc == nil {
+		return nil, notStubbed("SubscriptionsAPI.UpdateSubscription")
+	}
+	return m.UpdateSubscriptionFunc(subId, op)
+}
+
+func (m *SubscriptionsAPI) ShowSubscriptionDetails(subID string) (*paypalsdk.Subscription, error) {
+	m.record("ShowSubscriptionDetails", []interface{}{subID})
+	if m.ShowSubscriptionDetailsFunc == nil {
+		return nil, notStubbed("SubscriptionsAPI.ShowSubscriptionDetails")
+	}
+	return m.ShowSubscriptionDetailsFunc(subID)
+}
+
+func (m *SubscriptionsAPI) ActivateSubscription(subId string, reason string) error {
+	m.record("ActivateSubscription", []interface{}{subId, reason})
+	if m.ActivateSubscriptionFunc == nil {
+		return notStubbed("SubscriptionsAPI.ActivateSubscription")
+	}
+	return m.ActivateSubscriptionFunc(subId, reason)
+}
+
+func (m *SubscriptionsAPI) SuspendSubscription(subId string, reason string) error {
+	m.record("SuspendSubscription", []interface{}{subId, reason})
+	if m.SuspendSubscriptionFunc == nil {
+		return notStubbed("SubscriptionsAPI.SuspendSubscription")
+	}
+	return m.SuspendSubscriptionFunc(subId, reason)
+}
+
+func (m *SubscriptionsAPI) CancelSubscription(subID string, reason string) error {
+	m.record("CancelSubscription", []interface{}{subID, reason})
+	if m.CancelSubscriptionFunc == nil {
+		return notStubbed("SubscriptionsAPI.CancelSubscription")
+	}
+	return m.CancelSubscriptionFunc(subID, reason)
+}
+
+func (m *SubscriptionsAPI) ListTransactionsForSubscription(subID string, startTime string, endTime string) (*paypalsdk.ListTransactionRsp, error) {
+	m.record("ListTransactionsForSubscription", []interface{}{subID, startTime, endTime})
+	if m.ListTransactionsForSubscriptionFunc == nil {
+		return nil, notStubbed("SubscriptionsAPI.ListTransactionsForSubscription")
+	}
+	return m.ListTransactionsForSubscriptionFunc(subID, startTime, endTime)
+}
+
+// PlansAPI is a mock of paypalsdk.PlansAPI, set the Func field of each method called
+type PlansAPI struct {
+	CreatePlanFunc      func(q *paypalsdk.Plan) (*paypalsdk.Plan, error)
+	ListPlansFunc       func(productID string, page int, pageSize int) (*paypalsdk.PlanList, error)
+	ShowPlanDetailsFunc func(planID string) (*paypalsdk.Plan, error)
+	ActivatePlanFunc    func(planID string) error
+	DeactivatePlanFunc  func(planID string) error
+
+	calls
+}
+
+var _ paypalsdk.PlansAPI = (*PlansAPI)(nil)
+
+func (m *PlansAPI) CreatePlan(q *paypalsdk.Plan) (*paypalsdk.Plan, error) {
+	m.record("CreatePlan", []interface{}{q})
+	if m.CreatePlanFunc == nil {
+		return nil, notStubbed("PlansAPI.CreatePlan")
+	}
+	return m.CreatePlanFunc(q)
+}
+
+func (m *PlansAPI) ListPlans(productID string, page int, pageSize int) (*paypalsdk.PlanList, error) {
+	m.record("ListPlans", []interface{}{productID, page, pageSize})
+	if m.ListPlansFunc == nil {
+		return nil, notStubbed("PlansAPI.ListPlans")
+	}
+	return m.ListPlansFunc(productID, page, pageSize)
+}
+
+func (m *PlansAPI) ShowPlanDetails(planID string) (*paypalsdk.Plan, error) {
+	m.record("ShowPlanDetails", []interface{}{planID})
+	if m.ShowPlanDetailsFunc == nil {
+		return nil, notStubbed("PlansAPI.ShowPlanDetails")
+	}
+	return m.ShowPlanDetailsFunc(planID)
+}
+
+func (m *PlansAPI) ActivatePlan(planID string) error {
+	m.record("ActivatePlan", []interface{}{planID})
+	if m.ActivatePlanFunc == nil {
+		return notStubbed("PlansAPI.ActivatePlan")
+	}
+	return m.ActivatePlanFunc(planID)
+}
+
+func (m *PlansAPI) DeactivatePlan(planID string) error {
+	m.record("DeactivatePlan", []interface{}{planID})
+	if m.DeactivatePlanFunc == nil {
+		return notStubbed("PlansAPI.DeactivatePlan")
+	}
+	return m.DeactivatePlanFunc(planID)
+}
+
+// ProductsAPI is a mock of paypalsdk.ProductsAPI, set the Func field of each method called
+type ProductsAPI struct {
+	CreateProductFunc      func(q *paypalsdk.Product) (*paypalsdk.Product, error)
+	ListProductsFunc       func(page int, pageSize int) (*paypalsdk.ProductList, error)
+	ShowProductDetailsFunc func(productID string) (*paypalsdk.Product, error)
+
+	calls
+}
+
+var _ paypalsdk.ProductsAPI = (*ProductsAPI)(nil)
+
+func (m *ProductsAPI) CreateProduct(q *paypalsdk.Product) (*paypalsdk.Product, error) {
+	m.record("CreateProduct", []interface{}{q})
+	if m.CreateProductFunc == nil {
+		return nil, notStubbed("ProductsAPI.CreateProduct")
+	}
+	return m.CreateProductFunc(q)
+}
+
+func (m *ProductsAPI) ListProducts(page int, pageSize int) (*paypalsdk.ProductList, error) {
+	m.record("ListProducts", []interface{}{page, pageSize})
+	if m.ListProductsFunc == nil {
+		return nil, notStubbed("ProductsAPI.ListProducts")
+	}
+	return m.ListProductsFunc(page, pageSize)
+}
+
+func (m *ProductsAPI) ShowProductDetails(productID string) (*paypalsdk.Product, error) {
+	m.record("ShowProductDetails", []interface{}{productID})
+	if m.ShowProductDetailsFunc == nil {
+		return nil, notStubbed("ProductsAPI.ShowProductDetails")
+	}
+	return m.ShowProductDetailsFunc(productID)
+}
+
+// WebhooksAPI is a mock of paypalsdk.WebhooksAPI, set the Func field of each method called
+type WebhooksAPI struct {
+	CreateWebhookFunc          func(q *paypalsdk.CreateWebhookReq) (*paypalsdk.Webhook, error)
+	ListWebhooksFunc           func(anchor_type string) (*paypalsdk.WebhookList, error)
+	DeleteWebhookFunc          func(id string) error
+	UpdateWebhookFunc          func(id string, patches []paypalsdk.Patch) (*paypalsdk.Webhook, error)
+	VerifyWebhookSignatureFunc func(header http.Header, body []byte, webhookID string) (bool, error)
+	ListWebhookEventsFunc      func(q *paypalsdk.ListWebhookEventsReq) (*paypalsdk.EventList, error)
+	ShowWebhookEventFunc       func(eventID string) (*paypalsdk.Event, error)
+	ResendWebhookEventFunc     func(eventID string, webhookIDs []string) (*paypalsdk.Event, error)
+
+	calls
+}
+
+var _ paypalsdk.WebhooksAPI = (*WebhooksAPI)(nil)
+
+func (m *WebhooksAPI) CreateWebhook(q *paypalsdk.CreateWebhookReq) (*paypalsdk.Webhook, error) {
+	m.record("CreateWebhook", []interface{}{q})
+	if m.CreateWebhookFunc == nil {
+		return nil, notStubbed("WebhooksAPI.CreateWebhook")
+	}
+	return m.CreateWebhookFunc(q)
+}
+
+func (m *WebhooksAPI) ListWebhooks(anchor_type string) (*paypalsdk.WebhookList, error) {
+	m.record("ListWebhooks", []interface{}{anchor_type})
+	if m.ListWebhooksFunc == nil {
+		return nil, notStubbed("WebhooksAPI.ListWebhooks")
+	}
+	return m.ListWebhooksFunc(anchor_type)
+}
+
+func (m *WebhooksAPI) DeleteWebhook(id string) error {
+	m.record("DeleteWebhook", []interface{}{id})
+	if m.DeleteWebhookFunc == nil {
+		return notStubbed("WebhooksAPI.DeleteWebhook")
+	}
+	return m.DeleteWebhookFunc(id)
+}
+
+func (m *WebhooksAPI) UpdateWebhook(id string, patches []paypalsdk.Patch) (*paypalsdk.Webhook, error) {
+	m.record("UpdateWebhook", []interface{}{id, patches})
+	if m.UpdateWebhookFunc == nil {
+		return nil, notStubbed("WebhooksAPI.UpdateWebhook")
+	}
+	return m.UpdateWebhookFunc(id, patches)
+}
+
+func (m *WebhooksAPI) VerifyWebhookSignature(header http.Header, body []byte, webhookID string) (bool, error) {
+	m.record("VerifyWebhookSignature", []interface{}{header, body, webhookID})
+	if m.VerifyWebhookSignatureFunc == nil {
+		return false, notStubbed("WebhooksAPI.VerifyWebhookSignature")
+	}
+	return m.VerifyWebhookSignatureFunc(header, body, webhookID)
+}
+
+func (m *WebhooksAPI) ListWebhookEvents(q *paypalsdk.ListWebhookEventsReq) (*paypalsdk.EventList, error) {
+	m.record("ListWebhookEvents", []interface{}{q})
+	if m.ListWebhookEventsFunc == nil {
+		return nil, notStubbed("WebhooksAPI.ListWebhookEvents")
+	}
+	return m.ListWebhookEventsFunc(q)
+}
+
+func (m *WebhooksAPI) ShowWebhookEvent(eventID string) (*paypalsdk.Event, error) {
+	m.record("ShowWebhookEvent", []interface{}{eventID})
+	if m.ShowWebhookEventFunc == nil {
+		return nil, notStubbed("WebhooksAPI.ShowWebhookEvent")
+	}
+	return m.ShowWebhookEventFunc(eventID)
+}
+
+func (m *WebhooksAPI) ResendWebhookEvent(eventID string, webhookIDs []string) (*paypalsdk.Event, error) {
+	m.record("ResendWebhookEvent", []interface{}{eventID, webhookIDs})
+	if m.ResendWebhookEventFunc == nil {
+		return nil, notStubbed("WebhooksAPI.ResendWebhookEvent")
+	}
+	return m.ResendWebhookEventFunc(eventID, webhookIDs)
+}
+
+// SalesAPI is a mock of paypalsdk.SalesAPI, set the Func field of each method called
+type SalesAPI struct {
+	ShowSaleFunc   func(saleID string) (*paypalsdk.Sale, error)
+	RefundSaleFunc func(saleID string, q *paypalsdk.RefundSaleReq) (*paypalsdk.DetailedRefund, error)
+
+	calls
+}
+
+var _ paypalsdk.SalesAPI = (*SalesAPI)(nil)
+
+func (m *SalesAPI) ShowSale(saleID string) (*paypalsdk.Sale, error) {
+	m.record("ShowSale", []interface{}{saleID})
+	if m.ShowSaleFunc == nil {
+		return nil, notStubbed("SalesAPI.ShowSale")
+	}
+	return m.ShowSaleFunc(saleID)
+}
+
+func (m *SalesAPI) RefundSale(saleID string, q *paypalsdk.RefundSaleReq) (*paypalsdk.DetailedRefund, error) {
+	m.record("RefundSale", []interface{}{saleID, q})
+	if m.RefundSaleFunc == nil {
+		return nil, notStubbed("SalesAPI.RefundSale")
+	}
+	return m.RefundSaleFunc(saleID, q)
+}
+
+// PaymentsAPI is a mock of paypalsdk.PaymentsAPI, set the Func field of each method called
+type PaymentsAPI struct {
+	ShowAuthorizationFunc        func(authID string) (*paypalsdk.Authorization, error)
+	CaptureAuthorizationFunc     func(authID string, q *paypalsdk.CaptureAuthorizationReq, requestID string) (*paypalsdk.Capture, error)
+	ReauthorizeAuthorizationFunc func(authID string, q *paypalsdk.ReauthorizeReq, requestID string) (*paypalsdk.Authorization, error)
+	VoidAuthorizationFunc        func(authID string) (*paypalsdk.Authorization, error)
+	ShowCaptureFunc              func(captureID string) (*paypalsdk.Capture, error)
+	RefundCaptureFunc            func(captureID string, q *paypalsdk.RefundCaptureReq, requestID string) (*paypalsdk.Refund, error)
+	ShowRefundFunc               func(refundID string) (*paypalsdk.Refund, error)
+
+	calls
+}
+
+var _ paypalsdk.PaymentsAPI = (*PaymentsAPI)(nil)
+
+func (m *PaymentsAPI) ShowAuthorization(authID string) (*paypalsdk.Authorization, error) {
+	m.record("ShowAuthorization", []interface{}{authID})
+	if m.ShowAuthorizationFunc == nil {
+		return nil, notStubbed("PaymentsAPI.ShowAuthorization")
+	}
+	return m.ShowAuthorizationFunc(authID)
+}
+
+func (m *PaymentsAPI) CaptureAuthorization(authID string, q *paypalsdk.CaptureAuthorizationReq, requestID string) (*paypalsdk.Capture, error) {
+	m.record("CaptureAuthorization", []interface{}{authID, q, requestID})
+	if m.CaptureAuthorizationFunc == nil {
+		return nil, notStubbed("PaymentsAPI.CaptureAuthorization")
+	}
+	return m.CaptureAuthorizationFunc(authID, q, requestID)
+}
+
+func (m *PaymentsAPI) ReauthorizeAuthorization(authID string, q *paypalsdk.ReauthorizeReq, requestID string) (*paypalsdk.Authorization, error) {
+	m.record("ReauthorizeAuthorization", []interface{}{authID, q, requestID})
+	if m.ReauthorizeAuthorizationFunc == nil {
+		return nil, notStubbed("PaymentsAPI.ReauthorizeAuthorization")
+	}
+	return m.ReauthorizeAuthorizationFunc(authID, q, requestID)
+}
+
+func (m *PaymentsAPI) VoidAuthorization(authID string) (*paypalsdk.Authorization, error) {
+	m.record("VoidAuthorization", []interface{}{authID})
+	if m.VoidAuthorizationFunc == nil {
+		return nil, notStubbed("PaymentsAPI.VoidAuthorization")
+	}
+	return m.VoidAuthorizationFunc(authID)
+}
+
+func (m *PaymentsAPI) ShowCapture(captureID string) (*paypalsdk.Capture, error) {
+	m.record("ShowCapture", []interface{}{captureID})
+	if m.ShowCaptureFunc == nil {
+		return nil, notStubbed("PaymentsAPI.ShowCapture")
+	}
+	return m.ShowCaptureFunc(captureID)
+}
+
+func (m *PaymentsAPI) RefundCapture(captureID string, q *paypalsdk.RefundCaptureReq, requestID string) (*paypalsdk.Refund, error) {
+	m.record("RefundCapture", []interface{}{captureID, q, requestID})
+	if m.RefundCaptureFunc == nil {
+		return nil, notStubbed("PaymentsAPI.RefundCapture")
+	}
+	return m.RefundCaptureFunc(captureID, q, requestID)
+}
+
+func (m *PaymentsAPI) ShowRefund(refundID string) (*paypalsdk.Refund, error) {
+	m.record("ShowRefund", []interface{}{refundID})
+	if m.ShowRefundFunc == nil {
+		return nil, notStubbed("PaymentsAPI.ShowRefund")
+	}
+	return m.ShowRefundFunc(refundID)
+}
+
+// DisputesAPI is a mock of paypalsdk.DisputesAPI, set the Func field of each method called
+type DisputesAPI struct {
+	ListDisputesFunc           func(q *paypalsdk.ListDisputesReq) (*paypalsdk.DisputeList, error)
+	ShowDisputeDetailsFunc     func(disputeID string) (*paypalsdk.Dispute, error)
+	AcceptDisputeClaimFunc     func(disputeID string, q *paypalsdk.AcceptDisputeClaimReq) (*paypalsdk.DisputeSubsequentAction, error)
+	MakeDisputeOfferFunc       func(disputeID string, q *paypalsdk.MakeDisputeOfferReq) (*paypalsdk.DisputeSubsequentAction, error)
+	ProvideDisputeEvidenceFunc func(disputeID string, evidences []*paypalsdk.Evidence, files []*paypalsdk.EvidenceFile) (*paypalsdk.DisputeSubsequentAction, error)
+	AppealDisputeFunc          func(disputeID string, evidences []*paypalsdk.Evidence, files []*paypalsdk.EvidenceFile) (*paypalsdk.DisputeSubsequentAction, error)
+	SendDisputeMessageFunc     func(disputeID string, message string) (*paypalsdk.DisputeSubsequentAction, error)
+	EscalateDisputeFunc        func(disputeID string, note string) (*paypalsdk.DisputeSubsequentAction, error)
+
+	calls
+}
+
+var _ paypalsdk.DisputesAPI = (*DisputesAPI)(nil)
+
+func (m *DisputesAPI) ListDisputes(q *paypalsdk.ListDisputesReq) (*paypalsdk.DisputeList, error) {
+	m.record("ListDisputes", []interface{}{q})
+	if m.ListDisputesFunc == nil {
+		return nil, notStubbed("DisputesAPI.ListDisputes")
+	}
+	return m.ListDisputesFunc(q)
+}
+
+func (m *DisputesAPI) ShowDisputeDetails(disputeID string) (*paypalsdk.Dispute, error) {
+	m.record("ShowDisputeDetails", []interface{}{disputeID})
+	if m.ShowDisputeDetailsFunc == nil {
+		return nil, notStubbed("DisputesAPI.ShowDisputeDetails")
+	}
+	return m.ShowDisputeDetailsFunc(disputeID)
+}
+
+func (m *DisputesAPI) AcceptDisputeClaim(disputeID string, q *paypalsdk.AcceptDisputeClaimReq) (*paypalsdk.DisputeSubsequentAction, error) {
+	m.record("AcceptDisputeClaim", []interface{}{disputeID, q})
+	if m.AcceptDisputeClaimFunc == nil {
+		return nil, notStubbed("DisputesAPI.AcceptDisputeClaim")
+	}
+	return m.AcceptDisputeClaimFunc(disputeID, q)
+}
+
+func (m *DisputesAPI) MakeDisputeOffer(disputeID string, q *paypalsdk.MakeDisputeOfferReq) (*paypalsdk.DisputeSubsequentAction, error) {
+	m.record("MakeDisputeOffer", []interface{}{disputeID, q})
+	if m.MakeDisputeOfferFunc == nil {
+		return nil, notStubbed("DisputesAPI.MakeDisputeOffer")
+	}
+	return m.MakeDisputeOfferFunc(disputeID, q)
+}
+
+func (m *DisputesAPI) ProvideDisputeEvidence(disputeID string, evidences []*paypalsdk.Evidence, files []*paypalsdk.EvidenceFile) (*paypalsdk.DisputeSubsequentAction, error) {
+	m.record("ProvideDisputeEvidence", []interface{}{disputeID, evidences, files})
+	if m.ProvideDisputeEvidenceFunc == nil {
+		return nil, notStubbed("DisputesAPI.ProvideDisputeEvidence")
+	}
+	return m.ProvideDisputeEvidenceFunc(disputeID, evidences, files)
+}
+
+func (m *DisputesAPI) AppealDispute(disputeID string, evidences []*paypalsdk.Evidence, files []*paypalsdk.EvidenceFile) (*paypalsdk.DisputeSubsequentAction, error) {
+	m.record("AppealDispute", []interface{}{disputeID, evidences, files})
+	if m.AppealDisputeFunc == nil {
+		return nil, notStubbed("DisputesAPI.AppealDispute")
+	}
+	return m.AppealDisputeFunc(disputeID, evidences, files)
+}
+
+func (m *DisputesAPI) SendDisputeMessage(disputeID string, message string) (*paypalsdk.DisputeSubsequentAction, error) {
+	m.record("SendDisputeMessage", []interface{}{disputeID, message})
+	if m.SendDisputeMessageFunc == nil {
+		return nil, notStubbed("DisputesAPI.SendDisputeMessage")
+	}
+	return m.SendDisputeMessageFunc(disputeID, message)
+}
+
+func (m *DisputesAPI) EscalateDispute(disputeID string, note string) (*paypalsdk.DisputeSubsequentAction, error) {
+	m.record("EscalateDispute", []interface{}{disputeID, note})
+	if m.EscalateDisputeFunc == nil {
+		return nil, notStubbed("DisputesAPI.EscalateDispute")
+	}
+	return m.EscalateDisputeFunc(disputeID, note)
+}
+
+// InvoicesAPI is a mock of paypalsdk.InvoicesAPI, set the Func field of each method called
+type InvoicesAPI struct {
+	GenerateNextInvoiceNumberFunc func() (string, error)
+	CreateDraftInvoiceFunc        func(q *paypalsdk.Invoice) (*paypalsdk.Invoice, error)
+	ShowInvoiceDetailsFunc        func(invoiceID string) (*paypalsdk.Invoice, error)
+	ListInvoicesFunc              func(page int, pageSize int) (*paypalsdk.InvoiceList, error)
+	SearchInvoicesFunc            func(q *paypalsdk.SearchInvoiceReq, page int, pageSize int) (*paypalsdk.InvoiceList, error)
+	SendInvoiceFunc               func(invoiceID string, q *paypalsdk.InvoiceNotification) (*paypalsdk.LinkDescription, error)
+	RemindInvoiceFunc             func(invoiceID string, q *paypalsdk.InvoiceNotification) error
+	CancelInvoiceFunc             func(invoiceID string, q *paypalsdk.InvoiceNotification) error
+	RecordInvoicePaymentFunc      func(invoiceID string, q *paypalsdk.InvoicePaymentDetail) (string, error)
+	DeleteInvoicePaymentFunc      func(invoiceID string, paymentID string) error
+	RecordInvoiceRefundFunc       func(invoiceID string, q *paypalsdk.InvoiceRefundDetail) (string, error)
+	DeleteInvoiceRefundFunc       func(invoiceID string, refundID string) error
+	GenerateInvoiceQRCodeFunc     func(invoiceID string, q *paypalsdk.InvoiceQRCodeReq) (string, error)
+	ListInvoiceTemplatesFunc      func(page int, pageSize int) (*paypalsdk.InvoiceTemplateList, error)
+	CreateInvoiceTemplateFunc     func(q *paypalsdk.InvoiceTemplate) (*paypalsdk.InvoiceTemplate, error)
+	ShowInvoiceTemplateFunc       func(templateID string) (*paypalsdk.InvoiceTemplate, error)
+	UpdateInvoiceTemplateFunc     func(templateID string, q *paypalsdk.InvoiceTemplate) (*paypalsdk.InvoiceTemplate, error)
+	DeleteInvoiceTemplateFunc     func(templateID string) error
+
+	calls
+}
+
+var _ paypalsdk.InvoicesAPI = (*InvoicesAPI)(nil)
+
+func (m *InvoicesAPI) GenerateNextInvoiceNumber() (string, error) {
+	m.record("GenerateNextInvoiceNumber", []interface{}{})
+	if m.GenerateNextInvoiceNumberFunc == nil {
+		return "", notStubbed("InvoicesAPI.GenerateNextInvoiceNumber")
+	}
+	return m.GenerateNextInvoiceNumberFunc()
+}
+
+func (m *InvoicesAPI) CreateDraftInvoice(q *paypalsdk.Invoice) (*paypalsdk.Invoice, error) {
+	m.record("CreateDraftInvoice", []interface{}{q})
+	if m.CreateDraftInvoiceFunc == nil {
+		return nil, notStubbed("InvoicesAPI.CreateDraftInvoice")
+	}
+	return m.CreateDraftInvoiceFunc(q)
+}
+
+func (m *InvoicesAPI) ShowInvoiceDetails(invoiceID string) (*paypalsdk.Invoice, error) {
+	m.record("ShowInvoiceDetails", []interface{}{invoiceID})
+	if m.ShowInvoiceDetailsFunc == nil {
+		return nil, notStubbed("InvoicesAPI.ShowInvoiceDetails")
+	}
+	return m.ShowInvoiceDetailsFunc(invoiceID)
+}
+
+func (m *InvoicesAPI) ListInvoices(page int, pageSize int) (*paypalsdk.InvoiceList, error) {
+	m.record("ListInvoices", []interface{}{page, pageSize})
+	if m.ListInvoicesFunc == nil {
+		return nil, notStubbed("InvoicesAPI.ListInvoices")
+	}
+	return m.ListInvoicesFunc(page, pageSize)
+}
+
+func (m *InvoicesAPI) SearchInvoices(q *paypalsdk.SearchInvoiceReq, page int, pageSize int) (*paypalsdk.InvoiceList, error) {
+	m.record("SearchInvoices", []interface{}{q, page, pageSize})
+	if m.SearchInvoicesFunc == nil {
+		return nil, notStubbed("InvoicesAPI.SearchInvoices")
+	}
+	return m.SearchInvoicesFunc(q, page, pageSize)
+}
+
+func (m *InvoicesAPI) SendInvoice(invoiceID string, q *paypalsdk.InvoiceNotification) (*paypalsdk.LinkDescription, error) {
+	m.record("SendInvoice", []interface{}{invoiceID, q})
+	if m.SendInvoiceFunc == nil {
+		return nil, notStubbed("InvoicesAPI.SendInvoice")
+	}
+	return m.SendInvoiceFunc(invoiceID, q)
+}
+
+func (m *InvoicesAPI) RemindInvoice(invoiceID string, q *paypalsdk.InvoiceNotification) error {
+	m.record("RemindInvoice", []interface{}{invoiceID, q})
+	if m.RemindInvoiceFunc == nil {
+		return notStubbed("InvoicesAPI.RemindInvoice")
+	}
+	return m.RemindInvoiceFunc(invoiceID, q)
+}
+
+func (m *InvoicesAPI) CancelInvoice(invoiceID string, q *paypalsdk.InvoiceNotification) error {
+	m.record("CancelInvoice", []interface{}{invoiceID, q})
+	if m.CancelInvoiceFunc == nil {
+		return notStubbed("InvoicesAPI.CancelInvoice")
+	}
+	return m.CancelInvoiceFunc(invoiceID, q)
+}
+
+func (m *InvoicesAPI) RecordInvoicePayment(invoiceID string, q *paypalsdk.InvoicePaymentDetail) (string, error) {
+	m.record("RecordInvoicePayment", []interface{}{invoiceID, q})
+	if m.RecordInvoicePaymentFunc == nil {
+		return "", notStubbed("InvoicesAPI.RecordInvoicePayment")
+	}
+	return m.RecordInvoicePaymentFunc(invoiceID, q)
+}
+
+func (m *InvoicesAPI) DeleteInvoicePayment(invoiceID string, paymentID string) error {
+	m.record("DeleteInvoicePayment", []interface{}{invoiceID, paymentID})
+	if m.DeleteInvoicePaymentFunc == nil {
+		return notStubbed("InvoicesAPI.DeleteInvoicePayment")
+	}
+	return m.DeleteInvoicePaymentFunc(invoiceID, paymentID)
+}
+
+func (m *InvoicesAPI) RecordInvoiceRefund(invoiceID string, q *paypalsdk.InvoiceRefundDetail) (string, error) {
+	m.record("RecordInvoiceRefund", []interface{}{invoiceID, q})
+	if m.RecordInvoiceRefundFunc == nil {
+		return "", notStubbed("InvoicesAPI.RecordInvoiceRefund")
+	}
+	return m.RecordInvoiceRefundFunc(invoiceID, q)
+}
+
+func (m *InvoicesAPI) DeleteInvoiceRefund(invoiceID string, refundID string) error {
+	m.record("DeleteInvoiceRefund", []interface{}{invoiceID, refundID})
+	if m.DeleteInvoiceRefundFunc == nil {
+		return notStubbed("InvoicesAPI.DeleteInvoiceRefund")
+	}
+	return m.DeleteInvoiceRefundFunc(invoiceID, refundID)
+}
+
+func (m *InvoicesAPI) GenerateInvoiceQRCode(invoiceID string, q *paypalsdk.InvoiceQRCodeReq) (string, error) {
+	m.record("GenerateInvoiceQRCode", []interface{}{invoiceID, q})
+	if m.GenerateInvoiceQRCodeFunc == nil {
+		return "", notStubbed("InvoicesAPI.GenerateInvoiceQRCode")
+	}
+	return m.GenerateInvoiceQRCodeFunc(invoiceID, q)
+}
+
+func (m *InvoicesAPI) ListInvoiceTemplates(page int, pageSize int) (*paypalsdk.InvoiceTemplateList, error) {
+	m.record("ListInvoiceTemplates", []interface{}{page, pageSize})
+	if m.ListInvoiceTemplatesFunc == nil {
+		return nil, notStubbed("InvoicesAPI.ListInvoiceTemplates")
+	}
+	return m.ListInvoiceTemplatesFunc(page, pageSize)
+}
+
+func (m *InvoicesAPI) CreateInvoiceTemplate(q *paypalsdk.InvoiceTemplate) (*paypalsdk.InvoiceTemplate, error) {
+	m.record("CreateInvoiceTemplate", []interface{}{q})
+	if m.CreateInvoiceTemplateFunc == nil {
+		return nil, notStubbed("InvoicesAPI.CreateInvoiceTemplate")
+	}
+	return m.CreateInvoiceTemplateFunc(q)
+}
+
+func (m *InvoicesAPI) ShowInvoiceTemplate(templateID string) (*paypalsdk.InvoiceTemplate, error) {
+	m.record("ShowInvoiceTemplate", []interface{}{templateID})
+	if m.ShowInvoiceTemplateFunc == nil {
+		return nil, notStubbed("InvoicesAPI.ShowInvoiceTemplate")
+	}
+	return m.ShowInvoiceTemplateFunc(templateID)
+}
+
+func (m *InvoicesAPI) UpdateInvoiceTemplate(templateID string, q *paypalsdk.InvoiceTemplate) (*paypalsdk.InvoiceTemplate, error) {
+	m.record("UpdateInvoiceTemplate", []interface{}{templateID, q})
+	if m.UpdateInvoiceTemplateFunc == nil {
+		return nil, notStubbed("InvoicesAPI.UpdateInvoiceTemplate")
+	}
+	return m.UpdateInvoiceTemplateFunc(templateID, q)
+}
+
+func (m *InvoicesAPI) DeleteInvoiceTemplate(templateID string) error {
+	m.record("DeleteInvoiceTemplate", []interface{}{templateID})
+	if m.DeleteInvoiceTemplateFunc == nil {
+		return notStubbed("InvoicesAPI.DeleteInvoiceTemplate")
+	}
+	return m.DeleteInvoiceTemplateFunc(templateID)
+}
+
+// PayoutsAPI is a mock of paypalsdk.PayoutsAPI, set the Func field of each method called
+type PayoutsAPI struct {
+	CreatePayoutFunc     func(q *paypalsdk.CreatePayoutReq) (*paypalsdk.PayoutBatch, error)
+	ShowPayoutBatchFunc  func(batchID string, page int, pageSize int) (*paypalsdk.PayoutBatch, error)
+	ShowPayoutItemFunc   func(itemID string) (*paypalsdk.PayoutItemDetail, error)
+	CancelPayoutItemFunc func(itemID string) (*paypalsdk.PayoutItemDetail, error)
+
+	calls
+}
+
+var _ paypalsdk.PayoutsAPI = (*PayoutsAPI)(nil)
+
+func (m *PayoutsAPI) CreatePayout(q *paypalsdk.CreatePayoutReq) (*paypalsdk.PayoutBatch, error) {
+	m.record("CreatePayout", []interface{}{q})
+	if m.CreatePayoutFunc == nil {
+		return nil, notStubbed("PayoutsAPI.CreatePayout")
+	}
+	return m.CreatePayoutFunc(q)
+}
+
+func (m *PayoutsAPI) ShowPayoutBatch(batchID string, page int, pageSize int) (*paypalsdk.PayoutBatch, error) {
+	m.record("ShowPayoutBatch", []interface{}{batchID, page, pageSize})
+	if m.ShowPayoutBatchFunc == nil {
+		return nil, notStubbed("PayoutsAPI.ShowPayoutBatch")
+	}
+	return m.ShowPayoutBatchFunc(batchID, page, pageSize)
+}
+
+func (m *PayoutsAPI) ShowPayoutItem(itemID string) (*paypalsdk.PayoutItemDetail, error) {
+	m.record("ShowPayoutItem", []interface{}{itemID})
+	if m.ShowPayoutItemFunc == nil {
+		return nil, notStubbed("PayoutsAPI.ShowPayoutItem")
+	}
+	return m.ShowPayoutItemFunc(itemID)
+}
+
+func (m *PayoutsAPI) CancelPayoutItem(itemID string) (*paypalsdk.PayoutItemDetail, error) {
+	m.record("CancelPayoutItem", []interface{}{itemID})
+	if m.CancelPayoutItemFunc == nil {
+		return nil, notStubbed("PayoutsAPI.CancelPayoutItem")
+	}
+	return m.CancelPayoutItemFunc(itemID)
+}
+
+// ReportingAPI is a mock of paypalsdk.ReportingAPI, set the Func field of each method called
+type ReportingAPI struct {
+	ListTransactionsFunc      func(q *paypalsdk.TransactionSearchReq, page int) (*paypalsdk.TransactionSearchRsp, error)
+	SearchAllTransactionsFunc func(q *paypalsdk.TransactionSearchReq) ([]*paypalsdk.TransactionDetail, error)
+	ListBalancesFunc          func(asOfTime time.Time, currencyCode string) (*paypalsdk.BalancesRsp, error)
+
+	calls
+}
+
+var _ paypalsdk.ReportingAPI = (*ReportingAPI)(nil)
+
+func (m *ReportingAPI) ListTransactions(q *paypalsdk.TransactionSearchReq, page int) (*paypalsdk.TransactionSearchRsp, error) {
+	m.record("ListTransactions", []interface{}{q, page})
+	if m.ListTransactionsFunc == nil {
+		return nil, notStubbed("ReportingAPI.ListTransactions")
+	}
+	return m.ListTransactionsFunc(q, page)
+}
+
+func (m *ReportingAPI) SearchAllTransactions(q *paypalsdk.TransactionSearchReq) ([]*paypalsdk.TransactionDetail, error) {
+	m.record("SearchAllTransactions", []interface{}{q})
+	if m.SearchAllTransactionsFunc == nil {
+		return nil, notStubbed("ReportingAPI.SearchAllTransactions")
+	}
+	return m.SearchAllTransactionsFunc(q)
+}
+
+func (m *ReportingAPI) ListBalances(asOfTime time.Time, currencyCode string) (*paypalsdk.BalancesRsp, error) {
+	m.record("ListBalances", []interface{}{asOfTime, currencyCode})
+	if m.ListBalancesFunc == nil {
+		return nil, notStubbed("ReportingAPI.ListBalances")
+	}
+	return m.ListBalancesFunc(asOfTime, currencyCode)
+}
+
+// TrackingAPI is a mock of paypalsdk.TrackingAPI, set the Func field of each method called
+type TrackingAPI struct {
+	AddTrackersFunc   func(trackers []*paypalsdk.Tracker) (*paypalsdk.AddTrackersRsp, error)
+	UpdateTrackerFunc func(t *paypalsdk.Tracker) error
+	ShowTrackerFunc   func(transactionID string, trackingNumber string) (*paypalsdk.Tracker, error)
+
+	calls
+}
+
+var _ paypalsdk.TrackingAPI = (*TrackingAPI)(nil)
+
+func (m *TrackingAPI) AddTrackers(trackers []*paypalsdk.Tracker) (*paypalsdk.AddTrackersRsp, error) {
+	m.record("AddTrackers", []interface{}{trackers})
+	if m.AddTrackersFunc == nil {
+		return nil, notStubbed("TrackingAPI.AddTrackers")
+	}
+	return m.AddTrackersFunc(trackers)
+}
+
+func (m *TrackingAPI) UpdateTracker(t *paypalsdk.Tracker) error {
+	m.record("UpdateTracker", []interface{}{t})
+	if m.UpdateTrackerFunc == nil {
+		return notStubbed("TrackingAPI.UpdateTracker")
+	}
+	return m.UpdateTrackerFunc(t)
+}
+
+func (m *TrackingAPI) ShowTracker(transactionID string, trackingNumber string) (*paypalsdk.Tracker, error) {
+	m.record("ShowTracker", []interface{}{transactionID, trackingNumber})
+	if m.ShowTrackerFunc == nil {
+		return nil, notStubbed("TrackingAPI.ShowTracker")
+	}
+	return m.ShowTrackerFunc(transactionID, trackingNumber)
+}
+
+// VaultAPI is a mock of paypalsdk.VaultAPI, set the Func field of each method called
+type VaultAPI struct {
+	CreateSetupTokenFunc                 func(q *paypalsdk.CreateSetupTokenReq, requestID string) (*paypalsdk.SetupToken, error)
+	ShowSetupTokenFunc                   func(setupTokenID string) (*paypalsdk.SetupToken, error)
+	CreatePaymentTokenFunc               func(q *paypalsdk.CreatePaymentTokenReq, requestID string) (*paypalsdk.PaymentToken, error)
+	CreatePaymentTokenFromSetupTokenFunc func(setupTokenID string, customer *paypalsdk.VaultCustomer, requestID string) (*paypalsdk.PaymentToken, error)
+	ShowPaymentTokenFunc                 func(paymentTokenID string) (*paypalsdk.PaymentToken, error)
+	ListPaymentTokensFunc                func(customerID string, page int, pageSize int) (*paypalsdk.PaymentTokenList, error)
+	DeletePaymentTokenFunc               func(paymentTokenID string) error
+
+	calls
+}
+
+var _ paypalsdk.VaultAPI = (*VaultAPI)(nil)
+
+func (m *VaultAPI) CreateSetupToken(q *paypalsdk.CreateSetupTokenReq, requestID string) (*paypalsdk.SetupToken, error) {
+	m.record("CreateSetupToken", []interface{}{q, requestID})
+	if m.CreateSetupTokenFunc == nil {
+		return nil, notStubbed("VaultAPI.CreateSetupToken")
+	}
+	return m.CreateSetupTokenFunc(q, requestID)
+}
+
+func (m *VaultAPI) ShowSetupToken(setupTokenID string) (*paypalsdk.SetupToken, error) {
+	m.record("ShowSetupToken", []interface{}{setupTokenID})
+	if m.ShowSetupTokenFunc == nil {
+		return nil, notStubbed("VaultAPI.ShowSetupToken")
+	}
+	return m.ShowSetupTokenFunc(setupTokenID)
+}
+
+func (m *VaultAPI) CreatePaymentToken(q *paypalsdk.CreatePaymentTokenReq, requestID string) (*paypalsdk.PaymentToken, error) {
+	m.record("CreatePaymentToken", []interface{}{q, requestID})
+	if m.CreatePaymentTokenFunc == nil {
+		return nil, notStubbed("VaultAPI.CreatePaymentToken")
+	}
+	return m.CreatePaymentTokenFunc(q, requestID)
+}
+
+func (m *VaultAPI) CreatePaymentTokenFromSetupToken(setupTokenID string, customer *paypalsdk.VaultCustomer, requestID string) (*paypalsdk.PaymentToken, error) {
+	m.record("CreatePaymentTokenFromSetupToken", []interface{}{setupTokenID, customer, requestID})
+	if m.CreatePaymentTokenFromSetupTokenFunc == nil {
+		return nil, notStubbed("VaultAPI.CreatePaymentTokenFromSetupToken")
+	}
+	return m.CreatePaymentTokenFromSetupTokenFunc(setupTokenID, customer, requestID)
+}
+
+func (m *VaultAPI) ShowPaymentToken(paymentTokenID string) (*paypalsdk.PaymentToken, error) {
+	m.record("ShowPaymentToken", []interface{}{paymentTokenID})
+	if m.ShowPaymentTokenFunc == nil {
+		return nil, notStubbed("VaultAPI.ShowPaymentToken")
+	}
+	return m.ShowPaymentTokenFunc(paymentTokenID)
+}
+
+func (m *VaultAPI) ListPaymentTokens(customerID string, page int, pageSize int) (*paypalsdk.PaymentTokenList, error) {
+	m.record("ListPaymentTokens", []interface{}{customerID, page, pageSize})
+	if m.ListPaymentTokensFunc == nil {
+		return nil, notStubbed("VaultAPI.ListPaymentTokens")
+	}
+	return m.ListPaymentTokensFunc(customerID, page, pageSize)
+}
+
+func (m *VaultAPI) DeletePaymentToken(paymentTokenID string) error {
+	m.record("DeletePaymentToken", []interface{}{paymentTokenID})
+	if m.DeletePaymentTokenFunc == nil {
+		return notStubbed("VaultAPI.DeletePaymentToken")
+	}
+	return m.DeletePaymentTokenFunc(paymentTokenID)
+}
+
+// PartnersAPI is a mock of paypalsdk.PartnersAPI, set the Func field of each method called
+type PartnersAPI struct {
+	CreatePartnerReferralFunc  func(q *paypalsdk.PartnerReferralData) (*paypalsdk.PartnerReferral, error)
+	ShowPartnerReferralFunc    func(referralID string) (*paypalsdk.PartnerReferral, error)
+	ShowSellerStatusFunc       func(partnerID string, merchantID string) (*paypalsdk.SellerStatus, error)
+	FindSellerByTrackingIDFunc func(partnerID string, trackingID string) (*paypalsdk.SellerStatus, error)
+
+	calls
+}
+
+var _ paypalsdk.PartnersAPI = (*PartnersAPI)(nil)
+
+func (m *PartnersAPI) CreatePartnerReferral(q *paypalsdk.PartnerReferralData) (*paypalsdk.PartnerReferral, error) {
+	m.record("CreatePartnerReferral", []interface{}{q})
+	if m.CreatePartnerReferralFunc == nil {
+		return nil, notStubbed("PartnersAPI.CreatePartnerReferral")
+	}
+	return m.CreatePartnerReferralFunc(q)
+}
+
+func (m *PartnersAPI) ShowPartnerReferral(referralID string) (*paypalsdk.PartnerReferral, error) {
+	m.record("ShowPartnerReferral", []interface{}{referralID})
+	if m.ShowPartnerReferralFunc == nil {
+		return nil, notStubbed("PartnersAPI.ShowPartnerReferral")
+	}
+	return m.ShowPartnerReferralFunc(referralID)
+}
+
+func (m *PartnersAPI) ShowSellerStatus(partnerID string, merchantID string) (*paypalsdk.SellerStatus, error) {
+	m.record("ShowSellerStatus", []interface{}{partnerID, merchantID})
+	if m.ShowSellerStatusFunc == nil {
+		return nil, notStubbed("PartnersAPI.ShowSellerStatus")
+	}
+	return m.ShowSellerStatusFunc(partnerID, merchantID)
+}
+
+func (m *PartnersAPI) FindSellerByTrackingID(partnerID string, trackingID string) (*paypalsdk.SellerStatus, error) {
+	m.record("FindSellerByTrackingID", []interface{}{partnerID, trackingID})
+	if m.FindSellerByTrackingIDFunc == nil {
+		return nil, notStubbed("PartnersAPI.FindSellerByTrackingID")
+	}
+	return m.FindSellerByTrackingIDFunc(partnerID, trackingID)
+}
+
+// IdentityAPI is a mock of paypalsdk.IdentityAPI, set the Func field of each method called
+type IdentityAPI struct {
+	AuthorizeURLFunc                        func(redirectURI string, scopes []string, state string, nonce string) string
+	GrantNewAccessTokenFromAuthCodeFunc     func(code string, redirectURI string) (*paypalsdk.IdentityTokenResponse, error)
+	GrantNewAccessTokenFromRefreshTokenFunc func(refreshToken string) (*paypalsdk.IdentityTokenResponse, error)
+	GetUserInfoFunc                         func(accessToken string) (*paypalsdk.UserInfo, error)
+
+	calls
+}
+
+var _ paypalsdk.IdentityAPI = (*IdentityAPI)(nil)
+
+func (m *IdentityAPI) AuthorizeURL(redirectURI string, scopes []string, state string, nonce string) string {
+	m.record("AuthorizeURL", []interface{}{redirectURI, scopes, state, nonce})
+	if m.AuthorizeURLFunc == nil {
+		return ""
+	}
+	return m.AuthorizeURLFunc(redirectURI, scopes, state, nonce)
+}
+
+func (m *IdentityAPI) GrantNewAccessTokenFromAuthCode(code string, redirectURI string) (*paypalsdk.IdentityTokenResponse, error) {
+	m.record("GrantNewAccessTokenFromAuthCode", []interface{}{code, redirectURI})
+	if m.GrantNewAccessTokenFromAuthCodeFunc == nil {
+		return nil, notStubbed("IdentityAPI.GrantNewAccessTokenFromAuthCode")
+	}
+	return m.GrantNewAccessTokenFromAuthCodeFunc(code, redirectURI)
+}
+
+func (m *IdentityAPI) GrantNewAccessTokenFromRefreshToken(refreshToken string) (*paypalsdk.IdentityTokenResponse, error) {
+	m.record("GrantNewAccessTokenFromRefreshToken", []interface{}{refreshToken})
+	if m.GrantNewAccessTokenFromRefreshTokenFunc == nil {
+		return nil, notStubbed("IdentityAPI.GrantNewAccessTokenFromRefreshToken")
+	}
+	return m.GrantNewAccessTokenFromRefreshTokenFunc(refreshToken)
+}
+
+func (m *IdentityAPI) GetUserInfo(accessToken string) (*paypalsdk.UserInfo, error) {
+	m.record("GetUserInfo", []interface{}{accessToken})
+	if m.GetUserInfoFunc == nil {
+		return nil, notStubbed("IdentityAPI.GetUserInfo")
+	}
+	return m.GetUserInfoFunc(accessToken)
+}
+
+// API implements paypalsdk.API with the mocks of each API area
+type API struct {
+	SubscriptionsAPI
+	PlansAPI
+	ProductsAPI
+	WebhooksAPI
+	SalesAPI
+	PaymentsAPI
+	DisputesAPI
+	InvoicesAPI
+	PayoutsAPI
+	ReportingAPI
+	TrackingAPI
+	VaultAPI
+	PartnersAPI
+	IdentityAPI
+}
+
+var _ paypalsdk.API = (*API)(nil)
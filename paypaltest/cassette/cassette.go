@@ -0,0 +1,257 @@
+// Package cassette records the HTTP interactions of a paypalsdk.Client with PayPal into a fixture file
+// and replays them later, so that integration tests run against the sandbox once and deterministically in CI:
+//
+//	r, err := cassette.New("testdata/subscription.json", cassette.ModeAuto)
+//	defer r.Stop()
+//	c, err := paypalsdk.NewClient(clientID, secret)
+//	c.SetHTTPClient(r.Client())
+//
+// Secrets are redacted before anything is written: the Authorization and PayPal-Auth-Assertion headers, and
+// the tokens of the OAuth responses. In replay mode a request is answered by the first unused interaction
+// matching its method, path, query and normalized body; headers (PayPal-Request-Id included) and timestamps
+// are ignored, see Normalize.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type Mode int
+
+const (
+	ModeReplay Mode = iota // 只回放, 请求没有匹配的记录时返回错误
+	ModeRecord             // 请求真实的 API 并记录, Stop 时覆盖文件
+	ModeAuto               // 文件存在时回放, 否则记录
+)
+
+// 文件格式版本
+const kVersion = 1
+
+var ErrNoInteraction = errors.New("cassette: no recorded interaction matches the request")
+
+// Cassette is the content of a fixture file
+type Cassette struct {
+	Version      int            `json:"version"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  *Request  `json:"request"`
+	Response *Response `json:"response"`
+}
+
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper recording or replaying the interactions of a cassette, it is safe for concurrent use
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	match     MatchFunc
+	redactor  *redactor
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+type Option func(*Recorder)
+
+// WithTransport sets the transport of the requests recorded, http.DefaultTransport by default
+func WithTransport(t http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = t
+	}
+}
+
+// WithMatcher replaces the default matching rule of replay mode
+func WithMatcher(m MatchFunc) Option {
+	return func(r *Recorder) {
+		r.match = m
+	}
+}
+
+// WithRedactHeaders adds headers whose values are redacted, in requests and responses
+func WithRedactHeaders(names ...string) Option {
+	return func(r *Recorder) {
+		for _, n := range names {
+			r.redactor.headers[http.CanonicalHeaderKey(n)] = true
+		}
+	}
+}
+
+// WithRedactFields adds JSON fields whose values are redacted at any depth, in request and response bodies
+func WithRedactFields(names ...string) Option {
+	return func(r *Recorder) {
+		for _, n := range names {
+			r.redactor.fields[n] = true
+		}
+	}
+}
+
+// New creates a recorder of the cassette at path. In ModeReplay the file must exist
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		match:     DefaultMatcher,
+		redactor:  newRedactor(),
+		cassette:  &Cassette{Version: kVersion},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case err == nil && r.mode != ModeRecord:
+		r.mode = ModeReplay
+		if err = json.Unmarshal(data, r.cassette); err != nil {
+			return nil, fmt.Errorf("cassette: %s: %v", path, err)
+		}
+		if r.cassette.Version != kVersion {
+			return nil, fmt.Errorf("cassette: %s: unsupported version %d", path, r.cassette.Version)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	case os.IsNotExist(err) && r.mode == ModeAuto:
+		r.mode = ModeRecord
+	case err != nil && r.mode != ModeRecord:
+		return nil, err
+	}
+	return r, nil
+}
+
+// Mode returns the mode the recorder runs in, ModeAuto is resolved to ModeReplay or ModeRecord
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an http.Client using the recorder, to be given to paypalsdk.Client.SetHTTPClient
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Stop writes the recorded interactions in ModeRecord. In ModeReplay it reports the interactions not replayed
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mode == ModeReplay {
+		unused := 0
+		for _, u := range r.used {
+			if !u {
+				unused++
+			}
+		}
+		if unused > 0 {
+			return fmt.Errorf("cassette: %s: %d recorded interactions were not replayed", r.path, unused)
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	// 记录的请求体已隐去密钥, 比较前同样处理
+	body = r.redactor.body(body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || !r.match(req, body, in.Request) {
+			continue
+		}
+		r.used[i] = true
+		// 隐去密钥后长度可能变化
+		header := in.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		header.Set("Content-Length", fmt.Sprint(len(in.Response.Body)))
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader([]byte(in.Response.Body))),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = ioutil.NopCloser(bytes.NewReader(body))
+	rsp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	rspBody, err := ioutil.ReadAll(rsp.Body)
+	rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	rsp.Body = ioutil.NopCloser(bytes.NewReader(rspBody))
+
+	in := &Interaction{
+		Request: &Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: r.redactor.header(req.Header),
+			Body:   string(r.redactor.body(body)),
+		},
+		Response: &Response{
+			StatusCode: rsp.StatusCode,
+			Header:     r.redactor.header(rsp.Header),
+			Body:       string(r.redactor.body(rspBody)),
+		},
+	}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	r.mu.Unlock()
+	return rsp, nil
+}
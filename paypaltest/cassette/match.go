@@ -0,0 +1,183 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 替换被隐去的值和时间戳
+const (
+	Redacted   = "REDACTED"
+	kTimestamp = "<timestamp>"
+)
+
+// MatchFunc reports whether a request, with its body already redacted, matches a recorded one
+type MatchFunc func(req *http.Request, body []byte, recorded *Request) bool
+
+// DefaultMatcher matches the method, the path, the query and the body after Normalize.
+// The host is ignored so that a cassette recorded against the sandbox replays with any API base
+func DefaultMatcher(req *http.Request, body []byte, recorded *Request) bool {
+	if req.Method != recorded.Method {
+		return false
+	}
+	u, err := url.Parse(recorded.URL)
+	if err != nil || u.Path != req.URL.Path {
+		return false
+	}
+	if normalizeValues(u.Query()) != normalizeValues(req.URL.Query()) {
+		return false
+	}
+	return bytes.Equal(Normalize([]byte(recorded.Body)), Normalize(body))
+}
+
+// Normalize returns body in a canonical form: JSON objects with sorted keys and form values sorted by name,
+// with every timestamp (RFC 3339 date-time or full-date) replaced by a placeholder
+func Normalize(body []byte) []byte {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return body
+	}
+	if body[0] == '{' || body[0] == '[' {
+		d := json.NewDecoder(bytes.NewReader(body))
+		d.UseNumber()
+		var v interface{}
+		if err := d.Decode(&v); err == nil {
+			data, _ := json.Marshal(walk(v, func(key string, s string) string {
+				if isTimestamp(s) {
+					return kTimestamp
+				}
+				return s
+			}))
+			return data
+		}
+	}
+	if values, err := url.ParseQuery(string(body)); err == nil && strings.Contains(string(body), "=") {
+		return []byte(normalizeValues(values))
+	}
+	return body
+}
+
+func normalizeValues(values url.Values) string {
+	for _, vs := range values {
+		for i, v := range vs {
+			if isTimestamp(v) {
+				vs[i] = kTimestamp
+			}
+		}
+	}
+	return values.Encode()
+}
+
+func isTimestamp(s string) bool {
+	if _, err := time.Parse(time.RFC3339, s); err == nil {
+		return true
+	}
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
+
+// walk returns v with every string value replaced by f(key of the value, value)
+func walk(v interface{}, f func(key, s string) string) interface{} {
+	var visit func(key string, v interface{}) interface{}
+	visit = func(key string, v interface{}) interface{} {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for k, e := range t {
+				t[k] = visit(k, e)
+			}
+		case []interface{}:
+			for i, e := range t {
+				t[i] = visit(key, e)
+			}
+		case string:
+			return f(key, t)
+		}
+		return v
+	}
+	return visit("", v)
+}
+
+// redactor hides secrets before interactions are written or matched
+type redactor struct {
+	headers map[string]bool
+	fields  map[string]bool
+}
+
+func newRedactor() *redactor {
+	return &redactor{
+		headers: map[string]bool{
+			"Authorization":         true,
+			"Paypal-Auth-Assertion": true,
+			"Set-Cookie":            true,
+			"Cookie":                true,
+		},
+		fields: map[string]bool{
+			"access_token":  true,
+			"refresh_token": true,
+			"id_token":      true,
+			"client_secret": true,
+			"code":          true,
+		},
+	}
+}
+
+func (r *redactor) header(h http.Header) http.Header {
+	out := h.Clone()
+	for k := range out {
+		if r.headers[http.CanonicalHeaderKey(k)] {
+			out[k] = []string{Redacted}
+		}
+	}
+	return out
+}
+
+// body redacts the fields of a JSON or form body, other bodies are returned as is
+func (r *redactor) body(body []byte) []byte {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return body
+	}
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		d := json.NewDecoder(bytes.NewReader(trimmed))
+		d.UseNumber()
+		var v interface{}
+		if err := d.Decode(&v); err != nil {
+			return body
+		}
+		redacted := false
+		v = walk(v, func(key, s string) string {
+			if r.fields[key] {
+				redacted = true
+				return Redacted
+			}
+			return s
+		})
+		if !redacted {
+			return body
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return body
+		}
+		return data
+	}
+	values, err := url.ParseQuery(string(trimmed))
+	if err != nil || !strings.Contains(string(trimmed), "=") {
+		return body
+	}
+	redacted := false
+	for k := range values {
+		if r.fields[k] {
+			values[k] = []string{Redacted}
+			redacted = true
+		}
+	}
+	if !redacted {
+		return body
+	}
+	return []byte(values.Encode())
+}
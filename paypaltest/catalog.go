@@ -0,0 +1,159 @@
+package paypaltest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+func (s *Server) createProduct(r *http.Request, body []byte, ids []string) *response {
+	p := &paypalsdk.Product{}
+	if rsp := decodeBody(body, p); rsp != nil {
+		return rsp
+	}
+	if rsp := validationResponse(p.Validate()); rsp != nil {
+		return rsp
+	}
+	if p.Type == "" {
+		p.Type = paypalsdk.E_PRODUCT_TYPE_PHYSICAL
+	}
+	if p.ID == "" {
+		p.ID = s.nextID("PROD-", 12)
+	} else if _, ok := s.products[p.ID]; ok {
+		return unprocessable("DUPLICATE_RESOURCE_IDENTIFIER", "The product id already exists.")
+	}
+	p.CreateTime = s.now()
+	p.UpdateTime = p.CreateTime
+	p.Links = []*paypalsdk.LinkDescription{
+		s.link(paypalsdk.E_LINK_REL_SELF, "GET", paypalsdk.K_PRODUCT_API+"/"+p.ID),
+		s.link(paypalsdk.E_LINK_REL_EDIT, "PATCH", paypalsdk.K_PRODUCT_API+"/"+p.ID),
+	}
+	s.products[p.ID] = p
+	return &response{
+		status: http.StatusCreated,
+		body:   p,
+		events: s.newEvents(paypalsdk.E_EVENT_TYPE_CATALOG_PRODUCT_CREATED, paypalsdk.E_EVENT_RESOURCE_TYPE_PRODUCT, p),
+	}
+}
+
+func (s *Server) listProducts(r *http.Request, body []byte, ids []string) *response {
+	list := []*paypalsdk.Product{}
+	for _, p := range s.products {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return createdBefore(list[i].CreateTime, list[i].ID, list[j].CreateTime, list[j].ID)
+	})
+	start, end := page(r, len(list))
+	return &response{status: http.StatusOK, body: &paypalsdk.ProductList{
+		Products:   list[start:end],
+		TotalItems: len(list),
+		TotalPages: pages(r, len(list)),
+	}}
+}
+
+func (s *Server) showProduct(r *http.Request, body []byte, ids []string) *response {
+	p, ok := s.products[ids[0]]
+	if !ok {
+		return notFound("INVALID_RESOURCE_ID", "The specified resource ID does not exist.")
+	}
+	return &response{status: http.StatusOK, body: p}
+}
+
+func (s *Server) createPlan(r *http.Request, body []byte, ids []string) *response {
+	p := &paypalsdk.Plan{}
+	if rsp := decodeBody(body, p); rsp != nil {
+		return rsp
+	}
+	if rsp := validationResponse(p.Validate()); rsp != nil {
+		return rsp
+	}
+	if _, ok := s.products[p.ProductID]; !ok {
+		return notFound("INVALID_RESOURCE_ID", "The product_id does not exist.")
+	}
+	if p.Status == "" {
+		p.Status = paypalsdk.E_PLAN_STATUS_ACTIVE
+	}
+
+	p.ID = s.nextID("P-", 24)
+	p.CreateTime = s.now()
+	p.UpdateTime = p.CreateTime
+	p.Links = []*paypalsdk.LinkDescription{
+		s.link(paypalsdk.E_LINK_REL_SELF, "GET", paypalsdk.K_PLAN_API+"/"+p.ID),
+		s.link(paypalsdk.E_LINK_REL_EDIT, "PATCH", paypalsdk.K_PLAN_API+"/"+p.ID),
+	}
+	s.plans[p.ID] = p
+	return &response{
+		status: http.StatusCreated,
+		body:   p,
+		events: s.newEvents(paypalsdk.E_EVENT_TYPE_BILLING_PLAN_CREATED, paypalsdk.E_EVENT_RESOURCE_TYPE_PLAN, p),
+	}
+}
+
+func (s *Server) listPlans(r *http.Request, body []byte, ids []string) *response {
+	productID := r.URL.Query().Get("product_id")
+	list := []*paypalsdk.Plan{}
+	for _, p := range s.plans {
+		if productID == "" || p.ProductID == productID {
+			list = append(list, p)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return createdBefore(list[i].CreateTime, list[i].ID, list[j].CreateTime, list[j].ID)
+	})
+	start, end := page(r, len(list))
+	return &response{status: http.StatusOK, body: &paypalsdk.PlanList{
+		Plans:      list[start:end],
+		TotalItems: len(list),
+		TotalPages: pages(r, len(list)),
+	}}
+}
+
+func (s *Server) showPlan(r *http.Request, body []byte, ids []string) *response {
+	p, ok := s.plans[ids[0]]
+	if !ok {
+		return notFound("INVALID_RESOURCE_ID", "The specified resource ID does not exist.")
+	}
+	return &response{status: http.StatusOK, body: p}
+}
+
+func (s *Server) activatePlan(r *http.Request, body []byte, ids []string) *response {
+	return s.changePlanStatus(ids[0], paypalsdk.E_PLAN_STATUS_ACTIVE, paypalsdk.E_EVENT_TYPE_BILLING_PLAN_ACTIVATED,
+		paypalsdk.E_PLAN_STATUS_CREATED, paypalsdk.E_PLAN_STATUS_INACTIVE)
+}
+
+func (s *Server) deactivatePlan(r *http.Request, body []byte, ids []string) *response {
+	return s.changePlanStatus(ids[0], paypalsdk.E_PLAN_STATUS_INACTIVE, paypalsdk.E_EVENT_TYPE_BILLING_PLAN_DEACTIVATED,
+		paypalsdk.E_PLAN_STATUS_ACTIVE)
+}
+
+func (s *Server) changePlanStatus(id string, to paypalsdk.E_PlanStatus, eventType string, from ...paypalsdk.E_PlanStatus) *response {
+	p, ok := s.plans[id]
+	if !ok {
+		return notFound("INVALID_RESOURCE_ID", "The specified resource ID does not exist.")
+	}
+	allowed := false
+	for _, status := range from {
+		allowed = allowed || p.Status == status
+	}
+	if !allowed {
+		return unprocessable("PLAN_STATUS_INVALID", fmt.Sprintf("Invalid plan status for %s action; plan status should be one of %v.", to, from))
+	}
+	p.Status = to
+	p.UpdateTime = s.now()
+	return &response{
+		status: http.StatusNoContent,
+		events: s.newEvents(eventType, paypalsdk.E_EVENT_RESOURCE_TYPE_PLAN, p),
+	}
+}
+
+// createdBefore orders lists by create time like PayPal, the ID breaks ties of resources created in the same second
+func createdBefore(ti time.Time, idi string, tj time.Time, idj string) bool {
+	if !ti.Equal(tj) {
+		return ti.Before(tj)
+	}
+	return idi < idj
+}
@@ -0,0 +1,79 @@
+package paypaltest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+func TestListsByCreateTime(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.Now = func() time.Time { return now }
+	c, err := s.NewClient(paypalsdk.WithLogger(discard{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 第二个和第三个在同一秒创建, 按 ID 排序
+	steps := []struct {
+		id      string
+		advance time.Duration
+	}{
+		{"PROD-ZZZZZZ", 0},
+		{"PROD-MMMMMM", time.Second},
+		{"PROD-BBBBBB", 0},
+		{"PROD-AAAAAA", time.Minute},
+	}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		if _, err := c.CreateProduct(&paypalsdk.Product{ID: step.id, Name: step.id, Type: paypalsdk.E_PRODUCT_TYPE_SERVICE}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	products, err := c.ListProducts(1, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, p := range products.Products {
+		ids = append(ids, p.ID)
+	}
+	if want := []string{"PROD-ZZZZZZ", "PROD-BBBBBB", "PROD-MMMMMM", "PROD-AAAAAA"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("products = %v, want %v", ids, want)
+	}
+
+	// 时钟回拨后创建的 plan 排在前面
+	var created []string
+	for _, advance := range []time.Duration{0, -time.Hour} {
+		now = now.Add(advance)
+		p, err := c.CreatePlan(&paypalsdk.Plan{
+			ProductID: "PROD-AAAAAA",
+			Name:      "Monthly",
+			BillingCycles: []*paypalsdk.BillingCycle{{
+				TenureType:    paypalsdk.E_TENURE_TYPE_REGULAR,
+				Sequence:      1,
+				Frequency:     &paypalsdk.Frequency{IntervalUnit: paypalsdk.E_FREQUENCY_INTERVAL_MONTH},
+				PricingScheme: &paypalsdk.PricingScheme{FixedPrice: paypalsdk.NewMoney("USD", 999)},
+			}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		created = append(created, p.ID)
+	}
+	plans, err := c.ListPlans("", 1, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids = nil
+	for _, p := range plans.Plans {
+		ids = append(ids, p.ID)
+	}
+	if want := []string{created[1], created[0]}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("plans = %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,385 @@
+// Package paypaltest provides an in-process fake of the PayPal REST API for tests, so that code built on
+// paypalsdk.Client can be tested without reaching the sandbox.
+//
+// The fake implements OAuth token issuance, catalog products, billing plans, subscriptions, webhooks and events,
+// with the state transitions and error responses of PayPal. Webhook events caused by state changes are
+// signed and delivered synchronously to the registered webhooks, before the call causing them returns;
+// the signatures can be verified with paypalsdk.Client.VerifyWebhookSignature against the fake:
+//
+//	s := paypaltest.NewServer()
+//	defer s.Close()
+//	c, err := s.NewClient()
+//	sub, err := c.CreateSubscription(&paypalsdk.CreateSubscriptionReq{PlanID: planID})
+//	err = s.ApproveSubscription(sub.ID) // what the buyer does on the approve link
+package paypaltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+const (
+	DefaultClientID = "paypaltest-client-id"
+	DefaultSecret   = "paypaltest-secret"
+	DefaultTokenTTL = 9 * time.Hour
+)
+
+// Server is a fake PayPal REST API server, it is safe for concurrent use
+type Server struct {
+	URL      string
+	ClientID string
+	Secret   string
+	TokenTTL time.Duration
+	// Now returns the current time of the fake, replace it to control billing dates and token expiry
+	Now func() time.Time
+
+	srv        *httptest.Server
+	signingKey []byte
+
+	mu            sync.Mutex
+	seq           int
+	tokens        map[string]time.Time
+	failures      map[string][]int
+	products      map[string]*paypalsdk.Product
+	plans         map[string]*paypalsdk.Plan
+	subscriptions map[string]*subscription
+	webhooks      map[string]*paypalsdk.Webhook
+	webhookOrder  []string
+	events        []*paypalsdk.Event
+	deliveries    []*Delivery
+}
+
+// NewServer starts a fake server accepting DefaultClientID and DefaultSecret, it must be closed after use
+func NewServer() *Server {
+	s := &Server{
+		ClientID:      DefaultClientID,
+		Secret:        DefaultSecret,
+		TokenTTL:      DefaultTokenTTL,
+		Now:           time.Now,
+		signingKey:    []byte(fmt.Sprintf("paypaltest-%d", time.Now().UnixNano())),
+		tokens:        map[string]time.Time{},
+		failures:      map[string][]int{},
+		products:      map[string]*paypalsdk.Product{},
+		plans:         map[string]*paypalsdk.Plan{},
+		subscriptions: map[string]*subscription{},
+		webhooks:      map[string]*paypalsdk.Webhook{},
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// NewClient returns a client calling the fake, with an access token already obtained
+func (s *Server) NewClient(opts ...paypalsdk.ClientOption) (*paypalsdk.Client, error) {
+	opts = append([]paypalsdk.ClientOption{paypalsdk.WithAPIBase(s.URL)}, opts...)
+	c, err := paypalsdk.NewClient(s.ClientID, s.Secret, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = c.GetAccessToken(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// FailNext makes the next call of operation fail with status, eg: FailNext("paypal.subscriptions.create", 503).
+// Operation names are those of paypalsdk.ResolveOperation. Calls are failed in the order given
+func (s *Server) FailNext(operation string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[operation] = append(s.failures[operation], status)
+}
+
+// ExpireTokens makes every access token issued so far invalid
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]time.Time{}
+}
+
+// Events returns every webhook event emitted so far, whether a webhook subscribed to it or not
+func (s *Server) Events() []*paypalsdk.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*paypalsdk.Event(nil), s.events...)
+}
+
+// nextID returns a unique ID like I-000000000001, the same sequence of calls gives the same IDs
+func (s *Server) nextID(prefix string, digits int) string {
+	s.seq++
+	return fmt.Sprintf("%s%0*d", prefix, digits, s.seq)
+}
+
+func (s *Server) now() time.Time {
+	return s.Now().UTC().Truncate(time.Second)
+}
+
+// response is what a handler returns, events are delivered after the lock is released
+type response struct {
+	status int
+	body   interface{}
+	events []*paypalsdk.Event
+	resend *resend
+}
+
+// resend is an event to deliver again, to webhookIDs or to its subscribers if empty
+type resend struct {
+	event      *paypalsdk.Event
+	webhookIDs []string
+}
+
+type handlerFunc func(r *http.Request, body []byte, ids []string) *response
+
+func (s *Server) handlers() map[string]handlerFunc {
+	return map[string]handlerFunc{
+		"paypal.products.create": s.createProduct,
+		"paypal.products.list":   s.listProducts,
+		"paypal.products.get":    s.showProduct,
+
+		"paypal.plans.create":     s.createPlan,
+		"paypal.plans.list":       s.listPlans,
+		"paypal.plans.get":        s.showPlan,
+		"paypal.plans.activate":   s.activatePlan,
+		"paypal.plans.deactivate": s.deactivatePlan,
+
+		"paypal.subscriptions.create":       s.createSubscription,
+		"paypal.subscriptions.get":          s.showSubscription,
+		"paypal.subscriptions.activate":     s.activateSubscription,
+		"paypal.subscriptions.suspend":      s.suspendSubscription,
+		"paypal.subscriptions.cancel":       s.cancelSubscription,
+		"paypal.subscriptions.transactions": s.listSubscriptionTransactions,
+
+		"paypal.webhooks.create":           s.createWebhook,
+		"paypal.webhooks.list":             s.listWebhooks,
+		"paypal.webhooks.get":              s.showWebhook,
+		"paypal.webhooks.update":           s.updateWebhook,
+		"paypal.webhooks.delete":           s.deleteWebhook,
+		"paypal.webhooks.verify_signature": s.verifyWebhookSignature,
+
+		"paypal.webhook_events.list":   s.listWebhookEvents,
+		"paypal.webhook_events.get":    s.showWebhookEvent,
+		"paypal.webhook_events.resend": s.resendWebhookEvent,
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	operation, route := paypalsdk.ResolveOperation(r.Method, r.URL.Path)
+	w.Header().Set("Paypal-Debug-Id", fmt.Sprintf("%x", time.Now().UnixNano()))
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeJSON(w, errorResponse(http.StatusBadRequest, "INVALID_REQUEST", "Request body could not be read."))
+		return
+	}
+
+	s.mu.Lock()
+	rsp := s.handle(operation, route, r, body)
+	s.mu.Unlock()
+
+	s.deliver(rsp.events)
+	if rsp.resend != nil {
+		s.redeliver(rsp.resend)
+	}
+	writeJSON(w, rsp)
+}
+
+func (s *Server) handle(operation, route string, r *http.Request, body []byte) *response {
+	if statuses := s.failures[operation]; len(statuses) > 0 {
+		s.failures[operation] = statuses[1:]
+		name := strings.ToUpper(strings.Replace(http.StatusText(statuses[0]), " ", "_", -1))
+		return errorResponse(statuses[0], name, "Failure injected by paypaltest.FailNext.")
+	}
+	if operation == "paypal.oauth2.token" {
+		return s.issueToken(r, body)
+	}
+	if rsp := s.authenticate(r); rsp != nil {
+		return rsp
+	}
+	h, ok := s.handlers()[operation]
+	if !ok {
+		return errorResponse(http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("%s %s is not implemented by paypaltest.", r.Method, route))
+	}
+	return h(r, body, pathIDs(route, r.URL.Path))
+}
+
+// pathIDs returns the path segments matching {id} of route
+func pathIDs(route, path string) []string {
+	var ids []string
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, p := range strings.Split(strings.Trim(route, "/"), "/") {
+		if p == "{id}" && i < len(segments) {
+			ids = append(ids, segments[i])
+		}
+	}
+	return ids
+}
+
+func (s *Server) issueToken(r *http.Request, body []byte) *response {
+	id, secret, ok := r.BasicAuth()
+	if !ok || id != s.ClientID || secret != s.Secret {
+		return &response{status: http.StatusUnauthorized, body: map[string]string{
+			"error":             "invalid_client",
+			"error_description": "Client Authentication failed",
+		}}
+	}
+	if !strings.Contains(string(body), "grant_type=client_credentials") {
+		return &response{status: http.StatusBadRequest, body: map[string]string{
+			"error":             "unsupported_grant_type",
+			"error_description": "Grant Type is NULL",
+		}}
+	}
+	token := s.nextID("A21AA", 40)
+	s.tokens[token] = s.now().Add(s.TokenTTL)
+	return &response{status: http.StatusOK, body: map[string]interface{}{
+		"scope":        "https://uri.paypal.com/services/subscriptions https://uri.paypal.com/services/applications/webhooks",
+		"access_token": token,
+		"token_type":   "Bearer",
+		"app_id":       "APP-80W284485P519543T",
+		"expires_in":   int64(s.TokenTTL / time.Second),
+		"nonce":        s.now().Format(time.RFC3339) + token[len(token)-8:],
+	}}
+}
+
+func (s *Server) authenticate(r *http.Request) *response {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	expiresAt, ok := s.tokens[token]
+	if !ok || !s.now().Before(expiresAt) {
+		return &response{status: http.StatusUnauthorized, body: map[string]string{
+			"error":             "invalid_token",
+			"error_description": "Token signature verification failed",
+		}}
+	}
+	return nil
+}
+
+// errorDetail and errorBody are the error format of PayPal REST APIs
+type errorDetail struct {
+	Field       string `json:"field,omitempty"`
+	Value       string `json:"value,omitempty"`
+	Location    string `json:"location,omitempty"`
+	Issue       string `json:"issue"`
+	Description string `json:"description,omitempty"`
+}
+
+type errorBody struct {
+	Name    string         `json:"name"`
+	Message string         `json:"message"`
+	DebugID string         `json:"debug_id"`
+	Details []*errorDetail `json:"details,omitempty"`
+}
+
+func errorResponse(status int, name, message string, details ...*errorDetail) *response {
+	return &response{status: status, body: &errorBody{
+		Name:    name,
+		Message: message,
+		DebugID: fmt.Sprintf("%x", time.Now().UnixNano()),
+		Details: details,
+	}}
+}
+
+func notFound(issue, description string) *response {
+	return errorResponse(http.StatusNotFound, "RESOURCE_NOT_FOUND", "The specified resource does not exist.",
+		&errorDetail{Issue: issue, Description: description})
+}
+
+func unprocessable(issue, description string) *response {
+	return errorResponse(http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY",
+		"The requested action could not be performed, semantically incorrect, or failed business validation.",
+		&errorDetail{Issue: issue, Description: description})
+}
+
+func invalidRequest(details ...*errorDetail) *response {
+	return errorResponse(http.StatusBadRequest, "INVALID_REQUEST",
+		"Request is not well-formed, syntactically incorrect, or violates schema.", details...)
+}
+
+// validationResponse turns the violations reported by Validate into a 400 response, nil if err is nil
+func validationResponse(err error) *response {
+	if err == nil {
+		return nil
+	}
+	ve, ok := err.(*paypalsdk.ValidationError)
+	if !ok {
+		return invalidRequest(&errorDetail{Issue: "INVALID_PARAMETER_VALUE", Description: err.Error()})
+	}
+	details := make([]*errorDetail, 0, len(ve.Violations))
+	for _, v := range ve.Violations {
+		issue := "INVALID_PARAMETER_VALUE"
+		if v.Issue == "is required" {
+			issue = "MISSING_REQUIRED_PARAMETER"
+		}
+		details = append(details, &errorDetail{Field: v.Field, Location: "body", Issue: issue, Description: v.Issue})
+	}
+	return invalidRequest(details...)
+}
+
+func decodeBody(body []byte, v interface{}) *response {
+	if err := json.Unmarshal(body, v); err != nil {
+		return invalidRequest(&errorDetail{Location: "body", Issue: "MALFORMED_REQUEST_JSON", Description: err.Error()})
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, rsp *response) {
+	if rsp.body == nil {
+		w.WriteHeader(rsp.status)
+		return
+	}
+	data, err := json.Marshal(rsp.body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(rsp.status)
+	w.Write(data)
+}
+
+func (s *Server) link(rel paypalsdk.E_LinkRel, method, path string) *paypalsdk.LinkDescription {
+	return &paypalsdk.LinkDescription{Href: s.URL + path, Rel: rel, Method: method}
+}
+
+// page returns the start and end index of page (from 1) in n items
+func page(r *http.Request, n int) (int, int) {
+	p, size := pageParams(r)
+	start := (p - 1) * size
+	if start > n {
+		start = n
+	}
+	end := start + size
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+// pages returns the number of pages of n items
+func pages(r *http.Request, n int) int {
+	_, size := pageParams(r)
+	return (n + size - 1) / size
+}
+
+func pageParams(r *http.Request) (int, int) {
+	p, size := 1, 10
+	fmt.Sscan(r.URL.Query().Get("page"), &p)
+	fmt.Sscan(r.URL.Query().Get("page_size"), &size)
+	if p < 1 {
+		p = 1
+	}
+	if size < 1 {
+		size = 10
+	}
+	return p, size
+}
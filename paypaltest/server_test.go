@@ -0,0 +1,196 @@
+package paypaltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+type discard struct{}
+
+func (discard) Println(v ...interface{}) {}
+
+// newServer returns a fake whose clock only moves with *now, and a client of it
+func newServer(t *testing.T) (*Server, *paypalsdk.Client, *time.Time) {
+	t.Helper()
+	s := NewServer()
+	t.Cleanup(s.Close)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.Now = func() time.Time { return now }
+	c, err := s.NewClient(paypalsdk.WithLogger(discard{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, c, &now
+}
+
+// do calls the fake with the token of c and returns the status and body, payload is sent as JSON
+func do(t *testing.T, c *paypalsdk.Client, method, path string, payload interface{}) (int, []byte) {
+	t.Helper()
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, c.APIBase+path, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token.Token)
+	req.Header.Set("Content-Type", "application/json")
+	rsp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsp.Body.Close()
+	data, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rsp.StatusCode, data
+}
+
+// statusCode returns the HTTP status of an error returned by the client, 0 if there is none
+func statusCode(err error) int {
+	switch e := err.(type) {
+	case *paypalsdk.ResponseError:
+		return e.Response.StatusCode
+	case *paypalsdk.IdentityError:
+		return e.Response.StatusCode
+	}
+	return 0
+}
+
+func TestTokens(t *testing.T) {
+	s, c, now := newServer(t)
+	sub := func() error {
+		_, err := c.ShowSubscriptionDetails("I-404")
+		return err
+	}
+	if got := statusCode(sub()); got != http.StatusNotFound {
+		t.Fatalf("call with a valid token status = %d, want 404", got)
+	}
+
+	s.ExpireTokens()
+	if got := statusCode(sub()); got != http.StatusUnauthorized {
+		t.Errorf("call after ExpireTokens status = %d, want 401", got)
+	}
+	if _, err := c.GetAccessToken(); err != nil {
+		t.Fatal(err)
+	}
+	if got := statusCode(sub()); got != http.StatusNotFound {
+		t.Errorf("call with a new token status = %d, want 404", got)
+	}
+
+	*now = now.Add(DefaultTokenTTL)
+	if got := statusCode(sub()); got != http.StatusUnauthorized {
+		t.Errorf("call after the token TTL status = %d, want 401", got)
+	}
+
+	other, err := paypalsdk.NewClient(DefaultClientID, "wrong", paypalsdk.WithAPIBase(s.URL), paypalsdk.WithLogger(discard{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.GetAccessToken(); statusCode(err) != http.StatusUnauthorized {
+		t.Errorf("GetAccessToken with a wrong secret error = %v, want 401", err)
+	}
+}
+
+func TestFailNext(t *testing.T) {
+	s, c, _ := newServer(t)
+	s.FailNext("paypal.subscriptions.get", http.StatusServiceUnavailable)
+	s.FailNext("paypal.subscriptions.get", http.StatusInternalServerError)
+
+	for _, want := range []int{http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusNotFound} {
+		_, err := c.ShowSubscriptionDetails("I-404")
+		if got := statusCode(err); got != want {
+			t.Errorf("ShowSubscriptionDetails status = %d, want %d", got, want)
+		}
+	}
+	s.FailNext("paypal.subscriptions.get", http.StatusServiceUnavailable)
+	_, err := c.ShowSubscriptionDetails("I-404")
+	if e, ok := err.(*paypalsdk.ResponseError); !ok || e.Name != "SERVICE_UNAVAILABLE" {
+		t.Errorf("injected failure = %v, want name SERVICE_UNAVAILABLE", err)
+	}
+
+	// 其他接口不受影响
+	s.FailNext("paypal.subscriptions.create", http.StatusServiceUnavailable)
+	if _, err := c.ShowSubscriptionDetails("I-404"); statusCode(err) != http.StatusNotFound {
+		t.Errorf("ShowSubscriptionDetails error = %v, want 404", err)
+	}
+}
+
+func TestSignedDelivery(t *testing.T) {
+	s, c, _ := newServer(t)
+
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	var deliveries []received
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		deliveries = append(deliveries, received{r.Header, body})
+	}))
+	defer receiver.Close()
+
+	wh, err := c.CreateWebhook(&paypalsdk.CreateWebhookReq{
+		Url:        receiver.URL + "/paypal/webhook",
+		EventTypes: []*paypalsdk.EventType{{Name: paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_CREATED}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscribe(t, c, newPlan(t, c, regularPlan(0)), nil)
+
+	if len(deliveries) != 1 {
+		t.Fatalf("%d deliveries, want 1", len(deliveries))
+	}
+	d := deliveries[0]
+	e, err := paypalsdk.ParseEvent(d.body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.EventType != paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_CREATED {
+		t.Errorf("delivered event %s", e.EventType)
+	}
+	if got := s.Deliveries(); len(got) != 1 || got[0].StatusCode != http.StatusOK || got[0].WebhookID != wh.ID {
+		t.Errorf("Deliveries() = %+v", got)
+	}
+
+	tampered := bytes.Replace(d.body, []byte(e.Id), []byte("WH-FORGED"), 1)
+	tests := []struct {
+		name      string
+		header    http.Header
+		body      []byte
+		webhookID string
+		want      bool
+	}{
+		{"delivery", d.header, d.body, wh.ID, true},
+		{"SignEvent", s.SignEvent(wh.ID, d.body), d.body, wh.ID, true},
+		{"tampered body", d.header, tampered, wh.ID, false},
+		{"other webhook", d.header, d.body, "WH-OTHER", false},
+		{"signed for other webhook", s.SignEvent("WH-OTHER", d.body), d.body, wh.ID, false},
+	}
+	for _, tt := range tests {
+		verified, err := c.VerifyWebhookSignature(tt.header, tt.body, tt.webhookID)
+		if err != nil {
+			t.Fatalf("%s: VerifyWebhookSignature error = %v", tt.name, err)
+		}
+		if verified != tt.want {
+			t.Errorf("%s: verified = %v, want %v", tt.name, verified, tt.want)
+		}
+	}
+
+	if _, err := c.CreateWebhook(&paypalsdk.CreateWebhookReq{Url: receiver.URL + "/paypal/webhook", EventTypes: []*paypalsdk.EventType{{Name: "*"}}}); statusCode(err) != http.StatusBadRequest {
+		t.Errorf("CreateWebhook of an existing URL error = %v, want 400", err)
+	}
+}
@@ -0,0 +1,429 @@
+package paypaltest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+// subscription is a subscription of the fake with what PayPal keeps but does not return
+type subscription struct {
+	*paypalsdk.Subscription
+	plan         *paypalsdk.Plan
+	userAction   paypalsdk.E_UserAction
+	transactions []*paypalsdk.SubTransaction
+}
+
+func (s *Server) createSubscription(r *http.Request, body []byte, ids []string) *response {
+	q := &paypalsdk.CreateSubscriptionReq{}
+	if rsp := decodeBody(body, q); rsp != nil {
+		return rsp
+	}
+	var startTime time.Time
+	if q.StartTime != "" {
+		var err error
+		if startTime, err = time.Parse(time.RFC3339, q.StartTime); err != nil {
+			return invalidRequest(&errorDetail{Field: "/start_time", Value: q.StartTime, Location: "body", Issue: "INVALID_PARAMETER_SYNTAX", Description: "The value of a field does not conform to the expected format."})
+		}
+	}
+	if rsp := validationResponse(q.Validate()); rsp != nil {
+		return rsp
+	}
+	plan, ok := s.plans[q.PlanID]
+	if !ok {
+		return notFound("INVALID_RESOURCE_ID", "Requested resource ID was not found.")
+	}
+	if plan.Status != paypalsdk.E_PLAN_STATUS_ACTIVE {
+		return unprocessable("PLAN_STATUS_INVALID", "Invalid plan status for subscription creation; plan status should be ACTIVE.")
+	}
+	if q.Quantity != "" && q.Quantity != "1" && !plan.QuantitySupported {
+		return unprocessable("SUBSCRIPTION_UNSUPPORTED_QUANTITY", "Quantity is not supported for the plan.")
+	}
+
+	now := s.now()
+	start := now
+	if !startTime.IsZero() {
+		start = startTime
+	}
+	sub := &subscription{
+		plan:       plan,
+		userAction: paypalsdk.E_USER_ACTION_SUBSCRIBE_NOW,
+		Subscription: &paypalsdk.Subscription{
+			ID:               s.nextID("I-", 12),
+			Status:           paypalsdk.E_SUBSCRIPTION_STATUS_APPROVAL_PENDING,
+			StatusUpdateTime: now,
+			PlanID:           plan.ID,
+			StartTime:        start,
+			Quantity:         q.Quantity,
+			ShippingAmount:   q.ShippingAmount,
+			Subscriber:       q.Subscriber,
+			AutoRenewal:      q.AutoRenewal,
+			CreateTime:       now,
+			UpdateTime:       now,
+		},
+	}
+	if sub.Quantity == "" {
+		sub.Quantity = "1"
+	}
+	if q.ApplicationContext != nil && q.ApplicationContext.UserAction != "" {
+		sub.userAction = q.ApplicationContext.UserAction
+	}
+
+	currency := "USD"
+	cycles := make([]*paypalsdk.CycleExecutions, 0, len(plan.BillingCycles))
+	for _, bc := range plan.BillingCycles {
+		if bc.PricingScheme != nil && bc.PricingScheme.FixedPrice != nil {
+			currency = bc.PricingScheme.FixedPrice.CurrencyCode
+		}
+		cycles = append(cycles, &paypalsdk.CycleExecutions{
+			TenureType:      bc.TenureType,
+			Sequence:        bc.Sequence,
+			CyclesRemaining: bc.TotalCycles,
+			TotalCycles:     bc.TotalCycles,
+		})
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i].Sequence < cycles[j].Sequence })
+	sub.BillingInfo = &paypalsdk.BillingInfo{
+		OutstandingBalance: paypalsdk.NewMoney(currency, 0),
+		CycleExecutions:    cycles,
+		NextBillingTime:    start,
+	}
+	sub.Links = []*paypalsdk.LinkDescription{
+		s.link(paypalsdk.E_LINK_REL_APPROVE, "GET", "/webapps/billing/subscriptions?ba_token=BA-"+sub.ID),
+		s.link(paypalsdk.E_LINK_REL_EDIT, "PATCH", paypalsdk.K_SUBSCRIPTION_API+"/"+sub.ID),
+		s.link(paypalsdk.E_LINK_REL_SELF, "GET", paypalsdk.K_SUBSCRIPTION_API+"/"+sub.ID),
+	}
+	s.subscriptions[sub.ID] = sub
+
+	return &response{
+		status: http.StatusCreated,
+		body:   sub.Subscription,
+		events: s.newEvents(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_CREATED, paypalsdk.E_EVENT_RESOURCE_TYPE_SUBCRIPTION, sub.Subscription),
+	}
+}
+
+func (s *Server) showSubscription(r *http.Request, body []byte, ids []string) *response {
+	sub, rsp := s.findSubscription(ids[0])
+	if rsp != nil {
+		return rsp
+	}
+	return &response{status: http.StatusOK, body: sub.Subscription}
+}
+
+func (s *Server) activateSubscription(r *http.Request, body []byte, ids []string) *response {
+	return s.subscriptionAction(ids[0], body, paypalsdk.E_SUBSCRIPTION_ACTION_ACTIVATE, paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_ACTIVATED)
+}
+
+func (s *Server) suspendSubscription(r *http.Request, body []byte, ids []string) *response {
+	return s.subscriptionAction(ids[0], body, paypalsdk.E_SUBSCRIPTION_ACTION_SUSPEND, paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_SUSPENDED)
+}
+
+func (s *Server) cancelSubscription(r *http.Request, body []byte, ids []string) *response {
+	return s.subscriptionAction(ids[0], body, paypalsdk.E_SUBSCRIPTION_ACTION_CANCEL, paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_CANCELLED)
+}
+
+func (s *Server) subscriptionAction(id string, body []byte, action paypalsdk.E_SubscriptionAction, eventType string) *response {
+	q := &paypalsdk.UpdateSubscriptionReq{}
+	if rsp := decodeBody(body, q); rsp != nil {
+		return rsp
+	}
+	if rsp := validationResponse(q.Validate()); rsp != nil {
+		return rsp
+	}
+	sub, rsp := s.findSubscription(id)
+	if rsp != nil {
+		return rsp
+	}
+	if !action.CanApply(sub.Status) {
+		return unprocessable("SUBSCRIPTION_STATUS_INVALID", fmt.Sprintf("Invalid subscription status for %s action; subscription status is %s.", action, sub.Status))
+	}
+	s.setStatus(sub, action.Status(), q.Reason)
+	return &response{
+		status: http.StatusNoContent,
+		events: s.newEvents(eventType, paypalsdk.E_EVENT_RESOURCE_TYPE_SUBCRIPTION, sub.Subscription),
+	}
+}
+
+func (s *Server) setStatus(sub *subscription, status paypalsdk.E_SubscriptionStatus, note string) {
+	sub.Status = status
+	sub.StatusChangeNote = note
+	sub.StatusUpdateTime = s.now()
+	sub.UpdateTime = sub.StatusUpdateTime
+	if status == paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED || status == paypalsdk.E_SUBSCRIPTION_STATUS_EXPIRED {
+		sub.BillingInfo.NextBillingTime = time.Time{}
+	}
+	// 只有等待批准时才有 approve 链接
+	if status != paypalsdk.E_SUBSCRIPTION_STATUS_APPROVAL_PENDING {
+		links := sub.Links[:0]
+		for _, l := range sub.Links {
+			if l.Rel != paypalsdk.E_LINK_REL_APPROVE {
+				links = append(links, l)
+			}
+		}
+		sub.Links = links
+	}
+}
+
+func (s *Server) listSubscriptionTransactions(r *http.Request, body []byte, ids []string) *response {
+	query := r.URL.Query()
+	var details []*errorDetail
+	start, err := time.Parse(time.RFC3339, query.Get("start_time"))
+	if err != nil {
+		details = append(details, &errorDetail{Field: "start_time", Value: query.Get("start_time"), Location: "query", Issue: "INVALID_PARAMETER_SYNTAX", Description: "The value of a field does not conform to the expected format."})
+	}
+	end, err := time.Parse(time.RFC3339, query.Get("end_time"))
+	if err != nil {
+		details = append(details, &errorDetail{Field: "end_time", Value: query.Get("end_time"), Location: "query", Issue: "INVALID_PARAMETER_SYNTAX", Description: "The value of a field does not conform to the expected format."})
+	}
+	if len(details) > 0 {
+		return invalidRequest(details...)
+	}
+	sub, rsp := s.findSubscription(ids[0])
+	if rsp != nil {
+		return rsp
+	}
+	list := []*paypalsdk.SubTransaction{}
+	for _, tx := range sub.transactions {
+		if !tx.Time.Before(start) && !tx.Time.After(end) {
+			list = append(list, tx)
+		}
+	}
+	return &response{status: http.StatusOK, body: &paypalsdk.ListTransactionRsp{
+		Transactions: list,
+		TotalItems:   len(list),
+		TotalPages:   1,
+	}}
+}
+
+func (s *Server) findSubscription(id string) (*subscription, *response) {
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return nil, notFound("INVALID_RESOURCE_ID", "Requested resource ID was not found.")
+	}
+	return sub, nil
+}
+
+var (
+	ErrSubscriptionNotFound      = errors.New("paypaltest: subscription not found")
+	ErrSubscriptionStatusInvalid = errors.New("paypaltest: subscription status invalid for the action")
+)
+
+// Subscription returns a copy of the subscription as the fake sees it, nil if it does not exist
+func (s *Server) Subscription(id string) *paypalsdk.Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return nil
+	}
+	cp := *sub.Subscription
+	return &cp
+}
+
+// ApproveSubscription does what the buyer does on the approve link of an APPROVAL_PENDING subscription.
+// The subscription becomes ACTIVE and the first cycle is charged, or APPROVED if the user_action was CONTINUE
+func (s *Server) ApproveSubscription(id string) error {
+	s.mu.Lock()
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		s.mu.Unlock()
+		return ErrSubscriptionNotFound
+	}
+	if sub.Status != paypalsdk.E_SUBSCRIPTION_STATUS_APPROVAL_PENDING {
+		s.mu.Unlock()
+		return ErrSubscriptionStatusInvalid
+	}
+	var events []*paypalsdk.Event
+	if sub.userAction == paypalsdk.E_USER_ACTION_CONTINUE {
+		s.setStatus(sub, paypalsdk.E_SUBSCRIPTION_STATUS_APPROVAL, "")
+	} else {
+		s.setStatus(sub, paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, "")
+		events = append(events, s.newEvents(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_ACTIVATED, paypalsdk.E_EVENT_RESOURCE_TYPE_SUBCRIPTION, sub.Subscription)...)
+		events = append(events, s.charge(sub)...)
+	}
+	s.mu.Unlock()
+
+	s.deliver(events)
+	return nil
+}
+
+// ChargeSubscription bills the current cycle of an ACTIVE subscription, as PayPal does on next_billing_time.
+// A PAYMENT.SALE.COMPLETED event is emitted unless the cycle is free, and the subscription expires after its last cycle
+func (s *Server) ChargeSubscription(id string) error {
+	s.mu.Lock()
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		s.mu.Unlock()
+		return ErrSubscriptionNotFound
+	}
+	if sub.Status != paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE {
+		s.mu.Unlock()
+		return ErrSubscriptionStatusInvalid
+	}
+	events := s.charge(sub)
+	s.mu.Unlock()
+
+	s.deliver(events)
+	return nil
+}
+
+// FailSubscriptionPayment makes the payment of the current cycle of an ACTIVE subscription fail.
+// The subscription is suspended when payment_failure_threshold of the plan is reached
+func (s *Server) FailSubscriptionPayment(id string) error {
+	s.mu.Lock()
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		s.mu.Unlock()
+		return ErrSubscriptionNotFound
+	}
+	if sub.Status != paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE {
+		s.mu.Unlock()
+		return ErrSubscriptionStatusInvalid
+	}
+	now := s.now()
+	amount := paypalsdk.NewMoney(sub.BillingInfo.OutstandingBalance.CurrencyCode, 0)
+	if bc, _ := s.currentCycle(sub); bc != nil {
+		amount = s.cycleAmount(sub, bc)
+	}
+	info := sub.BillingInfo
+	info.FailedPaymentsCount++
+	info.LastFailedPayment = &paypalsdk.FailedPaymentDetails{
+		Amount:               amount,
+		Time:                 now.Format(time.RFC3339),
+		NextPaymentRetryTime: now.Add(5 * 24 * time.Hour).Format(time.RFC3339),
+	}
+	if balance, err := info.OutstandingBalance.Add(amount); err == nil {
+		info.OutstandingBalance = balance
+	}
+	sub.UpdateTime = now
+	events := s.newEvents(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_PAYMENT_FAILED, paypalsdk.E_EVENT_RESOURCE_TYPE_SUBCRIPTION, sub.Subscription)
+
+	threshold := 0
+	if sub.plan.PaymentPreferences != nil {
+		threshold = sub.plan.PaymentPreferences.PaymentFailureThreshold
+	}
+	if threshold > 0 && info.FailedPaymentsCount >= threshold {
+		s.setStatus(sub, paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED, "Payment failure threshold reached")
+		events = append(events, s.newEvents(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_SUSPENDED, paypalsdk.E_EVENT_RESOURCE_TYPE_SUBCRIPTION, sub.Subscription)...)
+	}
+	s.mu.Unlock()
+
+	s.deliver(events)
+	return nil
+}
+
+// currentCycle returns the plan's billing cycle to charge next and its execution, nil if every cycle is completed
+func (s *Server) currentCycle(sub *subscription) (*paypalsdk.BillingCycle, *paypalsdk.CycleExecutions) {
+	for _, ce := range sub.BillingInfo.CycleExecutions {
+		if ce.TotalCycles != 0 && ce.CyclesCompleted >= ce.TotalCycles {
+			continue
+		}
+		for _, bc := range sub.plan.BillingCycles {
+			if bc.Sequence == ce.Sequence {
+				return bc, ce
+			}
+		}
+	}
+	return nil, nil
+}
+
+// cycleAmount returns the price of bc multiplied by the quantity of sub
+func (s *Server) cycleAmount(sub *subscription, bc *paypalsdk.BillingCycle) *paypalsdk.Money {
+	currency := sub.BillingInfo.OutstandingBalance.CurrencyCode
+	if bc.PricingScheme == nil || bc.PricingScheme.FixedPrice == nil {
+		return paypalsdk.NewMoney(currency, 0)
+	}
+	var quantity int64 = 1
+	fmt.Sscan(sub.Quantity, &quantity)
+	amount, err := bc.PricingScheme.FixedPrice.Mul(quantity)
+	if err != nil {
+		return paypalsdk.NewMoney(currency, 0)
+	}
+	return amount
+}
+
+// charge bills the current cycle of sub, must be called with s.mu held
+func (s *Server) charge(sub *subscription) []*paypalsdk.Event {
+	now := s.now()
+	info := sub.BillingInfo
+	bc, ce := s.currentCycle(sub)
+	if bc == nil {
+		s.setStatus(sub, paypalsdk.E_SUBSCRIPTION_STATUS_EXPIRED, "")
+		return s.newEvents(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_EXPIRED, paypalsdk.E_EVENT_RESOURCE_TYPE_SUBCRIPTION, sub.Subscription)
+	}
+
+	var events []*paypalsdk.Event
+	amount := s.cycleAmount(sub, bc)
+	if !amount.IsZero() {
+		// 模拟的手续费: 2.9% + 0.30
+		minor, _ := amount.MinorUnits()
+		fee := paypalsdk.NewMoney(amount.CurrencyCode, minor*29/1000+30)
+		net, _ := amount.Sub(fee)
+		saleID := s.nextID("", 17)
+		sub.transactions = append(sub.transactions, &paypalsdk.SubTransaction{
+			Status: paypalsdk.E_TRANSACTION_STATUS_COMPLETED,
+			ID:     saleID,
+			AmountWithBreakdown: &paypalsdk.AmountWithBreakdown{
+				GrossAmount:    *amount,
+				FeeAmount:      *fee,
+				ShippingAmount: *paypalsdk.NewMoney(amount.CurrencyCode, 0),
+				TaxAmount:      *paypalsdk.NewMoney(amount.CurrencyCode, 0),
+				NetAmount:      *net,
+			},
+			Time: now,
+		})
+		info.LastPayment = &paypalsdk.LastPaymentDetails{Amount: amount, Time: now.Format(time.RFC3339)}
+		sale := &paypalsdk.Sale{
+			Id:                 saleID,
+			Amount:             amount.ToAmount(),
+			PaymentMode:        "INSTANT_TRANSFER",
+			State:              paypalsdk.E_SALE_STATE_COMPLETED,
+			TransactionFee:     fee.ToCurrency(),
+			BillingAgreementId: sub.ID,
+			CreateTime:         now.Format(time.RFC3339),
+			UpdateTime:         now.Format(time.RFC3339),
+			Links: []*paypalsdk.Link{
+				{Href: s.URL + paypalsdk.K_SALE_API + "/" + saleID, Rel: paypalsdk.E_LINK_REL_SELF, Method: "GET"},
+				{Href: s.URL + paypalsdk.K_SALE_API + "/" + saleID + "/refund", Rel: paypalsdk.E_LINK_REL_REFUND, Method: "POST"},
+			},
+		}
+		events = append(events, s.newEvents(paypalsdk.E_EVENT_TYPE_PAYMENT_SALE_COMPLETED, paypalsdk.E_EVENT_RESOURCE_TYPE_SALE, sale)...)
+	}
+
+	ce.CyclesCompleted++
+	if ce.TotalCycles != 0 {
+		ce.CyclesRemaining = ce.TotalCycles - ce.CyclesCompleted
+	}
+	info.FailedPaymentsCount = 0
+	info.NextBillingTime = nextBillingTime(now, bc.Frequency)
+	if next, _ := s.currentCycle(sub); next == nil {
+		info.NextBillingTime = time.Time{}
+		info.FinalPaymentTime = now
+	}
+	sub.UpdateTime = now
+	return events
+}
+
+func nextBillingTime(from time.Time, f *paypalsdk.Frequency) time.Time {
+	count := 1
+	if f != nil && f.IntervalCount > 0 {
+		count = f.IntervalCount
+	}
+	unit := paypalsdk.E_FREQUENCY_INTERVAL_MONTH
+	if f != nil {
+		unit = f.IntervalUnit
+	}
+	switch unit {
+	case paypalsdk.E_FREQUENCY_INTERVAL_DAY:
+		return from.AddDate(0, 0, count)
+	case paypalsdk.E_FREQUENCY_INTERVAL_WEEK:
+		return from.AddDate(0, 0, 7*count)
+	case paypalsdk.E_FREQUENCY_INTERVAL_YEAR:
+		return from.AddDate(count, 0, 0)
+	}
+	return from.AddDate(0, count, 0)
+}
@@ -0,0 +1,393 @@
+package paypaltest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+// regularPlan returns a plan billing 10.00 USD every month until cancelled
+func regularPlan(failureThreshold int) map[string]interface{} {
+	return plan(failureThreshold, cycle("REGULAR", 1, 0, "10.00"))
+}
+
+func plan(failureThreshold int, cycles ...map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"name":                "Monthly",
+		"billing_cycles":      cycles,
+		"payment_preferences": map[string]interface{}{"payment_failure_threshold": failureThreshold},
+	}
+}
+
+// cycle returns a monthly billing cycle, a free one if price is empty
+func cycle(tenureType string, sequence, totalCycles int, price string) map[string]interface{} {
+	c := map[string]interface{}{
+		"tenure_type":  tenureType,
+		"sequence":     sequence,
+		"total_cycles": totalCycles,
+		"frequency":    map[string]interface{}{"interval_unit": "MONTH", "interval_count": 1},
+	}
+	if price != "" {
+		c["pricing_scheme"] = map[string]interface{}{"fixed_price": map[string]string{"currency_code": "USD", "value": price}}
+	}
+	return c
+}
+
+// newPlan creates a product and the plan p of it, and returns the ID of the plan
+func newPlan(t *testing.T, c *paypalsdk.Client, p map[string]interface{}) string {
+	t.Helper()
+	var created struct {
+		ID string `json:"id"`
+	}
+	status, body := do(t, c, "POST", "/v1/catalogs/products", map[string]string{"name": "Video streaming", "type": "SERVICE"})
+	if status != http.StatusCreated || json.Unmarshal(body, &created) != nil {
+		t.Fatalf("create product: %d %s", status, body)
+	}
+	p["product_id"] = created.ID
+	status, body = do(t, c, "POST", "/v1/billing/plans", p)
+	if status != http.StatusCreated || json.Unmarshal(body, &created) != nil {
+		t.Fatalf("create plan: %d %s", status, body)
+	}
+	return created.ID
+}
+
+func subscribe(t *testing.T, c *paypalsdk.Client, planID string, ctx *paypalsdk.ApplicationContext) *paypalsdk.Subscription {
+	t.Helper()
+	sub, err := c.CreateSubscription(&paypalsdk.CreateSubscriptionReq{PlanID: planID, ApplicationContext: ctx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sub
+}
+
+// userAction returns the application context of a subscription created with the user action a
+func userAction(a paypalsdk.E_UserAction) *paypalsdk.ApplicationContext {
+	return &paypalsdk.ApplicationContext{UserAction: a, ReturnUrl: "https://example.com/return", CancelUrl: "https://example.com/cancel"}
+}
+
+// lastEventType returns the type of the last event emitted by s
+func lastEventType(s *Server) string {
+	events := s.Events()
+	if len(events) == 0 {
+		return ""
+	}
+	return events[len(events)-1].EventType
+}
+
+func TestSubscriptionActions(t *testing.T) {
+	const (
+		approve  = "approve"
+		activate = "activate"
+		suspend  = "suspend"
+		cancel   = "cancel"
+		charge   = "charge"
+	)
+	tests := []struct {
+		name       string
+		continued  bool     // user_action 为 CONTINUE, 批准后为 APPROVED
+		setup      []string // 依次执行, 必须成功
+		action     string
+		wantCode   int // 0 表示成功
+		wantStatus paypalsdk.E_SubscriptionStatus
+		wantEvent  string
+	}{
+		{"activate pending", false, nil, activate, 422, paypalsdk.E_SUBSCRIPTION_STATUS_APPROVAL_PENDING, ""},
+		{"suspend pending", false, nil, suspend, 422, paypalsdk.E_SUBSCRIPTION_STATUS_APPROVAL_PENDING, ""},
+		{"cancel pending", false, nil, cancel, 422, paypalsdk.E_SUBSCRIPTION_STATUS_APPROVAL_PENDING, ""},
+		{"activate approved", true, []string{approve}, activate, 0, paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_ACTIVATED},
+		{"cancel approved", true, []string{approve}, cancel, 0, paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED, paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_CANCELLED},
+		{"activate active", false, []string{approve}, activate, 422, paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, ""},
+		{"suspend active", false, []string{approve}, suspend, 0, paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED, paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_SUSPENDED},
+		{"cancel active", false, []string{approve}, cancel, 0, paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED, paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_CANCELLED},
+		{"activate suspended", false, []string{approve, suspend}, activate, 0, paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_ACTIVATED},
+		{"suspend suspended", false, []string{approve, suspend}, suspend, 422, paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED, ""},
+		{"cancel suspended", false, []string{approve, suspend}, cancel, 0, paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED, paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_CANCELLED},
+		{"activate cancelled", false, []string{approve, cancel}, activate, 422, paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED, ""},
+		{"suspend cancelled", false, []string{approve, cancel}, suspend, 422, paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED, ""},
+		{"cancel cancelled", false, []string{approve, cancel}, cancel, 422, paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED, ""},
+		{"activate expired", false, []string{approve, charge}, activate, 422, paypalsdk.E_SUBSCRIPTION_STATUS_EXPIRED, ""},
+		{"cancel expired", false, []string{approve, charge}, cancel, 422, paypalsdk.E_SUBSCRIPTION_STATUS_EXPIRED, ""},
+	}
+	for _, tt := range tests {
+		s, c, _ := newServer(t)
+		// 只有一个周期, 批准时扣款后再扣款即到期
+		planID := newPlan(t, c, plan(0, cycle("REGULAR", 1, 1, "10.00")))
+		ctx := userAction(paypalsdk.E_USER_ACTION_SUBSCRIBE_NOW)
+		if tt.continued {
+			ctx = userAction(paypalsdk.E_USER_ACTION_CONTINUE)
+		}
+		sub := subscribe(t, c, planID, ctx)
+
+		run := func(action string) error {
+			switch action {
+			case approve:
+				return s.ApproveSubscription(sub.ID)
+			case activate:
+				return c.ActivateSubscription(sub.ID, "Reactivating the subscription")
+			case suspend:
+				return c.SuspendSubscription(sub.ID, "Customer request")
+			case cancel:
+				return c.CancelSubscription(sub.ID, "Customer request")
+			case charge:
+				return s.ChargeSubscription(sub.ID)
+			}
+			t.Fatalf("unknown action %s", action)
+			return nil
+		}
+		for _, step := range tt.setup {
+			if err := run(step); err != nil {
+				t.Fatalf("%s: %s: %v", tt.name, step, err)
+			}
+		}
+		events := len(s.Events())
+
+		err := run(tt.action)
+		if got := statusCode(err); got != tt.wantCode {
+			t.Errorf("%s: status code = %d, want %d (error %v)", tt.name, got, tt.wantCode, err)
+		}
+		if e, ok := err.(*paypalsdk.ResponseError); ok && e.Name != "UNPROCESSABLE_ENTITY" {
+			t.Errorf("%s: error name = %s", tt.name, e.Name)
+		}
+		if got := s.Subscription(sub.ID).Status; got != tt.wantStatus {
+			t.Errorf("%s: status = %s, want %s", tt.name, got, tt.wantStatus)
+		}
+		if tt.wantEvent == "" && len(s.Events()) != events {
+			t.Errorf("%s: rejected action emitted %s", tt.name, lastEventType(s))
+		}
+		if tt.wantEvent != "" && lastEventType(s) != tt.wantEvent {
+			t.Errorf("%s: last event = %s, want %s", tt.name, lastEventType(s), tt.wantEvent)
+		}
+	}
+
+	_, c, _ := newServer(t)
+	if err := c.CancelSubscription("I-404", "Customer request"); statusCode(err) != http.StatusNotFound {
+		t.Errorf("CancelSubscription of an unknown subscription error = %v, want 404", err)
+	}
+}
+
+func TestCreateSubscriptionErrors(t *testing.T) {
+	_, c, _ := newServer(t)
+	active := newPlan(t, c, regularPlan(0))
+	inactive := newPlan(t, c, regularPlan(0))
+	if status, body := do(t, c, "POST", "/v1/billing/plans/"+inactive+"/deactivate", nil); status != http.StatusNoContent {
+		t.Fatalf("deactivate plan: %d %s", status, body)
+	}
+
+	tests := []struct {
+		name      string
+		req       map[string]interface{}
+		want      int
+		wantName  string
+		wantIssue string
+		wantField string
+	}{
+		{"created", map[string]interface{}{"plan_id": active, "start_time": "2026-02-01T00:00:00Z"}, http.StatusCreated, "", "", ""},
+		{"malformed start_time", map[string]interface{}{"plan_id": active, "start_time": "2026-02-30"}, http.StatusBadRequest, "INVALID_REQUEST", "INVALID_PARAMETER_SYNTAX", "/start_time"},
+		{"no plan_id", map[string]interface{}{}, http.StatusBadRequest, "INVALID_REQUEST", "MISSING_REQUIRED_PARAMETER", "/plan_id"},
+		{"malformed json", nil, http.StatusBadRequest, "INVALID_REQUEST", "MALFORMED_REQUEST_JSON", ""},
+		{"unknown plan", map[string]interface{}{"plan_id": "P-404"}, http.StatusNotFound, "RESOURCE_NOT_FOUND", "INVALID_RESOURCE_ID", ""},
+		{"inactive plan", map[string]interface{}{"plan_id": inactive}, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", "PLAN_STATUS_INVALID", ""},
+		{"quantity not supported", map[string]interface{}{"plan_id": active, "quantity": "2"}, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", "SUBSCRIPTION_UNSUPPORTED_QUANTITY", ""},
+	}
+	for _, tt := range tests {
+		var payload interface{} = tt.req
+		if tt.req == nil {
+			payload = "not an object"
+		}
+		status, body := do(t, c, "POST", paypalsdk.K_SUBSCRIPTION_API, payload)
+		if status != tt.want {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, status, tt.want, body)
+			continue
+		}
+		if tt.wantName == "" {
+			sub := &paypalsdk.Subscription{}
+			if err := json.Unmarshal(body, sub); err != nil {
+				t.Fatal(err)
+			}
+			if want := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC); !sub.StartTime.Equal(want) || !sub.BillingInfo.NextBillingTime.Equal(want) {
+				t.Errorf("%s: start_time = %v, next_billing_time = %v, want %v", tt.name, sub.StartTime, sub.BillingInfo.NextBillingTime, want)
+			}
+			continue
+		}
+		e := &errorBody{}
+		if err := json.Unmarshal(body, e); err != nil {
+			t.Fatal(err)
+		}
+		if e.Name != tt.wantName || len(e.Details) == 0 || e.Details[0].Issue != tt.wantIssue || e.Details[0].Field != tt.wantField {
+			t.Errorf("%s: error = %s, want %s %s on %q", tt.name, body, tt.wantName, tt.wantIssue, tt.wantField)
+		}
+	}
+}
+
+func TestBillingCycles(t *testing.T) {
+	s, c, now := newServer(t)
+	start := *now
+	planID := newPlan(t, c, plan(0, cycle("TRIAL", 1, 1, ""), cycle("REGULAR", 2, 2, "10.00")))
+	sub := subscribe(t, c, planID, nil)
+
+	// 试用期免费, 批准时不产生交易
+	if err := s.ApproveSubscription(sub.ID); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		wantStatus    paypalsdk.E_SubscriptionStatus
+		wantEvent     string
+		wantCompleted []int // 每个周期已完成的次数
+		wantNext      time.Time
+	}{
+		{paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_ACTIVATED, []int{1, 0}, start.AddDate(0, 1, 0)},
+		{paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, paypalsdk.E_EVENT_TYPE_PAYMENT_SALE_COMPLETED, []int{1, 1}, start.AddDate(0, 2, 0)},
+		{paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, paypalsdk.E_EVENT_TYPE_PAYMENT_SALE_COMPLETED, []int{1, 2}, time.Time{}},
+		{paypalsdk.E_SUBSCRIPTION_STATUS_EXPIRED, paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_EXPIRED, []int{1, 2}, time.Time{}},
+	}
+	for i, tt := range tests {
+		if i > 0 {
+			*now = start.AddDate(0, i, 0)
+			if err := s.ChargeSubscription(sub.ID); err != nil {
+				t.Fatalf("charge %d: %v", i, err)
+			}
+		}
+		got := s.Subscription(sub.ID)
+		if got.Status != tt.wantStatus || lastEventType(s) != tt.wantEvent {
+			t.Errorf("charge %d: status = %s, last event = %s, want %s %s", i, got.Status, lastEventType(s), tt.wantStatus, tt.wantEvent)
+		}
+		for j, ce := range got.BillingInfo.CycleExecutions {
+			if ce.CyclesCompleted != tt.wantCompleted[j] {
+				t.Errorf("charge %d: cycle %d completed %d times, want %d", i, ce.Sequence, ce.CyclesCompleted, tt.wantCompleted[j])
+			}
+		}
+		if !got.BillingInfo.NextBillingTime.Equal(tt.wantNext) {
+			t.Errorf("charge %d: next_billing_time = %v, want %v", i, got.BillingInfo.NextBillingTime, tt.wantNext)
+		}
+	}
+	if err := s.ChargeSubscription(sub.ID); err != ErrSubscriptionStatusInvalid {
+		t.Errorf("ChargeSubscription of an expired subscription error = %v, want ErrSubscriptionStatusInvalid", err)
+	}
+
+	got := s.Subscription(sub.ID)
+	if got.BillingInfo.LastPayment == nil || got.BillingInfo.LastPayment.Amount.Value != "10.00" || got.BillingInfo.LastPayment.Time != start.AddDate(0, 2, 0).Format(time.RFC3339) {
+		t.Errorf("last_payment = %+v", got.BillingInfo.LastPayment)
+	}
+	if !got.BillingInfo.FinalPaymentTime.Equal(start.AddDate(0, 2, 0)) {
+		t.Errorf("final_payment_time = %v", got.BillingInfo.FinalPaymentTime)
+	}
+	// 时钟已走过令牌的有效期
+	if _, err := c.GetAccessToken(); err != nil {
+		t.Fatal(err)
+	}
+	txs, err := c.ListTransactionsForSubscription(sub.ID, start.Format(time.RFC3339), now.Format(time.RFC3339))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs.Transactions) != 2 {
+		t.Fatalf("%d transactions, want 2", len(txs.Transactions))
+	}
+	if b := txs.Transactions[0].AmountWithBreakdown; b.GrossAmount.Value != "10.00" || b.FeeAmount.Value != "0.59" || b.NetAmount.Value != "9.41" {
+		t.Errorf("transaction amounts = %+v", b)
+	}
+	if _, err := c.ListTransactionsForSubscription(sub.ID, "yesterday", now.Format(time.RFC3339)); statusCode(err) != http.StatusBadRequest {
+		t.Errorf("ListTransactionsForSubscription with a malformed start_time error = %v, want 400", err)
+	}
+}
+
+func TestFailSubscriptionPayment(t *testing.T) {
+	tests := []struct {
+		threshold  int
+		failures   int
+		wantStatus paypalsdk.E_SubscriptionStatus
+		wantEvent  string
+	}{
+		{0, 3, paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_PAYMENT_FAILED},
+		{1, 1, paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED, paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_SUSPENDED},
+		{2, 1, paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_PAYMENT_FAILED},
+		{2, 2, paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED, paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_SUSPENDED},
+	}
+	for _, tt := range tests {
+		s, c, _ := newServer(t)
+		sub := subscribe(t, c, newPlan(t, c, regularPlan(tt.threshold)), nil)
+		if err := s.ApproveSubscription(sub.ID); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < tt.failures; i++ {
+			if err := s.FailSubscriptionPayment(sub.ID); err != nil {
+				t.Fatalf("threshold %d: failure %d: %v", tt.threshold, i+1, err)
+			}
+		}
+		got := s.Subscription(sub.ID)
+		if got.Status != tt.wantStatus || lastEventType(s) != tt.wantEvent {
+			t.Errorf("threshold %d, %d failures: status = %s, last event = %s, want %s %s",
+				tt.threshold, tt.failures, got.Status, lastEventType(s), tt.wantStatus, tt.wantEvent)
+		}
+		info := got.BillingInfo
+		if info.FailedPaymentsCount != tt.failures || info.LastFailedPayment == nil || info.LastFailedPayment.Amount.Value != "10.00" {
+			t.Errorf("threshold %d, %d failures: failed_payments_count = %d, last_failed_payment = %+v",
+				tt.threshold, tt.failures, info.FailedPaymentsCount, info.LastFailedPayment)
+		}
+		if c, _ := info.OutstandingBalance.Compare(paypalsdk.NewMoney("USD", int64(tt.failures)*1000)); c != 0 {
+			t.Errorf("threshold %d, %d failures: outstanding_balance = %s", tt.threshold, tt.failures, info.OutstandingBalance)
+		}
+
+		if tt.wantStatus == paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED {
+			if err := s.FailSubscriptionPayment(sub.ID); err != ErrSubscriptionStatusInvalid {
+				t.Errorf("FailSubscriptionPayment of a suspended subscription error = %v", err)
+			}
+			if err := c.ActivateSubscription(sub.ID, "Payment method updated"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// 扣款成功后失败次数清零
+		if err := s.ChargeSubscription(sub.ID); err != nil {
+			t.Fatal(err)
+		}
+		if n := s.Subscription(sub.ID).BillingInfo.FailedPaymentsCount; n != 0 {
+			t.Errorf("threshold %d: failed_payments_count after a payment = %d", tt.threshold, n)
+		}
+	}
+}
+
+func TestApproveSubscription(t *testing.T) {
+	s, c, _ := newServer(t)
+	planID := newPlan(t, c, regularPlan(0))
+
+	if err := s.ApproveSubscription("I-404"); err != ErrSubscriptionNotFound {
+		t.Errorf("ApproveSubscription of an unknown subscription error = %v", err)
+	}
+	for _, err := range []error{s.ChargeSubscription("I-404"), s.FailSubscriptionPayment("I-404")} {
+		if err != ErrSubscriptionNotFound {
+			t.Errorf("action on an unknown subscription error = %v, want ErrSubscriptionNotFound", err)
+		}
+	}
+
+	sub := subscribe(t, c, planID, userAction(paypalsdk.E_USER_ACTION_CONTINUE))
+	if sub.Status != paypalsdk.E_SUBSCRIPTION_STATUS_APPROVAL_PENDING || sub.FindLink(paypalsdk.E_LINK_REL_APPROVE) == nil {
+		t.Errorf("created subscription status = %s, links = %v", sub.Status, sub.Links)
+	}
+	if err := s.ChargeSubscription(sub.ID); err != ErrSubscriptionStatusInvalid {
+		t.Errorf("ChargeSubscription of a pending subscription error = %v", err)
+	}
+	if err := s.ApproveSubscription(sub.ID); err != nil {
+		t.Fatal(err)
+	}
+	// CONTINUE 时批准后由商户激活, 不扣款
+	got := s.Subscription(sub.ID)
+	if got.Status != paypalsdk.E_SUBSCRIPTION_STATUS_APPROVAL || got.BillingInfo.LastPayment != nil {
+		t.Errorf("approved subscription status = %s, last_payment = %+v", got.Status, got.BillingInfo.LastPayment)
+	}
+	if got.FindLink(paypalsdk.E_LINK_REL_APPROVE) != nil {
+		t.Error("approved subscription still has an approve link")
+	}
+	if err := s.ApproveSubscription(sub.ID); err != ErrSubscriptionStatusInvalid {
+		t.Errorf("second ApproveSubscription error = %v, want ErrSubscriptionStatusInvalid", err)
+	}
+
+	sub = subscribe(t, c, planID, nil)
+	if err := s.ApproveSubscription(sub.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Subscription(sub.ID); got.Status != paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE || got.BillingInfo.LastPayment == nil {
+		t.Errorf("approved subscription status = %s, last_payment = %+v", got.Status, got.BillingInfo.LastPayment)
+	}
+}
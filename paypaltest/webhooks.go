@@ -0,0 +1,413 @@
+package paypaltest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+// PayPal 限制每个应用最多 10 个 webhook
+const kMaxWebhooks = 10
+
+// Delivery is a webhook event delivered by the fake
+type Delivery struct {
+	Event      *paypalsdk.Event
+	WebhookID  string
+	URL        string
+	StatusCode int   // 0 if the request failed
+	Err        error // the error of the request
+}
+
+// Deliveries returns every delivery so far in order
+func (s *Server) Deliveries() []*Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Delivery(nil), s.deliveries...)
+}
+
+func (s *Server) createWebhook(r *http.Request, body []byte, ids []string) *response {
+	q := &paypalsdk.CreateWebhookReq{}
+	if rsp := decodeBody(body, q); rsp != nil {
+		return rsp
+	}
+	// 测试里的接收方是 httptest.Server, 本机地址允许用 http
+	checked := *q
+	if u, err := url.Parse(q.Url); err == nil && u.Scheme == "http" && isLoopback(u.Hostname()) {
+		u.Scheme = "https"
+		checked.Url = u.String()
+	}
+	if rsp := validationResponse(checked.Validate()); rsp != nil {
+		return rsp
+	}
+	for _, id := range s.webhookOrder {
+		if s.webhooks[id].Url == q.Url {
+			return errorResponse(http.StatusBadRequest, "WEBHOOK_URL_ALREADY_EXISTS", "Webhook URL already exists.")
+		}
+	}
+	if len(s.webhookOrder) >= kMaxWebhooks {
+		return errorResponse(http.StatusBadRequest, "WEBHOOK_NUMBER_LIMIT_EXCEEDED", "Maximum number of webhooks exceeded.")
+	}
+
+	id := s.nextID("WH-", 17)
+	links := []paypalsdk.LinkDescription{
+		*s.link(paypalsdk.E_LINK_REL_SELF, "GET", "/v1/notifications/webhooks/"+id),
+		*s.link(paypalsdk.E_LINK_REL_UPDATE, "PATCH", "/v1/notifications/webhooks/"+id),
+		*s.link(paypalsdk.E_LINK_REL_DELETE, "DELETE", "/v1/notifications/webhooks/"+id),
+	}
+	wh := &paypalsdk.Webhook{ID: id, CreateWebhookReq: q, Links: &links}
+	s.webhooks[id] = wh
+	s.webhookOrder = append(s.webhookOrder, id)
+	return &response{status: http.StatusCreated, body: wh}
+}
+
+func (s *Server) listWebhooks(r *http.Request, body []byte, ids []string) *response {
+	list := make([]*paypalsdk.Webhook, 0, len(s.webhookOrder))
+	for _, id := range s.webhookOrder {
+		list = append(list, s.webhooks[id])
+	}
+	return &response{status: http.StatusOK, body: map[string]interface{}{"webhooks": list}}
+}
+
+func (s *Server) showWebhook(r *http.Request, body []byte, ids []string) *response {
+	wh, ok := s.webhooks[ids[0]]
+	if !ok {
+		return notFound("INVALID_RESOURCE_ID", "Webhook id does not exist.")
+	}
+	return &response{status: http.StatusOK, body: wh}
+}
+
+func (s *Server) deleteWebhook(r *http.Request, body []byte, ids []string) *response {
+	if _, ok := s.webhooks[ids[0]]; !ok {
+		return notFound("INVALID_RESOURCE_ID", "Webhook id does not exist.")
+	}
+	delete(s.webhooks, ids[0])
+	order := s.webhookOrder[:0]
+	for _, id := range s.webhookOrder {
+		if id != ids[0] {
+			order = append(order, id)
+		}
+	}
+	s.webhookOrder = order
+	return &response{status: http.StatusNoContent}
+}
+
+func (s *Server) updateWebhook(r *http.Request, body []byte, ids []string) *response {
+	wh, ok := s.webhooks[ids[0]]
+	if !ok {
+		return notFound("INVALID_RESOURCE_ID", "Webhook id does not exist.")
+	}
+	var patches []struct {
+		Op    paypalsdk.E_PatchOp `json:"op"`
+		Path  string              `json:"path"`
+		Value json.RawMessage     `json:"value"`
+	}
+	if rsp := decodeBody(body, &patches); rsp != nil {
+		return rsp
+	}
+	// 先在副本上修改, 全部成功后再保存
+	q := *wh.CreateWebhookReq
+	for i, p := range patches {
+		field := fmt.Sprintf("/%d/path", i)
+		if p.Op != paypalsdk.E_PATCH_OP_REPLACE {
+			return invalidRequest(&errorDetail{Field: fmt.Sprintf("/%d/op", i), Value: string(p.Op), Location: "body", Issue: "INVALID_PARAMETER_VALUE", Description: "The value of a field is invalid."})
+		}
+		var err error
+		switch p.Path {
+		case "/url":
+			err = json.Unmarshal(p.Value, &q.Url)
+		case "/event_types":
+			err = json.Unmarshal(p.Value, &q.EventTypes)
+		default:
+			return invalidRequest(&errorDetail{Field: field, Value: p.Path, Location: "body", Issue: "INVALID_PARAMETER_VALUE", Description: "The value of a field is invalid."})
+		}
+		if err != nil {
+			return invalidRequest(&errorDetail{Field: fmt.Sprintf("/%d/value", i), Location: "body", Issue: "INVALID_PARAMETER_SYNTAX", Description: "The value of a field does not conform to the expected format."})
+		}
+	}
+	if len(q.EventTypes) == 0 {
+		return invalidRequest(&errorDetail{Field: "/event_types", Location: "body", Issue: "MISSING_REQUIRED_PARAMETER", Description: "A required field / parameter is missing."})
+	}
+	updated := *wh
+	updated.CreateWebhookReq = &q
+	s.webhooks[wh.ID] = &updated
+	return &response{status: http.StatusOK, body: &updated}
+}
+
+func (s *Server) verifyWebhookSignature(r *http.Request, body []byte, ids []string) *response {
+	q := &paypalsdk.VerifyWebhookSignatureReq{}
+	if rsp := decodeBody(body, q); rsp != nil {
+		return rsp
+	}
+	var details []*errorDetail
+	for field, value := range map[string]string{
+		"/auth_algo":         q.AuthAlgo,
+		"/cert_url":          q.CertURL,
+		"/transmission_id":   q.TransmissionID,
+		"/transmission_sig":  q.TransmissionSig,
+		"/transmission_time": q.TransmissionTime,
+		"/webhook_id":        q.WebhookID,
+	} {
+		if value == "" {
+			details = append(details, &errorDetail{Field: field, Location: "body", Issue: "MISSING_REQUIRED_PARAMETER", Description: "A required field / parameter is missing."})
+		}
+	}
+	if len(details) > 0 {
+		return invalidRequest(details...)
+	}
+	status := "FAILURE"
+	if _, ok := s.webhooks[q.WebhookID]; ok {
+		want := s.signature(q.TransmissionID, q.TransmissionTime, q.WebhookID, q.WebhookEvent)
+		if hmac.Equal([]byte(want), []byte(q.TransmissionSig)) {
+			status = "SUCCESS"
+		}
+	}
+	return &response{status: http.StatusOK, body: &paypalsdk.VerifyWebhookSignatureRsp{VerificationStatus: status}}
+}
+
+// newEvents records an event of resource and returns it to be delivered, must be called with s.mu held
+func (s *Server) newEvents(eventType string, resourceType paypalsdk.E_EventResourceType, resource interface{}) []*paypalsdk.Event {
+	// 资源之后还会变化, 事件里保存当时的快照
+	data, _ := json.Marshal(resource)
+	id := s.nextID("WH-", 17) + "-" + s.nextID("", 17)
+	e := &paypalsdk.Event{
+		Id:           id,
+		CreateTime:   s.now(),
+		ResourceType: resourceType,
+		EventVersion: "1.0",
+		EventType:    eventType,
+		Summary:      summary(eventType),
+		Resource:     json.RawMessage(data),
+		Links: []*paypalsdk.Link{
+			{Href: s.URL + "/v1/notifications/webhooks-events/" + id, Rel: paypalsdk.E_LINK_REL_SELF, Method: "GET"},
+			{Href: s.URL + "/v1/notifications/webhooks-events/" + id + "/resend", Rel: paypalsdk.E_LINK_REL_RESEND, Method: "POST"},
+		},
+	}
+	s.events = append(s.events, e)
+	return []*paypalsdk.Event{e}
+}
+
+// listWebhookEvents returns the events newest first, filtered by event_type and transaction_id (the ID of the resource)
+func (s *Server) listWebhookEvents(r *http.Request, body []byte, ids []string) *response {
+	query := r.URL.Query()
+	pageSize := 10
+	if v := query.Get("page_size"); v != "" {
+		if _, err := fmt.Sscan(v, &pageSize); err != nil || pageSize < 1 || pageSize > 300 {
+			return invalidRequest(&errorDetail{Field: "page_size", Value: v, Location: "query", Issue: "INVALID_PARAMETER_VALUE", Description: "The value of a field is invalid."})
+		}
+	}
+	var start, end time.Time
+	for _, p := range []struct {
+		name string
+		t    *time.Time
+	}{{"start_time", &start}, {"end_time", &end}} {
+		if v := query.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return invalidRequest(&errorDetail{Field: p.name, Value: v, Location: "query", Issue: "INVALID_PARAMETER_SYNTAX", Description: "The value of a field does not conform to the expected format."})
+			}
+			*p.t = t
+		}
+	}
+
+	list := []*paypalsdk.Event{}
+	for i := len(s.events) - 1; i >= 0 && len(list) < pageSize; i-- {
+		e := s.events[i]
+		if t := query.Get("event_type"); t != "" && e.EventType != t {
+			continue
+		}
+		if id := query.Get("transaction_id"); id != "" && resourceID(e) != id {
+			continue
+		}
+		if !start.IsZero() && e.CreateTime.Before(start) || !end.IsZero() && e.CreateTime.After(end) {
+			continue
+		}
+		list = append(list, e)
+	}
+	return &response{status: http.StatusOK, body: map[string]interface{}{"events": list, "count": len(list)}}
+}
+
+func (s *Server) showWebhookEvent(r *http.Request, body []byte, ids []string) *response {
+	e := s.findEvent(ids[0])
+	if e == nil {
+		return notFound("INVALID_RESOURCE_ID", "Webhook event id does not exist.")
+	}
+	return &response{status: http.StatusOK, body: e}
+}
+
+// resendWebhookEvent delivers the event again to webhook_ids, or to the webhooks subscribed to it
+func (s *Server) resendWebhookEvent(r *http.Request, body []byte, ids []string) *response {
+	e := s.findEvent(ids[0])
+	if e == nil {
+		return notFound("INVALID_RESOURCE_ID", "Webhook event id does not exist.")
+	}
+	q := &paypalsdk.ResendWebhookEventReq{}
+	if len(body) > 0 {
+		if rsp := decodeBody(body, q); rsp != nil {
+			return rsp
+		}
+	}
+	for _, id := range q.WebhookIDs {
+		if _, ok := s.webhooks[id]; !ok {
+			return notFound("INVALID_RESOURCE_ID", "Webhook id does not exist.")
+		}
+	}
+	return &response{status: http.StatusAccepted, body: e, resend: &resend{event: e, webhookIDs: q.WebhookIDs}}
+}
+
+func (s *Server) findEvent(id string) *paypalsdk.Event {
+	for _, e := range s.events {
+		if e.Id == id {
+			return e
+		}
+	}
+	return nil
+}
+
+// resourceID returns the id of the resource of e
+func resourceID(e *paypalsdk.Event) string {
+	data, ok := e.Resource.(json.RawMessage)
+	if !ok {
+		return ""
+	}
+	v := struct {
+		ID string `json:"id"`
+	}{}
+	json.Unmarshal(data, &v)
+	return v.ID
+}
+
+// summary returns a summary like PayPal's: BILLING.SUBSCRIPTION.CREATED -> Billing subscription created
+func summary(eventType string) string {
+	words := strings.Fields(strings.ToLower(strings.Replace(strings.Replace(eventType, ".", " ", -1), "_", " ", -1)))
+	if len(words) == 0 {
+		return ""
+	}
+	words[0] = strings.ToUpper(words[0][:1]) + words[0][1:]
+	return strings.Join(words, " ")
+}
+
+// Emit emits an event of resource as if PayPal did, for the events the fake does not cause itself
+func (s *Server) Emit(eventType string, resourceType paypalsdk.E_EventResourceType, resource interface{}) {
+	s.mu.Lock()
+	events := s.newEvents(eventType, resourceType, resource)
+	s.mu.Unlock()
+
+	s.deliver(events)
+}
+
+// deliver posts events to the webhooks subscribed to them, must be called without s.mu held
+func (s *Server) deliver(events []*paypalsdk.Event) {
+	for _, e := range events {
+		body, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+		for _, wh := range s.subscribers(e.EventType) {
+			s.post(e, body, wh)
+		}
+	}
+}
+
+// redeliver posts e again to webhookIDs, or to the webhooks subscribed to it, must be called without s.mu held
+func (s *Server) redeliver(r *resend) {
+	body, err := json.Marshal(r.event)
+	if err != nil {
+		return
+	}
+	webhooks := s.subscribers(r.event.EventType)
+	if len(r.webhookIDs) > 0 {
+		s.mu.Lock()
+		webhooks = webhooks[:0]
+		for _, id := range r.webhookIDs {
+			if wh, ok := s.webhooks[id]; ok {
+				webhooks = append(webhooks, wh)
+			}
+		}
+		s.mu.Unlock()
+	}
+	for _, wh := range webhooks {
+		s.post(r.event, body, wh)
+	}
+}
+
+// post delivers body of e to wh and records the delivery
+func (s *Server) post(e *paypalsdk.Event, body []byte, wh *paypalsdk.Webhook) {
+	d := &Delivery{Event: e, WebhookID: wh.ID, URL: wh.Url}
+	req, err := http.NewRequest("POST", wh.Url, bytes.NewReader(body))
+	if err == nil {
+		req.Header = s.SignEvent(wh.ID, body)
+		req.Header.Set("Content-Type", "application/json")
+		var rsp *http.Response
+		if rsp, err = http.DefaultClient.Do(req); err == nil {
+			d.StatusCode = rsp.StatusCode
+			rsp.Body.Close()
+		}
+	}
+	d.Err = err
+
+	s.mu.Lock()
+	s.deliveries = append(s.deliveries, d)
+	s.mu.Unlock()
+}
+
+// subscribers returns the webhooks subscribed to eventType, or to every event type with *
+func (s *Server) subscribers(eventType string) []*paypalsdk.Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []*paypalsdk.Webhook
+	for _, id := range s.webhookOrder {
+		wh := s.webhooks[id]
+		for _, et := range wh.EventTypes {
+			if et.Name == eventType || et.Name == "*" {
+				list = append(list, wh)
+				break
+			}
+		}
+	}
+	return list
+}
+
+// SignEvent returns the headers of a delivery of body to webhookID, signed so that the fake verifies them.
+// It is useful to test a webhook receiver with a hand-made event
+func (s *Server) SignEvent(webhookID string, body []byte) http.Header {
+	s.mu.Lock()
+	transmissionID := fmt.Sprintf("%08x-0000-11ea-0000-%012x", crc32.ChecksumIEEE(body), s.seq)
+	s.seq++
+	s.mu.Unlock()
+	transmissionTime := s.now().Format(time.RFC3339)
+
+	header := http.Header{}
+	header.Set("Paypal-Transmission-Id", transmissionID)
+	header.Set("Paypal-Transmission-Time", transmissionTime)
+	header.Set("Paypal-Auth-Algo", "SHA256withRSA")
+	header.Set("Paypal-Cert-Url", s.URL+"/v1/notifications/certs/CERT-360caa42-fca2a594-paypaltest")
+	header.Set("Paypal-Transmission-Sig", s.signature(transmissionID, transmissionTime, webhookID, body))
+	return header
+}
+
+// signature is computed on the same input as PayPal's: transmissionId|timeStamp|webhookId|crc32(body),
+// but with HMAC-SHA256 instead of the RSA key of PayPal
+func (s *Server) signature(transmissionID, transmissionTime, webhookID string, body []byte) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	fmt.Fprintf(mac, "%s|%s|%s|%d", transmissionID, transmissionTime, webhookID, crc32.ChecksumIEEE(body))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
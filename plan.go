@@ -0,0 +1,128 @@
+package paypalsdk
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	K_PLAN_API = "/v1/billing/plans"
+)
+
+type E_PlanStatus string
+
+const (
+	E_PLAN_STATUS_CREATED  E_PlanStatus = "CREATED" // 已创建未激活, 不能用于创建订阅。
+	E_PLAN_STATUS_ACTIVE   E_PlanStatus = "ACTIVE"  // Default
+	E_PLAN_STATUS_INACTIVE E_PlanStatus = "INACTIVE"
+)
+
+// https://developer.paypal.com/docs/api/subscriptions/v1/#plans_create
+type Plan struct {
+	ID                 string              `json:"id,omitempty"` // paypal生成的 plan ID, eg: P-5ML4271244454362WXNWU5NQ
+	ProductID          string              `json:"product_id"`
+	Name               string              `json:"name"`                  // 1<=len<=127
+	Description        string              `json:"description,omitempty"` // 1<=len<=127
+	Status             E_PlanStatus        `json:"status,omitempty"`
+	BillingCycles      []*BillingCycle     `json:"billing_cycles"` // 1<=len<=12, 试用周期在前
+	PaymentPreferences *PaymentPreferences `json:"payment_preferences,omitempty"`
+	Taxes              *Taxes              `json:"taxes,omitempty"`
+	QuantitySupported  bool                `json:"quantity_supported,omitempty"` // 是否可以订阅多份
+	CreateTime         time.Time           `json:"create_time,omitempty"`        // 只读
+	UpdateTime         time.Time           `json:"update_time,omitempty"`        // 只读
+	Links              []*LinkDescription  `json:"links,omitempty"`
+}
+
+type PlanList struct {
+	Plans      []*Plan            `json:"plans"`
+	TotalItems int                `json:"total_items,omitempty"`
+	TotalPages int                `json:"total_pages,omitempty"`
+	Links      []*LinkDescription `json:"links,omitempty"`
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/billing/plans
+// Create plan
+// 触发webhook： BILLING.PLAN.CREATED
+// 创建 plan, 商品需要先用 CreateProduct 创建
+*/
+
+func (c *Client) CreatePlan(q *Plan) (*Plan, error) {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, K_PLAN_API), q)
+	rsp := &Plan{}
+	if err != nil {
+		return rsp, err
+	}
+	req.Header.Add("Prefer", "return=representation")
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/billing/plans?product_id=PROD-XXCD1234QWER65782&page=1&page_size=10&total_required=true
+// List plans
+// 分页查询 plan, productID 为空时查询所有商品的 plan。
+*/
+
+func (c *Client) ListPlans(productID string, page, pageSize int) (*PlanList, error) {
+	query := invoicePageQuery(page, pageSize)
+	if productID != "" {
+		query += "&product_id=" + url.QueryEscape(productID)
+	}
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s?%s", c.APIBase, K_PLAN_API, query), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &PlanList{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/billing/plans/P-5ML4271244454362WXNWU5NQ
+// Show plan details
+*/
+
+func (c *Client) ShowPlanDetails(planID string) (*Plan, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.APIBase, K_PLAN_API, planID), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &Plan{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/billing/plans/P-5ML4271244454362WXNWU5NQ/activate
+// 204 No Content
+// Activate plan
+// 触发webhook： BILLING.PLAN.ACTIVATED
+*/
+
+func (c *Client) ActivatePlan(planID string) error {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/activate", c.APIBase, K_PLAN_API, planID), nil)
+	if err != nil {
+		return err
+	}
+	err = c.SendWithAuth(req, nil)
+	return err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/billing/plans/P-5ML4271244454362WXNWU5NQ/deactivate
+// 204 No Content
+// Deactivate plan
+// 触发webhook： BILLING.PLAN.DEACTIVATED
+// 停用后不能再创建订阅, 已有订阅不受影响
+*/
+
+func (c *Client) DeactivatePlan(planID string) error {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/deactivate", c.APIBase, K_PLAN_API, planID), nil)
+	if err != nil {
+		return err
+	}
+	err = c.SendWithAuth(req, nil)
+	return err
+}
@@ -0,0 +1,92 @@
+package paypalsdk
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	K_PRODUCT_API = "/v1/catalogs/products"
+)
+
+/*
+订阅前需要先创建商品(product), 再在商品下创建 plan。
+*/
+
+type E_ProductType string
+
+const (
+	E_PRODUCT_TYPE_PHYSICAL E_ProductType = "PHYSICAL" // Default
+	E_PRODUCT_TYPE_DIGITAL  E_ProductType = "DIGITAL"
+	E_PRODUCT_TYPE_SERVICE  E_ProductType = "SERVICE"
+)
+
+// https://developer.paypal.com/docs/api/catalog-products/v1/#products_create
+type Product struct {
+	ID          string             `json:"id,omitempty"`          // 6<=len<=50, 不传时由 paypal 生成, eg: PROD-XXCD1234QWER65782
+	Name        string             `json:"name"`                  // 1<=len<=127
+	Description string             `json:"description,omitempty"` // 1<=len<=256
+	Type        E_ProductType      `json:"type,omitempty"`
+	Category    string             `json:"category,omitempty"` // eg: SOFTWARE
+	ImageURL    string             `json:"image_url,omitempty"`
+	HomeURL     string             `json:"home_url,omitempty"`
+	CreateTime  time.Time          `json:"create_time,omitempty"` // 只读
+	UpdateTime  time.Time          `json:"update_time,omitempty"` // 只读
+	Links       []*LinkDescription `json:"links,omitempty"`
+}
+
+type ProductList struct {
+	Products   []*Product         `json:"products"`
+	TotalItems int                `json:"total_items,omitempty"`
+	TotalPages int                `json:"total_pages,omitempty"`
+	Links      []*LinkDescription `json:"links,omitempty"`
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/catalogs/products
+// Create product
+// 触发webhook： CATALOG.PRODUCT.CREATED
+// 创建商品
+*/
+
+func (c *Client) CreateProduct(q *Product) (*Product, error) {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, K_PRODUCT_API), q)
+	rsp := &Product{}
+	if err != nil {
+		return rsp, err
+	}
+	req.Header.Add("Prefer", "return=representation")
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/catalogs/products?page=1&page_size=10&total_required=true
+// List products
+// 分页查询商品, page 从 1 开始, pageSize 取值 [1, 20], 为 0 时使用 PayPal 默认值。
+*/
+
+func (c *Client) ListProducts(page, pageSize int) (*ProductList, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s?%s", c.APIBase, K_PRODUCT_API, invoicePageQuery(page, pageSize)), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &ProductList{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/catalogs/products/PROD-XXCD1234QWER65782
+// Show product details
+*/
+
+func (c *Client) ShowProductDetails(productID string) (*Product, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.APIBase, K_PRODUCT_API, productID), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &Product{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
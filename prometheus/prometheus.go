@@ -0,0 +1,98 @@
+// Package paypalprom reports the metrics of paypalsdk to Prometheus:
+//
+//	m := paypalprom.NewMetrics(prometheus.DefaultRegisterer)
+//	c, err := paypalsdk.NewClient(clientID, secret, paypalsdk.WithMetrics(m))
+package paypalprom
+
+import (
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "paypal"
+
+// Metrics implements paypalsdk.Metrics with the following metrics:
+//
+//	paypal_request_duration_seconds{operation, status_class}  histogram, its _count is the number of calls
+//	paypal_retries_total{operation}                           counter
+//	paypal_token_refreshes_total{result}                      counter, result is success or failure
+//	paypal_webhook_verifications_total{result}                counter, result is pass, fail or error
+//	paypal_webhook_events_total{event_type, outcome}          counter, outcome is success or failure
+type Metrics struct {
+	requestDuration      *prometheus.HistogramVec
+	retries              *prometheus.CounterVec
+	tokenRefreshes       *prometheus.CounterVec
+	webhookVerifications *prometheus.CounterVec
+	webhookEvents        *prometheus.CounterVec
+}
+
+var _ paypalsdk.Metrics = (*Metrics)(nil)
+
+// NewMetrics creates the metrics and registers them to reg
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Duration of PayPal API calls, including retries and token refresh.",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 30},
+		}, []string{"operation", "status_class"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "retries_total",
+			Help:      "Number of retried PayPal API requests.",
+		}, []string{"operation"}),
+		tokenRefreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "token_refreshes_total",
+			Help:      "Number of attempts to get a PayPal access token.",
+		}, []string{"result"}),
+		webhookVerifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "webhook_verifications_total",
+			Help:      "Number of PayPal webhook signature verifications.",
+		}, []string{"result"}),
+		webhookEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "webhook_events_total",
+			Help:      "Number of PayPal webhook events handled.",
+		}, []string{"event_type", "outcome"}),
+	}
+	reg.MustRegister(m.requestDuration, m.retries, m.tokenRefreshes, m.webhookVerifications, m.webhookEvents)
+	return m
+}
+
+func (m *Metrics) ObserveCall(operation, statusClass string, duration time.Duration) {
+	m.requestDuration.WithLabelValues(operation, statusClass).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveRetries(operation string, retries int) {
+	m.retries.WithLabelValues(operation).Add(float64(retries))
+}
+
+func (m *Metrics) ObserveTokenRefresh(err error) {
+	m.tokenRefreshes.WithLabelValues(outcome(err)).Inc()
+}
+
+func (m *Metrics) ObserveWebhookVerification(verified bool, err error) {
+	result := "pass"
+	if err != nil {
+		result = "error"
+	} else if !verified {
+		result = "fail"
+	}
+	m.webhookVerifications.WithLabelValues(result).Inc()
+}
+
+func (m *Metrics) ObserveWebhookHandled(eventType string, err error) {
+	m.webhookEvents.WithLabelValues(eventType, outcome(err)).Inc()
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
@@ -0,0 +1,82 @@
+package paypalprom
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	m := NewMetrics(reg)
+
+	m.ObserveCall("paypal.subscriptions.create", "2xx", 300*time.Millisecond)
+	m.ObserveCall("paypal.subscriptions.create", "5xx", 2*time.Second)
+	m.ObserveRetries("paypal.subscriptions.create", 2)
+	m.ObserveTokenRefresh(nil)
+	m.ObserveTokenRefresh(errors.New("401"))
+	m.ObserveWebhookVerification(true, nil)
+	m.ObserveWebhookVerification(false, nil)
+	m.ObserveWebhookVerification(false, errors.New("timeout"))
+	m.ObserveWebhookHandled("BILLING.SUBSCRIPTION.ACTIVATED", nil)
+	m.ObserveWebhookHandled("BILLING.SUBSCRIPTION.ACTIVATED", errors.New("db down"))
+
+	const want = `
+# HELP paypal_retries_total Number of retried PayPal API requests.
+# TYPE paypal_retries_total counter
+paypal_retries_total{operation="paypal.subscriptions.create"} 2
+# HELP paypal_token_refreshes_total Number of attempts to get a PayPal access token.
+# TYPE paypal_token_refreshes_total counter
+paypal_token_refreshes_total{result="failure"} 1
+paypal_token_refreshes_total{result="success"} 1
+# HELP paypal_webhook_events_total Number of PayPal webhook events handled.
+# TYPE paypal_webhook_events_total counter
+paypal_webhook_events_total{event_type="BILLING.SUBSCRIPTION.ACTIVATED",outcome="failure"} 1
+paypal_webhook_events_total{event_type="BILLING.SUBSCRIPTION.ACTIVATED",outcome="success"} 1
+# HELP paypal_webhook_verifications_total Number of PayPal webhook signature verifications.
+# TYPE paypal_webhook_verifications_total counter
+paypal_webhook_verifications_total{result="error"} 1
+paypal_webhook_verifications_total{result="fail"} 1
+paypal_webhook_verifications_total{result="pass"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want),
+		"paypal_retries_total", "paypal_token_refreshes_total", "paypal_webhook_events_total", "paypal_webhook_verifications_total"); err != nil {
+		t.Error(err)
+	}
+
+	const wantDuration = `
+# HELP paypal_request_duration_seconds Duration of PayPal API calls, including retries and token refresh.
+# TYPE paypal_request_duration_seconds histogram
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="2xx",le="0.05"} 0
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="2xx",le="0.1"} 0
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="2xx",le="0.25"} 0
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="2xx",le="0.5"} 1
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="2xx",le="1"} 1
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="2xx",le="2"} 1
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="2xx",le="5"} 1
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="2xx",le="10"} 1
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="2xx",le="30"} 1
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="2xx",le="+Inf"} 1
+paypal_request_duration_seconds_sum{operation="paypal.subscriptions.create",status_class="2xx"} 0.3
+paypal_request_duration_seconds_count{operation="paypal.subscriptions.create",status_class="2xx"} 1
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="5xx",le="0.05"} 0
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="5xx",le="0.1"} 0
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="5xx",le="0.25"} 0
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="5xx",le="0.5"} 0
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="5xx",le="1"} 0
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="5xx",le="2"} 1
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="5xx",le="5"} 1
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="5xx",le="10"} 1
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="5xx",le="30"} 1
+paypal_request_duration_seconds_bucket{operation="paypal.subscriptions.create",status_class="5xx",le="+Inf"} 1
+paypal_request_duration_seconds_sum{operation="paypal.subscriptions.create",status_class="5xx"} 2
+paypal_request_duration_seconds_count{operation="paypal.subscriptions.create",status_class="5xx"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(wantDuration), "paypal_request_duration_seconds"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,200 @@
+// Package reconcile checks that every PayPal subscription charge of a period landed in the ledger of the app:
+//
+//	r := &reconcile.Reconciler{API: client, Ledger: myLedger}
+//	report, err := r.Run(ctx, subscriptionIDs, start, end, saleEvents)
+//	err = report.WriteCSV(os.Stdout)
+//
+// PayPal transactions are pulled with ListTransactionsForSubscription, completed with the PAYMENT.SALE.* events
+// given (eg: stored by the webhook handler), and compared by transaction ID with the ledger entries of the period.
+package reconcile
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+// LedgerEntry is a PayPal charge as booked in the ledger of the app
+type LedgerEntry struct {
+	TransactionID  string // the PayPal transaction (sale) ID
+	SubscriptionID string
+	Time           time.Time
+	Gross          *paypalsdk.Money
+	Fee            *paypalsdk.Money // nil if the ledger does not book fees
+	Net            *paypalsdk.Money // nil if the ledger does not book net amounts
+	Refunded       *paypalsdk.Money // nil if not refunded
+}
+
+// Ledger is the ledger of the app, implemented by the caller
+type Ledger interface {
+	// Entries returns the PayPal charges booked in [start, end)
+	Entries(ctx context.Context, start, end time.Time) ([]*LedgerEntry, error)
+}
+
+// Reconciler compares PayPal transactions with a Ledger
+type Reconciler struct {
+	API    paypalsdk.SubscriptionsAPI
+	Ledger Ledger
+}
+
+// transaction is a PayPal transaction of the period
+type transaction struct {
+	id             string
+	subscriptionID string
+	status         string
+	time           time.Time
+	gross          *paypalsdk.Money
+	fee            *paypalsdk.Money
+	net            *paypalsdk.Money
+	refunded       *paypalsdk.Money // nil if unknown
+}
+
+// Run builds the report of [start, end) for the transactions of subscriptionIDs and those of events.
+// Events other than PAYMENT.SALE.* are ignored, sale events outside of the period are only used for refunds
+func (r *Reconciler) Run(ctx context.Context, subscriptionIDs []string, start, end time.Time, events []*paypalsdk.Event) (*Report, error) {
+	txs := map[string]*transaction{}
+	for _, id := range subscriptionIDs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		rsp, err := r.API.ListTransactionsForSubscription(id, start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range rsp.Transactions {
+			if t.Time.Before(start) || !t.Time.Before(end) {
+				continue
+			}
+			tx := &transaction{id: t.ID, subscriptionID: id, status: string(t.Status), time: t.Time}
+			if b := t.AmountWithBreakdown; b != nil {
+				tx.gross, tx.fee, tx.net = optional(b.GrossAmount), optional(b.FeeAmount), optional(b.NetAmount)
+			}
+			// 全额退款时退款金额等于毛额, 部分退款的金额需要从退款事件获取
+			if t.Status == paypalsdk.E_TRANSACTION_STATUS_REFUNDED {
+				tx.refunded = tx.gross
+			}
+			txs[tx.id] = tx
+		}
+	}
+	if err := addEvents(txs, events, start, end); err != nil {
+		return nil, err
+	}
+
+	entries, err := r.Ledger.Entries(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return compare(start, end, txs, entries), nil
+}
+
+// addEvents 用 sale 事件补充交易列表中没有的交易, 并累加退款金额
+func addEvents(txs map[string]*transaction, events []*paypalsdk.Event, start, end time.Time) error {
+	refunds := map[string]*paypalsdk.Money{}
+	for _, e := range events {
+		if sale := e.Sale(); sale != nil && sale.Amount != nil {
+			if _, ok := txs[sale.Id]; ok {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339, sale.CreateTime)
+			if err != nil || t.Before(start) || !t.Before(end) {
+				continue
+			}
+			// sale 的状态是小写的, 统一为交易列表的大写状态
+			tx := &transaction{id: sale.Id, subscriptionID: sale.BillingAgreementId, status: strings.ToUpper(string(sale.State)), time: t}
+			if tx.gross, err = paypalsdk.MoneyFromAmount(sale.Amount); err != nil {
+				return err
+			}
+			if sale.TransactionFee != nil {
+				if tx.fee, err = paypalsdk.MoneyFromCurrency(sale.TransactionFee); err != nil {
+					return err
+				}
+				if tx.net, err = tx.gross.Sub(tx.fee); err != nil {
+					return err
+				}
+			}
+			txs[tx.id] = tx
+		}
+		if refund := e.Refund(); refund != nil && refund.SaleId != "" && refund.Amount != nil {
+			amount, err := paypalsdk.MoneyFromAmount(refund.Amount)
+			if err != nil {
+				return err
+			}
+			if sum, ok := refunds[refund.SaleId]; ok {
+				if amount, err = sum.Add(amount); err != nil {
+					return err
+				}
+			}
+			refunds[refund.SaleId] = amount
+		}
+	}
+	for id, amount := range refunds {
+		if tx, ok := txs[id]; ok {
+			tx.refunded = amount
+		}
+	}
+	return nil
+}
+
+func compare(start, end time.Time, txs map[string]*transaction, entries []*LedgerEntry) *Report {
+	report := &Report{Start: start, End: end}
+	booked := map[string]*LedgerEntry{}
+	for _, e := range entries {
+		booked[e.TransactionID] = e
+	}
+
+	for id, tx := range txs {
+		e, ok := booked[id]
+		delete(booked, id)
+		declined := tx.status == paypalsdk.E_TRANSACTION_STATUS_DECLINED || tx.status == strings.ToUpper(string(paypalsdk.E_SALE_STATE_DENIED))
+		switch {
+		case !ok && declined:
+			report.Matched++
+		case !ok:
+			report.add(KindMissing, tx, nil, "charged in PayPal but not in the ledger")
+		case declined:
+			report.add(KindExtra, tx, e, "declined in PayPal")
+		case !equal(tx.gross, e.Gross) || e.Fee != nil && !equal(tx.fee, e.Fee) || e.Net != nil && !equal(tx.net, e.Net):
+			report.add(KindAmountMismatch, tx, e, "amounts differ")
+		case tx.refunded != nil && !equal(tx.refunded, e.Refunded):
+			report.add(KindRefunded, tx, e, "refunded in PayPal, the ledger refund differs")
+		case tx.refunded == nil && tx.status == paypalsdk.E_TRANSACTION_STATUS_PARTIALLY_REFUNDED && e.Refunded == nil:
+			report.add(KindRefunded, tx, e, "partially refunded in PayPal but not in the ledger")
+		case tx.refunded == nil && tx.status != paypalsdk.E_TRANSACTION_STATUS_PARTIALLY_REFUNDED && e.Refunded != nil && !e.Refunded.IsZero():
+			report.add(KindRefunded, tx, e, "refunded in the ledger but not in PayPal")
+		default:
+			report.Matched++
+		}
+	}
+	for _, e := range booked {
+		report.add(KindExtra, nil, e, "booked in the ledger but not found in PayPal")
+	}
+
+	sort.Slice(report.Items, func(i, j int) bool {
+		a, b := report.Items[i], report.Items[j]
+		if !a.Time.Equal(b.Time) {
+			return a.Time.Before(b.Time)
+		}
+		return a.TransactionID < b.TransactionID
+	})
+	return report
+}
+
+// optional 把交易列表中缺失的金额(零值或没有币种)转为 nil, amount_with_breakdown 的金额不是指针, 缺失时为零值
+func optional(m paypalsdk.Money) *paypalsdk.Money {
+	if m.CurrencyCode == "" || m.IsZero() {
+		return nil
+	}
+	return &m
+}
+
+// equal 比较金额, nil 与零金额相等, 币种不同或无法解析时视为不相等
+func equal(a, b *paypalsdk.Money) bool {
+	if a == nil || b == nil {
+		return (a == nil || a.IsZero()) && (b == nil || b.IsZero())
+	}
+	c, err := a.Compare(b)
+	return err == nil && c == 0
+}
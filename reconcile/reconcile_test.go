@@ -0,0 +1,207 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+	"github.com/YYRise/PayPal-GO-SDK/paypalmock"
+)
+
+type ledger []*LedgerEntry
+
+func (l ledger) Entries(ctx context.Context, start, end time.Time) ([]*LedgerEntry, error) {
+	return l, nil
+}
+
+func usd(value string) *paypalsdk.Money {
+	return &paypalsdk.Money{CurrencyCode: "USD", Value: value}
+}
+
+func subTransaction(id string, status paypalsdk.E_Transaction_Status, t time.Time, gross, fee, net string) *paypalsdk.SubTransaction {
+	b := &paypalsdk.AmountWithBreakdown{GrossAmount: *usd(gross)}
+	if fee != "" {
+		b.FeeAmount, b.NetAmount = *usd(fee), *usd(net)
+	}
+	return &paypalsdk.SubTransaction{ID: id, Status: status, Time: t, AmountWithBreakdown: b}
+}
+
+func event(t *testing.T, data string) *paypalsdk.Event {
+	e, err := paypalsdk.ParseEvent([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestRun(t *testing.T) {
+	start := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	day := func(n int) time.Time { return start.AddDate(0, 0, n) }
+
+	tests := []struct {
+		name         string
+		transactions []*paypalsdk.SubTransaction
+		events       []string
+		entries      ledger
+		want         map[Kind]int
+		matched      int
+	}{
+		{
+			name:         "matched",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_COMPLETED, day(1), "10.00", "0.59", "9.41")},
+			entries:      ledger{{TransactionID: "T1", Gross: usd("10.00"), Fee: usd("0.59"), Net: usd("9.41")}},
+			matched:      1,
+		},
+		{
+			name:         "ledger without fees",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_COMPLETED, day(1), "10.00", "0.59", "9.41")},
+			entries:      ledger{{TransactionID: "T1", Gross: usd("10")}},
+			matched:      1,
+		},
+		{
+			name:         "missing breakdown amounts equal zero in the ledger",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_COMPLETED, day(1), "10.00", "", "")},
+			entries:      ledger{{TransactionID: "T1", Gross: usd("10.00"), Fee: usd("0.00")}},
+			matched:      1,
+		},
+		{
+			name:         "missing",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_COMPLETED, day(1), "10.00", "0.59", "9.41")},
+			want:         map[Kind]int{KindMissing: 1},
+		},
+		{
+			name:    "extra",
+			entries: ledger{{TransactionID: "T9", Gross: usd("10.00")}},
+			want:    map[Kind]int{KindExtra: 1},
+		},
+		{
+			name:         "outside of the period",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_COMPLETED, end, "10.00", "0.59", "9.41")},
+		},
+		{
+			name:         "gross mismatch",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_COMPLETED, day(1), "10.00", "0.59", "9.41")},
+			entries:      ledger{{TransactionID: "T1", Gross: usd("12.00")}},
+			want:         map[Kind]int{KindAmountMismatch: 1},
+		},
+		{
+			name:         "fee missing in PayPal",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_COMPLETED, day(1), "10.00", "", "")},
+			entries:      ledger{{TransactionID: "T1", Gross: usd("10.00"), Fee: usd("0.59")}},
+			want:         map[Kind]int{KindAmountMismatch: 1},
+		},
+		{
+			name:         "currency mismatch",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_COMPLETED, day(1), "10.00", "", "")},
+			entries:      ledger{{TransactionID: "T1", Gross: &paypalsdk.Money{CurrencyCode: "EUR", Value: "10.00"}}},
+			want:         map[Kind]int{KindAmountMismatch: 1},
+		},
+		{
+			name:         "declined and not booked",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_DECLINED, day(1), "10.00", "", "")},
+			matched:      1,
+		},
+		{
+			name:         "declined but booked",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_DECLINED, day(1), "10.00", "", "")},
+			entries:      ledger{{TransactionID: "T1", Gross: usd("10.00")}},
+			want:         map[Kind]int{KindExtra: 1},
+		},
+		{
+			name:         "refunded",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_REFUNDED, day(1), "10.00", "0.59", "9.41")},
+			entries:      ledger{{TransactionID: "T1", Gross: usd("10.00"), Refunded: usd("10.00")}},
+			matched:      1,
+		},
+		{
+			name:         "refund not booked",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_REFUNDED, day(1), "10.00", "0.59", "9.41")},
+			entries:      ledger{{TransactionID: "T1", Gross: usd("10.00")}},
+			want:         map[Kind]int{KindRefunded: 1},
+		},
+		{
+			name:         "refund booked but not made",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_COMPLETED, day(1), "10.00", "0.59", "9.41")},
+			entries:      ledger{{TransactionID: "T1", Gross: usd("10.00"), Refunded: usd("5.00")}},
+			want:         map[Kind]int{KindRefunded: 1},
+		},
+		{
+			name:         "zero refund booked",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_COMPLETED, day(1), "10.00", "0.59", "9.41")},
+			entries:      ledger{{TransactionID: "T1", Gross: usd("10.00"), Refunded: usd("0.00")}},
+			matched:      1,
+		},
+		{
+			name:         "partial refund without events",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_PARTIALLY_REFUNDED, day(1), "10.00", "0.59", "9.41")},
+			entries:      ledger{{TransactionID: "T1", Gross: usd("10.00")}},
+			want:         map[Kind]int{KindRefunded: 1},
+		},
+		{
+			name:         "partial refunds from events",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_PARTIALLY_REFUNDED, day(1), "10.00", "0.59", "9.41")},
+			events: []string{
+				`{"id":"WH-1","event_type":"PAYMENT.SALE.REFUNDED","resource_type":"refund","resource":{"id":"R1","sale_id":"T1","amount":{"currency":"USD","total":"2.00"}}}`,
+				`{"id":"WH-2","event_type":"PAYMENT.SALE.REFUNDED","resource_type":"refund","resource":{"id":"R2","sale_id":"T1","amount":{"currency":"USD","total":"1.50"}}}`,
+			},
+			entries: ledger{{TransactionID: "T1", Gross: usd("10.00"), Refunded: usd("3.50")}},
+			matched: 1,
+		},
+		{
+			name:         "partial refund booked with another amount",
+			transactions: []*paypalsdk.SubTransaction{subTransaction("T1", paypalsdk.E_TRANSACTION_STATUS_PARTIALLY_REFUNDED, day(1), "10.00", "0.59", "9.41")},
+			events: []string{
+				`{"id":"WH-1","event_type":"PAYMENT.SALE.REFUNDED","resource_type":"refund","resource":{"id":"R1","sale_id":"T1","amount":{"currency":"USD","total":"2.00"}}}`,
+			},
+			entries: ledger{{TransactionID: "T1", Gross: usd("10.00"), Refunded: usd("3.00")}},
+			want:    map[Kind]int{KindRefunded: 1},
+		},
+		{
+			name: "sale event not in the transaction list",
+			events: []string{
+				`{"id":"WH-1","event_type":"PAYMENT.SALE.COMPLETED","resource_type":"sale","resource":{"id":"T2","state":"completed","billing_agreement_id":"I-1","create_time":"2026-02-03T00:00:00Z","amount":{"currency":"USD","total":"10.00"},"transaction_fee":{"currency":"USD","value":"0.59"}}}`,
+			},
+			entries: ledger{{TransactionID: "T2", Gross: usd("10.00"), Net: usd("9.41")}},
+			matched: 1,
+		},
+		{
+			name: "denied sale event",
+			events: []string{
+				`{"id":"WH-1","event_type":"PAYMENT.SALE.DENIED","resource_type":"sale","resource":{"id":"T2","state":"denied","create_time":"2026-02-03T00:00:00Z","amount":{"currency":"USD","total":"10.00"}}}`,
+			},
+			entries: ledger{{TransactionID: "T2", Gross: usd("10.00")}},
+			want:    map[Kind]int{KindExtra: 1},
+		},
+	}
+	for _, tt := range tests {
+		api := &paypalmock.SubscriptionsAPI{
+			ListTransactionsForSubscriptionFunc: func(subID, startTime, endTime string) (*paypalsdk.ListTransactionRsp, error) {
+				return &paypalsdk.ListTransactionRsp{Transactions: tt.transactions}, nil
+			},
+		}
+		var events []*paypalsdk.Event
+		for _, data := range tt.events {
+			events = append(events, event(t, data))
+		}
+		r := &Reconciler{API: api, Ledger: tt.entries}
+		report, err := r.Run(context.Background(), []string{"I-1"}, start, end, events)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		n := 0
+		for kind, want := range tt.want {
+			if got := report.Count(kind); got != want {
+				t.Errorf("%s: Count(%s) = %d, want %d", tt.name, kind, got, want)
+			}
+			n += want
+		}
+		if len(report.Items) != n {
+			t.Errorf("%s: %d items, want %d: %+v", tt.name, len(report.Items), n, report.Items)
+		}
+		if report.Matched != tt.matched {
+			t.Errorf("%s: Matched = %d, want %d", tt.name, report.Matched, tt.matched)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package reconcile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+// Kind is the kind of a difference between PayPal and the ledger
+type Kind string
+
+const (
+	// KindMissing is a PayPal charge not booked in the ledger
+	KindMissing Kind = "MISSING"
+	// KindExtra is a ledger entry without a PayPal charge, or whose charge was declined
+	KindExtra Kind = "EXTRA"
+	// KindAmountMismatch is a charge whose gross, fee or net amount differs in the ledger
+	KindAmountMismatch Kind = "AMOUNT_MISMATCH"
+	// KindRefunded is a PayPal refund not booked, or booked with another amount, in the ledger
+	KindRefunded Kind = "REFUNDED"
+)
+
+// Item is a difference between PayPal and the ledger, the PayPal or the ledger amounts are nil if absent
+type Item struct {
+	Kind           Kind      `json:"kind"`
+	TransactionID  string    `json:"transaction_id"`
+	SubscriptionID string    `json:"subscription_id,omitempty"`
+	Time           time.Time `json:"time"`
+	PayPalStatus   string    `json:"paypal_status,omitempty"`
+
+	PayPalGross    *paypalsdk.Money `json:"paypal_gross,omitempty"`
+	PayPalFee      *paypalsdk.Money `json:"paypal_fee,omitempty"`
+	PayPalNet      *paypalsdk.Money `json:"paypal_net,omitempty"`
+	PayPalRefunded *paypalsdk.Money `json:"paypal_refunded,omitempty"`
+
+	LedgerGross    *paypalsdk.Money `json:"ledger_gross,omitempty"`
+	LedgerFee      *paypalsdk.Money `json:"ledger_fee,omitempty"`
+	LedgerNet      *paypalsdk.Money `json:"ledger_net,omitempty"`
+	LedgerRefunded *paypalsdk.Money `json:"ledger_refunded,omitempty"`
+
+	Note string `json:"note,omitempty"`
+}
+
+// Report is the result of a reconciliation of [Start, End)
+type Report struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Matched is the number of transactions identical in PayPal and in the ledger
+	Matched int `json:"matched"`
+	// Items are the differences, by time
+	Items []*Item `json:"items"`
+}
+
+// OK reports whether PayPal and the ledger agree
+func (r *Report) OK() bool {
+	return len(r.Items) == 0
+}
+
+// Count returns the number of items of kind
+func (r *Report) Count(kind Kind) int {
+	n := 0
+	for _, item := range r.Items {
+		if item.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *Report) add(kind Kind, tx *transaction, e *LedgerEntry, note string) {
+	item := &Item{Kind: kind, Note: note}
+	if e != nil {
+		item.TransactionID, item.SubscriptionID, item.Time = e.TransactionID, e.SubscriptionID, e.Time
+		item.LedgerGross, item.LedgerFee, item.LedgerNet, item.LedgerRefunded = e.Gross, e.Fee, e.Net, e.Refunded
+	}
+	// PayPal 的数据优先
+	if tx != nil {
+		item.TransactionID, item.Time, item.PayPalStatus = tx.id, tx.time, tx.status
+		if tx.subscriptionID != "" {
+			item.SubscriptionID = tx.subscriptionID
+		}
+		item.PayPalGross, item.PayPalFee, item.PayPalNet, item.PayPalRefunded = tx.gross, tx.fee, tx.net, tx.refunded
+	}
+	r.Items = append(r.Items, item)
+}
+
+var csvHeader = []string{
+	"kind", "transaction_id", "subscription_id", "time", "paypal_status",
+	"paypal_gross", "paypal_fee", "paypal_net", "paypal_refunded",
+	"ledger_gross", "ledger_fee", "ledger_net", "ledger_refunded",
+	"currency", "note",
+}
+
+// WriteCSV writes the items with a header line. Amounts are written without currency, in the currency column
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, item := range r.Items {
+		record := []string{
+			string(item.Kind), item.TransactionID, item.SubscriptionID, item.Time.UTC().Format(time.RFC3339), item.PayPalStatus,
+			value(item.PayPalGross), value(item.PayPalFee), value(item.PayPalNet), value(item.PayPalRefunded),
+			value(item.LedgerGross), value(item.LedgerFee), value(item.LedgerNet), value(item.LedgerRefunded),
+			item.currency(), item.Note,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func (item *Item) currency() string {
+	for _, m := range []*paypalsdk.Money{item.PayPalGross, item.LedgerGross, item.PayPalRefunded, item.LedgerRefunded} {
+		if m != nil {
+			return m.CurrencyCode
+		}
+	}
+	return ""
+}
+
+func value(m *paypalsdk.Money) string {
+	if m == nil {
+		return ""
+	}
+	return m.Value
+}
@@ -0,0 +1,330 @@
+package paypalsdk
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	K_REPORTING_TRANSACTION_API = "/v1/reporting/transactions"
+	K_REPORTING_BALANCE_API     = "/v1/reporting/balances"
+
+	// 单次交易查询的最大时间跨度
+	kTransactionSearchMaxRange = 31 * 24 * time.Hour
+	// 单页最大条数
+	kTransactionSearchMaxPageSize = 500
+)
+
+/*
+交易数据有最多 3 小时的延迟, 可通过返回的 last_refreshed_datetime 判断数据的新鲜度。
+单次查询的 start_date 与 end_date 不能超过 31 天, SearchAllTransactions 会自动按 31 天切分并翻页。
+*/
+
+// https://developer.paypal.com/docs/api/transaction-search/v1/#transactions_get
+type E_TransactionSearchStatus string
+
+const (
+	E_TRANSACTION_SEARCH_STATUS_DENIED   E_TransactionSearchStatus = "D" // 交易被拒绝。
+	E_TRANSACTION_SEARCH_STATUS_PENDING  E_TransactionSearchStatus = "P" // 交易处理中。
+	E_TRANSACTION_SEARCH_STATUS_SUCCESS  E_TransactionSearchStatus = "S" // 交易成功。
+	E_TRANSACTION_SEARCH_STATUS_REVERSED E_TransactionSearchStatus = "V" // 交易已撤销, 如退款、拒付。
+)
+
+// https://developer.paypal.com/docs/api/transaction-search/v1/#transactions_get
+type E_TransactionSearchField string
+
+const (
+	E_TRANSACTION_SEARCH_FIELD_TRANSACTION_INFO E_TransactionSearchField = "transaction_info"
+	E_TRANSACTION_SEARCH_FIELD_PAYER_INFO       E_TransactionSearchField = "payer_info"
+	E_TRANSACTION_SEARCH_FIELD_SHIPPING_INFO    E_TransactionSearchField = "shipping_info"
+	E_TRANSACTION_SEARCH_FIELD_AUCTION_INFO     E_TransactionSearchField = "auction_info"
+	E_TRANSACTION_SEARCH_FIELD_CART_INFO        E_TransactionSearchField = "cart_info"
+	E_TRANSACTION_SEARCH_FIELD_INCENTIVE_INFO   E_TransactionSearchField = "incentive_info"
+	E_TRANSACTION_SEARCH_FIELD_STORE_INFO       E_TransactionSearchField = "store_info"
+	E_TRANSACTION_SEARCH_FIELD_ALL              E_TransactionSearchField = "all"
+)
+
+type TransactionSearchReq struct {
+	StartDate                   time.Time // 必填
+	EndDate                     time.Time // 必填
+	TransactionID               string    // 17<=len<=19
+	TransactionType             string    // 交易事件码, eg: T0002 (订阅付款)
+	TransactionStatus           E_TransactionSearchStatus
+	TransactionAmount           string // 毛额范围, 以最小货币单位表示, eg: 500 TO 1005 表示 $5.00 ~ $10.05
+	TransactionCurrency         string // len=3, eg: USD ……
+	PaymentInstrumentType       string // CREDITCARD, DEBITCARD
+	StoreID                     string
+	TerminalID                  string
+	Fields                      []E_TransactionSearchField // 返回的字段, 为空时只返回 transaction_info
+	BalanceAffectingRecordsOnly bool                       // 只返回影响余额的记录
+	PageSize                    int                        // [1, 500] Default: 100.
+}
+
+// https://developer.paypal.com/docs/api/transaction-search/v1/#definition-transaction_info
+type TransactionInfo struct {
+	PaypalAccountID           string                    `json:"paypal_account_id,omitempty"`
+	TransactionID             string                    `json:"transaction_id,omitempty"`
+	PaypalReferenceID         string                    `json:"paypal_reference_id,omitempty"`      // 关联交易 ID, 如退款对应的原交易。
+	PaypalReferenceIDType     string                    `json:"paypal_reference_id_type,omitempty"` // ODR, TXN, SUB, PAP
+	TransactionEventCode      string                    `json:"transaction_event_code,omitempty"`   // eg: T0002
+	TransactionInitiationDate string                    `json:"transaction_initiation_date,omitempty"`
+	TransactionUpdatedDate    string                    `json:"transaction_updated_date,omitempty"`
+	TransactionAmount         *Money                    `json:"transaction_amount,omitempty"`
+	FeeAmount                 *Money                    `json:"fee_amount,omitempty"`
+	InsuranceAmount           *Money                    `json:"insurance_amount,omitempty"`
+	ShippingAmount            *Money                    `json:"shipping_amount,omitempty"`
+	ShippingDiscountAmount    *Money                    `json:"shipping_discount_amount,omitempty"`
+	TransactionStatus         E_TransactionSearchStatus `json:"transaction_status,omitempty"`
+	TransactionSubject        string                    `json:"transaction_subject,omitempty"`
+	TransactionNote           string                    `json:"transaction_note,omitempty"`
+	InvoiceID                 string                    `json:"invoice_id,omitempty"`
+	CustomField               string                    `json:"custom_field,omitempty"`
+	ProtectionEligibility     string                    `json:"protection_eligibility,omitempty"` // 01 ELIGIBLE, 02 NOT_ELIGIBLE, 03 PARTIALLY_ELIGIBLE
+	EndingBalance             *Money                    `json:"ending_balance,omitempty"`
+	AvailableBalance          *Money                    `json:"available_balance,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/transaction-search/v1/#definition-payer_info
+type TransactionPayerInfo struct {
+	AccountID     string `json:"account_id,omitempty"`
+	EmailAddress  string `json:"email_address,omitempty"`
+	AddressStatus string `json:"address_status,omitempty"` // Y, N
+	PayerStatus   string `json:"payer_status,omitempty"`   // Y, N
+	PayerName     *Name  `json:"payer_name,omitempty"`
+	CountryCode   string `json:"country_code,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/transaction-search/v1/#definition-shipping_info
+type TransactionShippingInfo struct {
+	Name    string                         `json:"name,omitempty"`
+	Method  string                         `json:"method,omitempty"`
+	Address *ShippingDetailAddressPortable `json:"address,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/transaction-search/v1/#definition-item_detail
+type TransactionItemDetail struct {
+	ItemCode        string `json:"item_code,omitempty"`
+	ItemName        string `json:"item_name,omitempty"`
+	ItemDescription string `json:"item_description,omitempty"`
+	ItemQuantity    string `json:"item_quantity,omitempty"`
+	ItemUnitPrice   *Money `json:"item_unit_price,omitempty"`
+	ItemAmount      *Money `json:"item_amount,omitempty"`
+	TotalItemAmount *Money `json:"total_item_amount,omitempty"`
+	InvoiceNumber   string `json:"invoice_number,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/transaction-search/v1/#definition-cart_info
+type TransactionCartInfo struct {
+	ItemDetails     []*TransactionItemDetail `json:"item_details,omitempty"`
+	TaxInclusive    bool                     `json:"tax_inclusive,omitempty"`
+	PaypalInvoiceID string                   `json:"paypal_invoice_id,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/transaction-search/v1/#definition-store_info
+type TransactionStoreInfo struct {
+	StoreID    string `json:"store_id,omitempty"`
+	TerminalID string `json:"terminal_id,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/transaction-search/v1/#definition-auction_info
+type TransactionAuctionInfo struct {
+	AuctionSite        string `json:"auction_site,omitempty"`
+	AuctionItemSite    string `json:"auction_item_site,omitempty"`
+	AuctionBuyerID     string `json:"auction_buyer_id,omitempty"`
+	AuctionClosingDate string `json:"auction_closing_date,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/transaction-search/v1/#definition-incentive_info
+type TransactionIncentiveInfo struct {
+	IncentiveDetails []*struct {
+		IncentiveType        string `json:"incentive_type,omitempty"`
+		IncentiveCode        string `json:"incentive_code,omitempty"`
+		IncentiveAmount      *Money `json:"incentive_amount,omitempty"`
+		IncentiveProgramCode string `json:"incentive_program_code,omitempty"`
+	} `json:"incentive_details,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/transaction-search/v1/#definition-transaction_detail
+type TransactionDetail struct {
+	TransactionInfo *TransactionInfo          `json:"transaction_info,omitempty"`
+	PayerInfo       *TransactionPayerInfo     `json:"payer_info,omitempty"`
+	ShippingInfo    *TransactionShippingInfo  `json:"shipping_info,omitempty"`
+	CartInfo        *TransactionCartInfo      `json:"cart_info,omitempty"`
+	StoreInfo       *TransactionStoreInfo     `json:"store_info,omitempty"`
+	AuctionInfo     *TransactionAuctionInfo   `json:"auction_info,omitempty"`
+	IncentiveInfo   *TransactionIncentiveInfo `json:"incentive_info,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/transaction-search/v1/#definition-search_response
+type TransactionSearchRsp struct {
+	TransactionDetails    []*TransactionDetail `json:"transaction_details"`
+	AccountNumber         string               `json:"account_number,omitempty"`
+	StartDate             string               `json:"start_date,omitempty"`
+	EndDate               string               `json:"end_date,omitempty"`
+	LastRefreshedDatetime string               `json:"last_refreshed_datetime,omitempty"`
+	Page                  int                  `json:"page,omitempty"`
+	TotalItems            int                  `json:"total_items,omitempty"`
+	TotalPages            int                  `json:"total_pages,omitempty"`
+	Links                 []*LinkDescription   `json:"links,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/transaction-search/v1/#definition-balance_detail
+type BalanceDetail struct {
+	Currency         string `json:"currency"`
+	Primary          bool   `json:"primary,omitempty"` // 是否为主币种
+	TotalBalance     *Money `json:"total_balance"`
+	AvailableBalance *Money `json:"available_balance,omitempty"`
+	WithheldBalance  *Money `json:"withheld_balance,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/transaction-search/v1/#definition-balances_response
+type BalancesRsp struct {
+	Balances        []*BalanceDetail `json:"balances,omitempty"`
+	AccountID       string           `json:"account_id,omitempty"`
+	AsOfTime        string           `json:"as_of_time,omitempty"`
+	LastRefreshTime string           `json:"last_refresh_time,omitempty"`
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/reporting/transactions?start_date=2020-03-01T00:00:00Z&end_date=2020-03-31T23:59:59Z&fields=all&page_size=100&page=1
+// List transactions
+// 查询一页交易, 时间跨度不能超过 31 天, page 从 1 开始。
+*/
+
+func (c *Client) ListTransactions(q *TransactionSearchReq, page int) (*TransactionSearchRsp, error) {
+	if q == nil || q.StartDate.IsZero() || q.EndDate.IsZero() {
+		return nil, errors.New("StartDate and EndDate are required to search transactions")
+	}
+	if q.EndDate.Before(q.StartDate) {
+		return nil, errors.New("EndDate must not be before StartDate to search transactions")
+	}
+	// 日期按秒发送, 按发送的值检查跨度
+	if q.EndDate.Truncate(time.Second).Sub(q.StartDate.Truncate(time.Second)) > kTransactionSearchMaxRange {
+		return nil, errors.New("the date range of a transaction search can not exceed 31 days")
+	}
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s?%s", c.APIBase, K_REPORTING_TRANSACTION_API, q.values(page).Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &TransactionSearchRsp{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+// SearchAllTransactions 将 [StartDate, EndDate] 按 31 天切分, 并翻页拉取所有交易。
+// end_date 包含在查询范围内, 下一段从上一段结束后 1 秒开始, 边界上的交易不会重复。
+// 日期按秒发送, 切分前先去掉不足 1 秒的部分, 否则最后不足 1 秒的一段会被漏掉。
+func (c *Client) SearchAllTransactions(q *TransactionSearchReq) ([]*TransactionDetail, error) {
+	if q == nil || q.StartDate.IsZero() || q.EndDate.IsZero() {
+		return nil, errors.New("StartDate and EndDate are required to search transactions")
+	}
+	if !q.StartDate.Before(q.EndDate) {
+		return nil, errors.New("StartDate must be before EndDate to search transactions")
+	}
+	window := *q
+	if window.PageSize <= 0 {
+		window.PageSize = kTransactionSearchMaxPageSize
+	}
+
+	var (
+		details []*TransactionDetail
+		end     time.Time
+		last    = q.EndDate.Truncate(time.Second)
+	)
+	for start := q.StartDate.Truncate(time.Second); !start.After(last); start = end.Add(time.Second) {
+		end = start.Add(kTransactionSearchMaxRange)
+		if end.After(last) {
+			end = last
+		}
+		window.StartDate, window.EndDate = start, end
+
+		for page := 1; ; page++ {
+			rsp, err := c.ListTransactions(&window, page)
+			if err != nil {
+				return details, err
+			}
+			details = append(details, rsp.TransactionDetails...)
+			if page >= rsp.TotalPages {
+				break
+			}
+		}
+	}
+	return details, nil
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/reporting/balances?as_of_time=2020-03-31T00:00:00Z&currency_code=USD
+// List all balances
+// 查询账户余额, asOfTime 为零值时查询当前余额, currencyCode 为空时返回所有币种。
+*/
+
+func (c *Client) ListBalances(asOfTime time.Time, currencyCode string) (*BalancesRsp, error) {
+	v := url.Values{}
+	if !asOfTime.IsZero() {
+		v.Set("as_of_time", asOfTime.UTC().Format(time.RFC3339))
+	}
+	if currencyCode != "" {
+		v.Set("currency_code", currencyCode)
+	}
+	endpoint := fmt.Sprintf("%s%s", c.APIBase, K_REPORTING_BALANCE_API)
+	if len(v) > 0 {
+		endpoint = fmt.Sprintf("%s?%s", endpoint, v.Encode())
+	}
+	req, err := c.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &BalancesRsp{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+func (q *TransactionSearchReq) values(page int) url.Values {
+	v := url.Values{}
+	v.Set("start_date", q.StartDate.UTC().Format(time.RFC3339))
+	v.Set("end_date", q.EndDate.UTC().Format(time.RFC3339))
+	if q.TransactionID != "" {
+		v.Set("transaction_id", q.TransactionID)
+	}
+	if q.TransactionType != "" {
+		v.Set("transaction_type", q.TransactionType)
+	}
+	if q.TransactionStatus != "" {
+		v.Set("transaction_status", string(q.TransactionStatus))
+	}
+	if q.TransactionAmount != "" {
+		v.Set("transaction_amount", q.TransactionAmount)
+	}
+	if q.TransactionCurrency != "" {
+		v.Set("transaction_currency", q.TransactionCurrency)
+	}
+	if q.PaymentInstrumentType != "" {
+		v.Set("payment_instrument_type", q.PaymentInstrumentType)
+	}
+	if q.StoreID != "" {
+		v.Set("store_id", q.StoreID)
+	}
+	if q.TerminalID != "" {
+		v.Set("terminal_id", q.TerminalID)
+	}
+	if len(q.Fields) > 0 {
+		fields := make([]string, 0, len(q.Fields))
+		for _, f := range q.Fields {
+			fields = append(fields, string(f))
+		}
+		v.Set("fields", strings.Join(fields, ","))
+	}
+	if q.BalanceAffectingRecordsOnly {
+		v.Set("balance_affecting_records_only", "Y")
+	}
+	if q.PageSize > 0 {
+		v.Set("page_size", fmt.Sprint(q.PageSize))
+	}
+	if page > 0 {
+		v.Set("page", fmt.Sprint(page))
+	}
+	return v
+}
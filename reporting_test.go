@@ -0,0 +1,138 @@
+package paypalsdk
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newReportingServer returns a client of a server answering 2 pages of one transaction per search,
+// and the windows searched, in order
+func newReportingServer(t *testing.T) (*Client, *[][2]string) {
+	t.Helper()
+	var windows [][2]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := r.URL.Query()
+		page, _ := strconv.Atoi(v.Get("page"))
+		if page == 1 {
+			windows = append(windows, [2]string{v.Get("start_date"), v.Get("end_date")})
+		}
+		id := v.Get("start_date") + "/" + v.Get("page")
+		json.NewEncoder(w).Encode(&TransactionSearchRsp{
+			TransactionDetails: []*TransactionDetail{{TransactionInfo: &TransactionInfo{TransactionID: id}}},
+			TotalPages:         2,
+		})
+	}))
+	t.Cleanup(srv.Close)
+	c, err := NewClient("id", "secret", WithAPIBase(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetAccessToken("token")
+	return c, &windows
+}
+
+func TestSearchAllTransactions(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	days31 := 31 * 24 * time.Hour
+	tests := []struct {
+		name  string
+		start time.Time
+		end   time.Time
+		want  [][2]string
+	}{
+		{"70 days", start, start.AddDate(0, 0, 70), [][2]string{
+			{"2026-01-01T00:00:00Z", "2026-02-01T00:00:00Z"},
+			{"2026-02-01T00:00:01Z", "2026-03-04T00:00:01Z"},
+			{"2026-03-04T00:00:02Z", "2026-03-12T00:00:00Z"},
+		}},
+		{"exactly 31 days", start, start.Add(days31), [][2]string{
+			{"2026-01-01T00:00:00Z", "2026-02-01T00:00:00Z"},
+		}},
+		{"31 days and 1s", start, start.Add(days31 + time.Second), [][2]string{
+			{"2026-01-01T00:00:00Z", "2026-02-01T00:00:00Z"},
+			{"2026-02-01T00:00:01Z", "2026-02-01T00:00:01Z"},
+		}},
+		{"sub-second start", start.Add(700 * time.Millisecond), start.Add(40 * 24 * time.Hour), [][2]string{
+			{"2026-01-01T00:00:00Z", "2026-02-01T00:00:00Z"},
+			{"2026-02-01T00:00:01Z", "2026-02-10T00:00:00Z"},
+		}},
+		{"tail shorter than 1s", start, start.Add(days31 + 500*time.Millisecond), [][2]string{
+			{"2026-01-01T00:00:00Z", "2026-02-01T00:00:00Z"},
+		}},
+		// 去掉不足 1 秒的部分之前, 最后 1 秒会被漏掉
+		{"sub-second start and tail", start.Add(700 * time.Millisecond), start.Add(days31 + 1200*time.Millisecond), [][2]string{
+			{"2026-01-01T00:00:00Z", "2026-02-01T00:00:00Z"},
+			{"2026-02-01T00:00:01Z", "2026-02-01T00:00:01Z"},
+		}},
+		{"within a second", start.Add(200 * time.Millisecond), start.Add(700 * time.Millisecond), [][2]string{
+			{"2026-01-01T00:00:00Z", "2026-01-01T00:00:00Z"},
+		}},
+	}
+	for _, tt := range tests {
+		c, windows := newReportingServer(t)
+		details, err := c.SearchAllTransactions(&TransactionSearchReq{StartDate: tt.start, EndDate: tt.end})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(*windows) != len(tt.want) {
+			t.Errorf("%s: windows = %v, want %v", tt.name, *windows, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if (*windows)[i] != tt.want[i] {
+				t.Errorf("%s: window %d = %v, want %v", tt.name, i, (*windows)[i], tt.want[i])
+			}
+		}
+		if len(details) != 2*len(tt.want) {
+			t.Errorf("%s: %d transactions, want %d", tt.name, len(details), 2*len(tt.want))
+		}
+	}
+
+	c, _ := newReportingServer(t)
+	for _, q := range []*TransactionSearchReq{
+		nil,
+		{StartDate: start},
+		{StartDate: start, EndDate: start},
+		{StartDate: start, EndDate: start.Add(-time.Hour)},
+	} {
+		if _, err := c.SearchAllTransactions(q); err == nil {
+			t.Errorf("SearchAllTransactions(%+v) error = nil", q)
+		}
+	}
+}
+
+func TestListTransactionsRange(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	days31 := 31 * 24 * time.Hour
+	tests := []struct {
+		name    string
+		start   time.Time
+		end     time.Time
+		wantErr bool
+	}{
+		{"31 days", start, start.Add(days31), false},
+		{"31 days and 1s", start, start.Add(days31 + time.Second), true},
+		// 按秒发送的跨度为 31 天
+		{"sub-second 31 days", start.Add(700 * time.Millisecond), start.Add(days31 + 900*time.Millisecond), false},
+		{"same time", start, start, false},
+		{"end before start", start, start.Add(-time.Second), true},
+		{"end before start within a second", start.Add(700 * time.Millisecond), start.Add(200 * time.Millisecond), true},
+		{"no end", start, time.Time{}, true},
+		{"no start", time.Time{}, start, true},
+	}
+	c, windows := newReportingServer(t)
+	for _, tt := range tests {
+		n := len(*windows)
+		_, err := c.ListTransactions(&TransactionSearchReq{StartDate: tt.start, EndDate: tt.end}, 1)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+		if sent := len(*windows) > n; sent == tt.wantErr {
+			t.Errorf("%s: request sent = %v", tt.name, sent)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package paypalsdk
+
+import (
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy retries calls failed with a network error, 429 Too Many Requests or 5xx.
+// Only idempotent calls are retried: GET, HEAD, PUT, DELETE, OPTIONS, and calls carrying PayPal-Request-Id,
+// which PayPal uses to deduplicate POST calls
+type RetryPolicy struct {
+	MaxRetries int           // 最多重试次数, 不含第一次请求
+	Backoff    time.Duration // 第 n 次重试前等待 Backoff * 2^(n-1), 429 时优先使用 Retry-After
+	MaxBackoff time.Duration // 等待时间上限, 0 为不限制
+}
+
+func (p *RetryPolicy) retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// 请求体无法重新读取
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return req.Header.Get("PayPal-Request-Id") != ""
+}
+
+func (p *RetryPolicy) shouldRetry(rsp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return rsp.StatusCode == http.StatusTooManyRequests || rsp.StatusCode >= http.StatusInternalServerError
+}
+
+func (p *RetryPolicy) wait(attempt int, rsp *http.Response) time.Duration {
+	if rsp != nil {
+		if seconds, err := strconv.Atoi(rsp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	// 移位溢出时取最大值, 再按 MaxBackoff 截断
+	d := time.Duration(math.MaxInt64)
+	if shift := uint(attempt - 1); shift < 63 && p.Backoff <= d>>shift {
+		d = p.Backoff << shift
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// RetryInterceptor retries calls according to p, it is installed as the outermost interceptor by Client.Retry
+func RetryInterceptor(p *RetryPolicy) Interceptor {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			rsp, err := next(req)
+			if !p.retryable(req) {
+				return rsp, err
+			}
+			for attempt := 1; attempt <= p.MaxRetries && p.shouldRetry(rsp, err); attempt++ {
+				wait := p.wait(attempt, rsp)
+				if rsp != nil {
+					io.Copy(ioutil.Discard, rsp.Body)
+					rsp.Body.Close()
+				}
+				select {
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				case <-time.After(wait):
+				}
+				if req.GetBody != nil {
+					body, e := req.GetBody()
+					if e != nil {
+						return nil, e
+					}
+					req.Body = body
+				}
+				if call := CallFromContext(req.Context()); call != nil {
+					call.Retries++
+				}
+				rsp, err = next(req)
+			}
+			return rsp, err
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package paypalsdk
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type discardLogger struct{}
+
+func (discardLogger) Println(v ...interface{}) {}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		requestID string
+		statuses  []int // 依次返回的状态码, 之后返回 200
+		retries   int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"success", "GET", "", nil, 2, 1, false},
+		{"5xx then success", "GET", "", []int{500, 502}, 2, 3, false},
+		{"429 then success", "GET", "", []int{429}, 2, 2, false},
+		{"retries exhausted", "GET", "", []int{500, 500, 500}, 2, 3, true},
+		{"no retry policy", "GET", "", []int{500}, 0, 1, true},
+		{"4xx is not retried", "GET", "", []int{400}, 2, 1, true},
+		{"POST is not retried", "POST", "", []int{500}, 2, 1, true},
+		{"POST with PayPal-Request-Id", "POST", "req-1", []int{503}, 2, 2, false},
+		{"DELETE", "DELETE", "", []int{503}, 2, 2, false},
+	}
+	for _, tt := range tests {
+		calls := 0
+		var bodies []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(data))
+			calls++
+			if calls <= len(tt.statuses) {
+				w.WriteHeader(tt.statuses[calls-1])
+				w.Write([]byte(`{"name":"ERROR"}`))
+				return
+			}
+			w.Write([]byte(`{}`))
+		}))
+		opts := []ClientOption{WithAPIBase(srv.URL), WithLogger(discardLogger{})}
+		if tt.retries > 0 {
+			opts = append(opts, WithRetry(tt.retries, time.Millisecond))
+		}
+		c, err := NewClient("id", "secret", opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var payload interface{}
+		if tt.method == "POST" {
+			payload = map[string]string{"reason": "retry"}
+		}
+		req, err := c.NewRequest(tt.method, srv.URL+"/v1/billing/subscriptions/I-1", payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.requestID != "" {
+			req.Header.Set("PayPal-Request-Id", tt.requestID)
+		}
+		err = c.Send(req, nil)
+		srv.Close()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+		if calls != tt.wantCalls {
+			t.Errorf("%s: %d calls, want %d", tt.name, calls, tt.wantCalls)
+		}
+		// 重试时重新发送请求体
+		for i, body := range bodies {
+			if body != bodies[0] {
+				t.Errorf("%s: body of call %d = %q, want %q", tt.name, i+1, body, bodies[0])
+			}
+		}
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	p := &RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	retryAfter := &http.Response{Header: http.Header{"Retry-After": {"3"}}}
+	badRetryAfter := &http.Response{Header: http.Header{"Retry-After": {"Wed, 21 Oct 2026 07:28:00 GMT"}}}
+	tests := []struct {
+		attempt int
+		rsp     *http.Response
+		want    time.Duration
+	}{
+		{1, nil, 100 * time.Millisecond},
+		{2, nil, 200 * time.Millisecond},
+		{4, nil, 800 * time.Millisecond},
+		{5, nil, time.Second},
+		{64, nil, time.Second},
+		{1, retryAfter, 3 * time.Second},
+		{2, badRetryAfter, 200 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := p.wait(tt.attempt, tt.rsp); got != tt.want {
+			t.Errorf("wait(%d, %v) = %v, want %v", tt.attempt, tt.rsp != nil, got, tt.want)
+		}
+	}
+
+	// 没有上限时, 溢出不能变成不等待
+	unbounded := &RetryPolicy{Backoff: time.Second}
+	if got := unbounded.wait(64, nil); got != math.MaxInt64 {
+		t.Errorf("wait(64) without MaxBackoff = %v, want the longest duration", got)
+	}
+}
+
+func TestRetryStopsWhenCanceled(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	c, err := NewClient("id", "secret", WithAPIBase(srv.URL), WithLogger(discardLogger{}), WithRetry(5, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := c.NewRequest("GET", srv.URL+"/v1/billing/plans", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.Send(req.WithContext(ctx), nil)
+	if err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Errorf("error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if calls != 1 {
+		t.Errorf("%d calls, want 1", calls)
+	}
+}
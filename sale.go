@@ -0,0 +1,110 @@
+package paypalsdk
+
+import "fmt"
+
+const (
+	K_SALE_API = "/v1/payments/sale"
+)
+
+/*
+订阅的每次扣款在 PAYMENT.SALE.* webhook 中以 v1 Sale 资源的形式返回,
+Sale.BillingAgreementId 即订阅 ID, Sale.Id 用于查询和退款。
+*/
+
+// https://developer.paypal.com/docs/api/payments/v1/#definition-amount
+type Amount struct {
+	Currency string   `json:"currency"` // len=3, eg: USD ……
+	Total    string   `json:"total"`    // len<=10, 必须数字，eg：123.45
+	Details  *Details `json:"details,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payments/v1/#definition-details
+type Details struct {
+	Subtotal         string `json:"subtotal,omitempty"`
+	Shipping         string `json:"shipping,omitempty"`
+	Tax              string `json:"tax,omitempty"`
+	HandlingFee      string `json:"handling_fee,omitempty"`
+	ShippingDiscount string `json:"shipping_discount,omitempty"`
+	Insurance        string `json:"insurance,omitempty"`
+	GiftWrap         string `json:"gift_wrap,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payments/v1/#definition-currency
+type Currency struct {
+	Currency string `json:"currency"` // len=3, eg: USD ……
+	Value    string `json:"value"`
+}
+
+// https://developer.paypal.com/docs/api/payments/v1/#definition-refund
+type E_SaleRefundState string
+
+const (
+	E_SALE_REFUND_STATE_PENDING   E_SaleRefundState = "pending"
+	E_SALE_REFUND_STATE_COMPLETED E_SaleRefundState = "completed"
+	E_SALE_REFUND_STATE_CANCELLED E_SaleRefundState = "cancelled"
+	E_SALE_REFUND_STATE_FAILED    E_SaleRefundState = "failed"
+)
+
+// https://developer.paypal.com/docs/api/payments/v1/#sale_refund
+type RefundSaleReq struct {
+	Amount        *Amount `json:"amount,omitempty"` // 不传则全额退款。
+	InvoiceNumber string  `json:"invoice_number,omitempty"`
+	Description   string  `json:"description,omitempty"` // len<=255
+	Reason        string  `json:"reason,omitempty"`      // len<=30
+}
+
+// https://developer.paypal.com/docs/api/payments/v1/#definition-detailed_refund
+type DetailedRefund struct {
+	Id                       string            `json:"id,omitempty"`
+	Amount                   *Amount           `json:"amount,omitempty"`
+	State                    E_SaleRefundState `json:"state,omitempty"`
+	Reason                   string            `json:"reason,omitempty"`
+	InvoiceNumber            string            `json:"invoice_number,omitempty"`
+	SaleId                   string            `json:"sale_id,omitempty"`
+	CaptureId                string            `json:"capture_id,omitempty"`
+	ParentPayment            string            `json:"parent_payment,omitempty"`
+	Description              string            `json:"description,omitempty"`
+	CreateTime               string            `json:"create_time,omitempty"`
+	UpdateTime               string            `json:"update_time,omitempty"`
+	ReasonCode               string            `json:"reason_code,omitempty"`
+	RefundFromTransactionFee *Currency         `json:"refund_from_transaction_fee,omitempty"`
+	RefundFromReceivedAmount *Currency         `json:"refund_from_received_amount,omitempty"`
+	TotalRefundedAmount      *Currency         `json:"total_refunded_amount,omitempty"`
+	Links                    []*Link           `json:"links,omitempty"`
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/payments/sale/5C5052428C036462C
+// Show sale details
+// 查询扣款详情
+*/
+
+func (c *Client) ShowSale(saleID string) (*Sale, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.APIBase, K_SALE_API, saleID), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &Sale{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/payments/sale/5C5052428C036462C/refund
+// Refund sale
+// 触发webhook： PAYMENT.SALE.REFUNDED
+// 退款, q 为空或 q.Amount 为空时全额退款, 否则部分退款。
+*/
+
+func (c *Client) RefundSale(saleID string, q *RefundSaleReq) (*DetailedRefund, error) {
+	if q == nil {
+		q = &RefundSaleReq{}
+	}
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/refund", c.APIBase, K_SALE_API, saleID), q)
+	rsp := &DetailedRefund{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
@@ -37,6 +37,9 @@ const (
 	E_SUBSCRIPTION_STATUS_EXPIRED          E_SubscriptionStatus = "EXPIRED"
 )
 
+// Int returns 1 for ACTIVE and 0 otherwise.
+//
+// Deprecated: it cannot tell a subscription in grace period from a cancelled one, use Subscription.IsEntitled
 func (s E_SubscriptionStatus) Int() int {
 	if s == E_SUBSCRIPTION_STATUS_ACTIVE {
 		return 1
@@ -72,11 +75,11 @@ type Subscription struct {
 
 func (c *Client) CreateSubscription(q *CreateSubscriptionReq) (*Subscription, error) {
 	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/billing/subscriptions"), q)
-	req.Header.Add("Prefer", "return=representation")
 	rsp := &Subscription{}
 	if err != nil {
 		return rsp, err
 	}
+	req.Header.Add("Prefer", "return=representation")
 	err = c.SendWithAuth(req, rsp)
 	return rsp, err
 }
@@ -136,6 +139,9 @@ type UpdateSubscriptionReq struct {
 }
 
 func (c *Client) ActivateSubscription(subId, reason string) error {
+	if err := c.checkSubscriptionAction(subId, E_SUBSCRIPTION_ACTION_ACTIVATE); err != nil {
+		return err
+	}
 	as := &UpdateSubscriptionReq{Reason: reason}
 	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/activate", c.APIBase, K_SUBSCRIPTION_API, subId), as)
 	if err != nil {
@@ -153,6 +159,9 @@ func (c *Client) ActivateSubscription(subId, reason string) error {
 // 取消
 */
 func (c *Client) CancelSubscription(subID, reason string) error {
+	if err := c.checkSubscriptionAction(subID, E_SUBSCRIPTION_ACTION_CANCEL); err != nil {
+		return err
+	}
 	as := &UpdateSubscriptionReq{Reason: reason}
 	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/cancel", c.APIBase, K_SUBSCRIPTION_API, subID), as)
 	if err != nil {
@@ -214,6 +223,9 @@ func (c *Client) ListTransactionsForSubscription(subID, startTime, endTime strin
 */
 
 func (c *Client) SuspendSubscription(subId, reason string) error {
+	if err := c.checkSubscriptionAction(subId, E_SUBSCRIPTION_ACTION_SUSPEND); err != nil {
+		return err
+	}
 	as := &UpdateSubscriptionReq{Reason: reason}
 	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/suspend", c.APIBase, K_SUBSCRIPTION_API, subId), as)
 	if err != nil {
@@ -0,0 +1,123 @@
+package paypalsdk
+
+import "fmt"
+
+/*
+订阅状态机：
+
+	APPROVAL_PENDING --买家批准--> APPROVED --activate--> ACTIVE
+	APPROVAL_PENDING --买家批准(SUBSCRIBE_NOW)--> ACTIVE
+	ACTIVE --suspend / 扣款失败达到阈值--> SUSPENDED --activate--> ACTIVE
+	APPROVED, ACTIVE, SUSPENDED --cancel--> CANCELLED
+	ACTIVE --最后一个计费周期完成--> EXPIRED
+
+CANCELLED 和 EXPIRED 是终态。对不允许的状态调用 activate/suspend/cancel, PayPal 返回 422 SUBSCRIPTION_STATUS_INVALID。
+*/
+
+var subscriptionTransitions = map[E_SubscriptionStatus][]E_SubscriptionStatus{
+	E_SUBSCRIPTION_STATUS_APPROVAL_PENDING: {E_SUBSCRIPTION_STATUS_APPROVAL, E_SUBSCRIPTION_STATUS_ACTIVE},
+	E_SUBSCRIPTION_STATUS_APPROVAL:         {E_SUBSCRIPTION_STATUS_ACTIVE, E_SUBSCRIPTION_STATUS_CANCELLED},
+	E_SUBSCRIPTION_STATUS_ACTIVE:           {E_SUBSCRIPTION_STATUS_SUSPENDED, E_SUBSCRIPTION_STATUS_CANCELLED, E_SUBSCRIPTION_STATUS_EXPIRED},
+	E_SUBSCRIPTION_STATUS_SUSPENDED:        {E_SUBSCRIPTION_STATUS_ACTIVE, E_SUBSCRIPTION_STATUS_CANCELLED},
+}
+
+// CanTransition reports whether a subscription can go from one status to another, by an API call, the buyer or PayPal
+func CanTransition(from, to E_SubscriptionStatus) bool {
+	for _, s := range subscriptionTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal reports whether no transition leaves the status
+func (s E_SubscriptionStatus) IsTerminal() bool {
+	return s == E_SUBSCRIPTION_STATUS_CANCELLED || s == E_SUBSCRIPTION_STATUS_EXPIRED
+}
+
+// E_SubscriptionAction 是可以通过 API 对订阅执行的操作
+type E_SubscriptionAction string
+
+const (
+	E_SUBSCRIPTION_ACTION_ACTIVATE E_SubscriptionAction = "activate"
+	E_SUBSCRIPTION_ACTION_SUSPEND  E_SubscriptionAction = "suspend"
+	E_SUBSCRIPTION_ACTION_CANCEL   E_SubscriptionAction = "cancel"
+)
+
+// Status 返回操作成功后订阅的状态
+func (a E_SubscriptionAction) Status() E_SubscriptionStatus {
+	switch a {
+	case E_SUBSCRIPTION_ACTION_ACTIVATE:
+		return E_SUBSCRIPTION_STATUS_ACTIVE
+	case E_SUBSCRIPTION_ACTION_SUSPEND:
+		return E_SUBSCRIPTION_STATUS_SUSPENDED
+	case E_SUBSCRIPTION_ACTION_CANCEL:
+		return E_SUBSCRIPTION_STATUS_CANCELLED
+	}
+	return ""
+}
+
+// CanApply reports whether action can be called on a subscription in status.
+// Unlike CanTransition, APPROVAL_PENDING cannot be activated: only the buyer approves it
+func (a E_SubscriptionAction) CanApply(status E_SubscriptionStatus) bool {
+	if status == E_SUBSCRIPTION_STATUS_APPROVAL_PENDING {
+		return false
+	}
+	return CanTransition(status, a.Status())
+}
+
+func (s *Subscription) CanActivate() bool {
+	return E_SUBSCRIPTION_ACTION_ACTIVATE.CanApply(s.Status)
+}
+
+func (s *Subscription) CanSuspend() bool {
+	return E_SUBSCRIPTION_ACTION_SUSPEND.CanApply(s.Status)
+}
+
+func (s *Subscription) CanCancel() bool {
+	return E_SUBSCRIPTION_ACTION_CANCEL.CanApply(s.Status)
+}
+
+// SubscriptionTransitionError is returned by the pre-flight checks when a subscription cannot take an action
+type SubscriptionTransitionError struct {
+	SubscriptionID string
+	Action         E_SubscriptionAction
+	Status         E_SubscriptionStatus
+}
+
+func (e *SubscriptionTransitionError) Error() string {
+	return fmt.Sprintf("paypalsdk: cannot %s subscription %s in status %s", e.Action, e.SubscriptionID, e.Status)
+}
+
+// checkSubscriptionAction 在 PreflightChecks 打开时查询订阅状态, 不允许执行 action 时返回 *SubscriptionTransitionError
+func (c *Client) checkSubscriptionAction(subID string, action E_SubscriptionAction) error {
+	if !c.PreflightChecks {
+		return nil
+	}
+	sub, err := c.ShowSubscriptionDetails(subID)
+	if err != nil {
+		return err
+	}
+	if !action.CanApply(sub.Status) {
+		return &SubscriptionTransitionError{SubscriptionID: subID, Action: action, Status: sub.Status}
+	}
+	return nil
+}
+
+// 订阅事件与事件发生后订阅的状态
+var subscriptionEventStatus = map[string]E_SubscriptionStatus{
+	E_EVENT_TYPE_BILLING_SUBSCRIPTION_CREATED:   E_SUBSCRIPTION_STATUS_APPROVAL_PENDING,
+	E_EVENT_TYPE_BILLING_SUBSCRIPTION_ACTIVATED: E_SUBSCRIPTION_STATUS_ACTIVE,
+	E_EVENT_TYPE_BILLING_SUBSCRIPTION_RENEWED:   E_SUBSCRIPTION_STATUS_ACTIVE,
+	E_EVENT_TYPE_BILLING_SUBSCRIPTION_SUSPENDED: E_SUBSCRIPTION_STATUS_SUSPENDED,
+	E_EVENT_TYPE_BILLING_SUBSCRIPTION_CANCELLED: E_SUBSCRIPTION_STATUS_CANCELLED,
+	E_EVENT_TYPE_BILLING_SUBSCRIPTION_EXPIRED:   E_SUBSCRIPTION_STATUS_EXPIRED,
+}
+
+// SubscriptionStatusForEvent returns the status of a subscription after an event of eventType.
+// ok is false for events not changing the status, eg: BILLING.SUBSCRIPTION.UPDATED, BILLING.SUBSCRIPTION.PAYMENT.FAILED
+func SubscriptionStatusForEvent(eventType string) (status E_SubscriptionStatus, ok bool) {
+	status, ok = subscriptionEventStatus[eventType]
+	return
+}
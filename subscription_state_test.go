@@ -0,0 +1,141 @@
+package paypalsdk
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var allSubscriptionStatuses = []E_SubscriptionStatus{
+	E_SUBSCRIPTION_STATUS_APPROVAL_PENDING,
+	E_SUBSCRIPTION_STATUS_APPROVAL,
+	E_SUBSCRIPTION_STATUS_ACTIVE,
+	E_SUBSCRIPTION_STATUS_SUSPENDED,
+	E_SUBSCRIPTION_STATUS_CANCELLED,
+	E_SUBSCRIPTION_STATUS_EXPIRED,
+}
+
+func TestCanTransition(t *testing.T) {
+	allowed := map[[2]E_SubscriptionStatus]bool{
+		{E_SUBSCRIPTION_STATUS_APPROVAL_PENDING, E_SUBSCRIPTION_STATUS_APPROVAL}: true,
+		{E_SUBSCRIPTION_STATUS_APPROVAL_PENDING, E_SUBSCRIPTION_STATUS_ACTIVE}:   true,
+		{E_SUBSCRIPTION_STATUS_APPROVAL, E_SUBSCRIPTION_STATUS_ACTIVE}:           true,
+		{E_SUBSCRIPTION_STATUS_APPROVAL, E_SUBSCRIPTION_STATUS_CANCELLED}:        true,
+		{E_SUBSCRIPTION_STATUS_ACTIVE, E_SUBSCRIPTION_STATUS_SUSPENDED}:          true,
+		{E_SUBSCRIPTION_STATUS_ACTIVE, E_SUBSCRIPTION_STATUS_CANCELLED}:          true,
+		{E_SUBSCRIPTION_STATUS_ACTIVE, E_SUBSCRIPTION_STATUS_EXPIRED}:            true,
+		{E_SUBSCRIPTION_STATUS_SUSPENDED, E_SUBSCRIPTION_STATUS_ACTIVE}:          true,
+		{E_SUBSCRIPTION_STATUS_SUSPENDED, E_SUBSCRIPTION_STATUS_CANCELLED}:       true,
+	}
+	for _, from := range allSubscriptionStatuses {
+		for _, to := range allSubscriptionStatuses {
+			if got, want := CanTransition(from, to), allowed[[2]E_SubscriptionStatus{from, to}]; got != want {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+	if CanTransition("UNKNOWN", E_SUBSCRIPTION_STATUS_ACTIVE) {
+		t.Error("CanTransition from an unknown status = true")
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	for _, s := range allSubscriptionStatuses {
+		want := s == E_SUBSCRIPTION_STATUS_CANCELLED || s == E_SUBSCRIPTION_STATUS_EXPIRED
+		if s.IsTerminal() != want {
+			t.Errorf("%s.IsTerminal() = %v, want %v", s, s.IsTerminal(), want)
+		}
+		// 终态没有出边
+		for _, to := range allSubscriptionStatuses {
+			if want && CanTransition(s, to) {
+				t.Errorf("terminal status %s can transition to %s", s, to)
+			}
+		}
+	}
+}
+
+func TestCanApply(t *testing.T) {
+	tests := []struct {
+		status                    E_SubscriptionStatus
+		activate, suspend, cancel bool
+	}{
+		{E_SUBSCRIPTION_STATUS_APPROVAL_PENDING, false, false, false},
+		{E_SUBSCRIPTION_STATUS_APPROVAL, true, false, true},
+		{E_SUBSCRIPTION_STATUS_ACTIVE, false, true, true},
+		{E_SUBSCRIPTION_STATUS_SUSPENDED, true, false, true},
+		{E_SUBSCRIPTION_STATUS_CANCELLED, false, false, false},
+		{E_SUBSCRIPTION_STATUS_EXPIRED, false, false, false},
+	}
+	for _, tt := range tests {
+		s := &Subscription{Status: tt.status}
+		if s.CanActivate() != tt.activate {
+			t.Errorf("%s: CanActivate = %v, want %v", tt.status, s.CanActivate(), tt.activate)
+		}
+		if s.CanSuspend() != tt.suspend {
+			t.Errorf("%s: CanSuspend = %v, want %v", tt.status, s.CanSuspend(), tt.suspend)
+		}
+		if s.CanCancel() != tt.cancel {
+			t.Errorf("%s: CanCancel = %v, want %v", tt.status, s.CanCancel(), tt.cancel)
+		}
+	}
+	if E_SubscriptionAction("pause").CanApply(E_SUBSCRIPTION_STATUS_ACTIVE) {
+		t.Error("unknown action can apply")
+	}
+}
+
+func TestSubscriptionStatusForEvent(t *testing.T) {
+	tests := []struct {
+		eventType string
+		status    E_SubscriptionStatus
+		ok        bool
+	}{
+		{E_EVENT_TYPE_BILLING_SUBSCRIPTION_CREATED, E_SUBSCRIPTION_STATUS_APPROVAL_PENDING, true},
+		{E_EVENT_TYPE_BILLING_SUBSCRIPTION_ACTIVATED, E_SUBSCRIPTION_STATUS_ACTIVE, true},
+		{E_EVENT_TYPE_BILLING_SUBSCRIPTION_RENEWED, E_SUBSCRIPTION_STATUS_ACTIVE, true},
+		{E_EVENT_TYPE_BILLING_SUBSCRIPTION_SUSPENDED, E_SUBSCRIPTION_STATUS_SUSPENDED, true},
+		{E_EVENT_TYPE_BILLING_SUBSCRIPTION_CANCELLED, E_SUBSCRIPTION_STATUS_CANCELLED, true},
+		{E_EVENT_TYPE_BILLING_SUBSCRIPTION_EXPIRED, E_SUBSCRIPTION_STATUS_EXPIRED, true},
+		{E_EVENT_TYPE_BILLING_SUBSCRIPTION_UPDATED, "", false},
+		{E_EVENT_TYPE_BILLING_SUBSCRIPTION_PAYMENT_FAILED, "", false},
+		{E_EVENT_TYPE_PAYMENT_SALE_COMPLETED, "", false},
+	}
+	for _, tt := range tests {
+		status, ok := SubscriptionStatusForEvent(tt.eventType)
+		if status != tt.status || ok != tt.ok {
+			t.Errorf("SubscriptionStatusForEvent(%s) = %s, %v, want %s, %v", tt.eventType, status, ok, tt.status, tt.ok)
+		}
+	}
+}
+
+func TestPreflightChecks(t *testing.T) {
+	var actions []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			actions = append(actions, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte(`{"id":"I-1","status":"CANCELLED"}`))
+	}))
+	defer srv.Close()
+	c, err := NewClient("id", "secret", WithAPIBase(srv.URL), WithLogger(discardLogger{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetAccessToken("token")
+
+	// 关闭时不查询状态, 直接调用
+	if err := c.SuspendSubscription("I-1", "vacation"); err != nil {
+		t.Fatalf("SuspendSubscription without preflight checks error = %v", err)
+	}
+	c.SetPreflightChecks(true)
+	err = c.ActivateSubscription("I-1", "back")
+	var terr *SubscriptionTransitionError
+	if !errors.As(err, &terr) || terr.Action != E_SUBSCRIPTION_ACTION_ACTIVATE || terr.Status != E_SUBSCRIPTION_STATUS_CANCELLED {
+		t.Errorf("ActivateSubscription of a cancelled subscription error = %v", err)
+	}
+	if len(actions) != 1 {
+		t.Errorf("actions sent = %v, want only the suspension", actions)
+	}
+}
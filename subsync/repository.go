@@ -0,0 +1,97 @@
+package subsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+var ErrNotFound = errors.New("subsync: subscription not found")
+
+// Record is a subscription of the mirror
+type Record struct {
+	Subscription *paypalsdk.Subscription
+	// PaidThrough is Subscription.PaidThrough, kept from before when PayPal drops it, eg: on cancellation
+	PaidThrough time.Time
+	// SyncedAt is when the record was last written from an event or PayPal
+	SyncedAt time.Time
+}
+
+func (r *Record) ID() string {
+	return r.Subscription.ID
+}
+
+// IsEntitled is Subscription.IsEntitled, except that cancelled and expired subscriptions are entitled until PaidThrough
+func (r *Record) IsEntitled(now time.Time) bool {
+	return paypalsdk.DefaultEntitlementPolicy.IsEntitledThrough(r.Subscription, r.PaidThrough, now)
+}
+
+// Repository stores the mirror. Implementations must be safe for concurrent use
+type Repository interface {
+	// Get returns ErrNotFound if the subscription is not in the mirror
+	Get(ctx context.Context, id string) (*Record, error)
+	// Put stores r unless the stored subscription has a later UpdateTime, it reports whether r was stored.
+	// The check and the write must be atomic, so that an event delivered late does not overwrite a newer state
+	Put(ctx context.Context, r *Record) (bool, error)
+	// List returns the records synced before syncedBefore, for reconciliation
+	List(ctx context.Context, syncedBefore time.Time) ([]*Record, error)
+}
+
+// MemoryRepository is a Repository in memory, for tests and single-process apps
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	records map[string]*Record
+}
+
+var _ Repository = (*MemoryRepository)(nil)
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{records: map[string]*Record{}}
+}
+
+func (m *MemoryRepository) Get(ctx context.Context, id string) (*Record, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.records[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyRecord(r), nil
+}
+
+func (m *MemoryRepository) Put(ctx context.Context, r *Record) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if old, ok := m.records[r.ID()]; ok && old.Subscription.UpdateTime.After(r.Subscription.UpdateTime) {
+		return false, nil
+	}
+	m.records[r.ID()] = copyRecord(r)
+	return true, nil
+}
+
+func (m *MemoryRepository) List(ctx context.Context, syncedBefore time.Time) ([]*Record, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var list []*Record
+	for _, r := range m.records {
+		if r.SyncedAt.Before(syncedBefore) {
+			list = append(list, copyRecord(r))
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID() < list[j].ID() })
+	return list, nil
+}
+
+// copyRecord 深拷贝记录, 调用方修改返回值不影响仓库中的数据
+func copyRecord(r *Record) *Record {
+	cp := *r
+	cp.Subscription = &paypalsdk.Subscription{}
+	data, _ := json.Marshal(r.Subscription)
+	json.Unmarshal(data, cp.Subscription)
+	return &cp
+}
@@ -0,0 +1,182 @@
+package subsync
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+// Schema is the table of SQLRepository, with the default table name. Times are stored as Unix nanoseconds
+// so that they compare the same in every database
+const Schema = `CREATE TABLE paypal_subscriptions (
+	id           VARCHAR(64) PRIMARY KEY,
+	status       VARCHAR(32) NOT NULL,
+	plan_id      VARCHAR(64) NOT NULL,
+	update_time  BIGINT      NOT NULL,
+	paid_through BIGINT      NOT NULL,
+	synced_at    BIGINT      NOT NULL,
+	data         TEXT        NOT NULL
+)`
+
+const kDefaultTable = "paypal_subscriptions"
+
+// SQLRepository is a Repository in a database/sql table, see Schema
+type SQLRepository struct {
+	db          *sql.DB
+	table       string
+	placeholder func(n int) string
+}
+
+var _ Repository = (*SQLRepository)(nil)
+
+type SQLOption func(*SQLRepository)
+
+// WithTable sets the table name, paypal_subscriptions by default
+func WithTable(table string) SQLOption {
+	return func(r *SQLRepository) {
+		r.table = table
+	}
+}
+
+// WithDollarPlaceholders uses $1, $2... placeholders, for PostgreSQL. ? is used by default
+func WithDollarPlaceholders() SQLOption {
+	return func(r *SQLRepository) {
+		r.placeholder = func(n int) string { return fmt.Sprintf("$%d", n) }
+	}
+}
+
+func NewSQLRepository(db *sql.DB, opts ...SQLOption) *SQLRepository {
+	r := &SQLRepository{
+		db:          db,
+		table:       kDefaultTable,
+		placeholder: func(int) string { return "?" },
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// query replaces the ? of q with the placeholders of the database
+func (r *SQLRepository) query(q string) string {
+	q = strings.Replace(q, "{table}", r.table, -1)
+	var b strings.Builder
+	n := 0
+	for _, ch := range q {
+		if ch == '?' {
+			n++
+			b.WriteString(r.placeholder(n))
+			continue
+		}
+		b.WriteRune(ch)
+	}
+	return b.String()
+}
+
+func (r *SQLRepository) Get(ctx context.Context, id string) (*Record, error) {
+	row := r.db.QueryRowContext(ctx, r.query("SELECT data, paid_through, synced_at FROM {table} WHERE id = ?"), id)
+	return scanRecord(row)
+}
+
+// Put updates the record if the stored one is not newer, or inserts it. Two Puts of a new subscription may both
+// find no row to update: the INSERT of the second fails on the primary key, and its guarded UPDATE is retried.
+// With MySQL, the DSN must set clientFoundRows=true, otherwise an UPDATE writing the same values reports no row
+func (r *SQLRepository) Put(ctx context.Context, rec *Record) (bool, error) {
+	data, err := json.Marshal(rec.Subscription)
+	if err != nil {
+		return false, err
+	}
+	sub := rec.Subscription
+	values := []interface{}{string(sub.Status), sub.PlanID, unixNano(sub.UpdateTime), unixNano(rec.PaidThrough), unixNano(rec.SyncedAt), string(data)}
+
+	if stored, err := r.update(ctx, sub, values); err != nil || stored {
+		return stored, err
+	}
+	// 没有更新时, 要么记录不存在, 要么已保存的更新
+	_, err = r.db.ExecContext(ctx, r.query(`INSERT INTO {table} (status, plan_id, update_time, paid_through, synced_at, data, id)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`), append(values, sub.ID)...)
+	if err == nil {
+		return true, nil
+	}
+	// 主键冲突: 记录已存在, 或在 UPDATE 之后被并发插入, 重试带条件的 UPDATE
+	var exists int
+	if r.db.QueryRowContext(ctx, r.query("SELECT 1 FROM {table} WHERE id = ?"), sub.ID).Scan(&exists) != nil {
+		return false, err
+	}
+	return r.update(ctx, sub, values)
+}
+
+// update writes values if the stored record is not newer than sub, the check and the write are one statement
+func (r *SQLRepository) update(ctx context.Context, sub *paypalsdk.Subscription, values []interface{}) (bool, error) {
+	res, err := r.db.ExecContext(ctx, r.query(`UPDATE {table}
+		SET status = ?, plan_id = ?, update_time = ?, paid_through = ?, synced_at = ?, data = ?
+		WHERE id = ? AND update_time <= ?`), append(values, sub.ID, unixNano(sub.UpdateTime))...)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *SQLRepository) List(ctx context.Context, syncedBefore time.Time) ([]*Record, error) {
+	rows, err := r.db.QueryContext(ctx, r.query("SELECT data, paid_through, synced_at FROM {table} WHERE synced_at < ? ORDER BY id"), unixNano(syncedBefore))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []*Record
+	for rows.Next() {
+		rec, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, rec)
+	}
+	return list, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRecord(s scanner) (*Record, error) {
+	var (
+		data                  string
+		paidThrough, syncedAt int64
+	)
+	if err := s.Scan(&data, &paidThrough, &syncedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	rec := &Record{
+		Subscription: &paypalsdk.Subscription{},
+		PaidThrough:  fromUnixNano(paidThrough),
+		SyncedAt:     fromUnixNano(syncedAt),
+	}
+	if err := json.Unmarshal([]byte(data), rec.Subscription); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// 零值时间存为 0
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func fromUnixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n).UTC()
+}
@@ -0,0 +1,362 @@
+package subsync
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+var errDuplicateKey = errors.New("fakedb: duplicate primary key")
+
+// fakeDB is a database/sql driver keeping the table in a map, it only runs the statements of SQLRepository.
+// Arguments are taken in order, whatever the placeholders are
+type fakeDB struct {
+	mu      sync.Mutex
+	rows    map[string]fakeRow
+	queries []string
+	// beforeInsert runs before an INSERT, to simulate a concurrent writer
+	beforeInsert func(db *fakeDB)
+	// insertErr fails the INSERTs
+	insertErr error
+}
+
+type fakeRow struct {
+	status, planID                    string
+	updateTime, paidThrough, syncedAt int64
+	data                              string
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{rows: map[string]fakeRow{}}
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return db }
+func (db *fakeDB) Open(string) (driver.Conn, error)             { return fakeConn{db}, nil }
+
+// statements returns the statements run so far, with the whitespace collapsed
+func (db *fakeDB) statements() []string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return append([]string(nil), db.queries...)
+}
+
+func (db *fakeDB) count(verb string) int {
+	n := 0
+	for _, q := range db.statements() {
+		if strings.HasPrefix(q, verb) {
+			n++
+		}
+	}
+	return n
+}
+
+type fakeConn struct{ db *fakeDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeStmt{c.db, strings.Join(strings.Fields(query), " ")}, nil
+}
+func (c fakeConn) Close() error { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fakedb: transactions not supported")
+}
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	db := s.db
+	if strings.HasPrefix(s.query, "INSERT") && db.beforeInsert != nil {
+		db.beforeInsert(db)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.queries = append(db.queries, s.query)
+
+	row := fakeRow{args[0].(string), args[1].(string), args[2].(int64), args[3].(int64), args[4].(int64), args[5].(string)}
+	id := args[6].(string)
+	switch {
+	case strings.HasPrefix(s.query, "INSERT"):
+		if db.insertErr != nil {
+			return nil, db.insertErr
+		}
+		if _, ok := db.rows[id]; ok {
+			return nil, errDuplicateKey
+		}
+		db.rows[id] = row
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(s.query, "UPDATE"):
+		old, ok := db.rows[id]
+		if !ok || old.updateTime > args[7].(int64) {
+			return driver.RowsAffected(0), nil
+		}
+		db.rows[id] = row
+		return driver.RowsAffected(1), nil
+	}
+	return nil, errors.New("fakedb: unexpected statement " + s.query)
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.queries = append(db.queries, s.query)
+
+	rows := &fakeRows{}
+	switch {
+	case strings.HasPrefix(s.query, "SELECT 1 "):
+		if _, ok := db.rows[args[0].(string)]; ok {
+			rows.values = append(rows.values, []driver.Value{int64(1)})
+		}
+	case strings.Contains(s.query, "WHERE id ="):
+		if row, ok := db.rows[args[0].(string)]; ok {
+			rows.values = append(rows.values, []driver.Value{row.data, row.paidThrough, row.syncedAt})
+		}
+	case strings.Contains(s.query, "WHERE synced_at <"):
+		var ids []string
+		for id, row := range db.rows {
+			if row.syncedAt < args[0].(int64) {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			row := db.rows[id]
+			rows.values = append(rows.values, []driver.Value{row.data, row.paidThrough, row.syncedAt})
+		}
+	default:
+		return nil, errors.New("fakedb: unexpected query " + s.query)
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	if len(r.values) > 0 && len(r.values[0]) == 1 {
+		return []string{"1"}
+	}
+	return []string{"data", "paid_through", "synced_at"}
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func newSQLRepository(t *testing.T, opts ...SQLOption) (*SQLRepository, *fakeDB) {
+	fake := newFakeDB()
+	db := sql.OpenDB(fake)
+	t.Cleanup(func() { db.Close() })
+	return NewSQLRepository(db, opts...), fake
+}
+
+func TestSQLRepositoryPut(t *testing.T) {
+	paid := t0.AddDate(0, 1, 0)
+	tests := []struct {
+		status     paypalsdk.E_SubscriptionStatus
+		updated    time.Time
+		wantStored bool
+		wantStatus paypalsdk.E_SubscriptionStatus
+	}{
+		{paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, t0, true, paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE},
+		{paypalsdk.E_SUBSCRIPTION_STATUS_APPROVAL, t0.Add(-time.Second), false, paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE},
+		{paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED, t0, true, paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED},
+		{paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED, t0.Add(time.Hour), true, paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED},
+		{paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, t0.Add(time.Minute), false, paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED},
+	}
+	repo, fake := newSQLRepository(t)
+	ctx := context.Background()
+	if _, err := repo.Get(ctx, "I-1"); err != ErrNotFound {
+		t.Fatalf("Get of a new subscription error = %v, want ErrNotFound", err)
+	}
+	for i, tt := range tests {
+		rec := &Record{Subscription: subscription(tt.status, tt.updated, paid), PaidThrough: paid, SyncedAt: t0.Add(time.Duration(i) * time.Minute)}
+		stored, err := repo.Put(ctx, rec)
+		if err != nil {
+			t.Fatalf("Put %d: %v", i, err)
+		}
+		if stored != tt.wantStored {
+			t.Errorf("Put %d (%s at %v): stored = %v, want %v", i, tt.status, tt.updated, stored, tt.wantStored)
+		}
+		got, err := repo.Get(ctx, "I-1")
+		if err != nil {
+			t.Fatalf("Get after Put %d: %v", i, err)
+		}
+		if got.Subscription.Status != tt.wantStatus {
+			t.Errorf("Put %d: stored status = %s, want %s", i, got.Subscription.Status, tt.wantStatus)
+		}
+	}
+	// 新记录和没有写入的旧记录才尝试 INSERT, 主键冲突后重试 UPDATE
+	if n, m := fake.count("INSERT"), fake.count("UPDATE"); n != 3 || m != 7 {
+		t.Errorf("%d INSERTs and %d UPDATEs, want 3 and 7", n, m)
+	}
+
+	got, _ := repo.Get(ctx, "I-1")
+	if !got.PaidThrough.Equal(paid) || !got.SyncedAt.Equal(t0.Add(3*time.Minute)) || !got.Subscription.UpdateTime.Equal(t0.Add(time.Hour)) {
+		t.Errorf("Get = %+v, PaidThrough %v, SyncedAt %v", got.Subscription, got.PaidThrough, got.SyncedAt)
+	}
+	if row := fake.rows["I-1"]; row.status != "CANCELLED" || row.updateTime != t0.Add(time.Hour).UnixNano() {
+		t.Errorf("stored row = %+v", row)
+	}
+}
+
+func TestSQLRepositoryInsertRace(t *testing.T) {
+	tests := []struct {
+		name       string
+		concurrent time.Time // update_time 的并发插入的记录, 零值表示没有
+		insertErr  error
+		wantStored bool
+		wantErr    error
+		wantTime   time.Time
+	}{
+		{"no race", time.Time{}, nil, true, nil, t0},
+		{"older inserted concurrently", t0.Add(-time.Hour), nil, true, nil, t0},
+		{"same inserted concurrently", t0, nil, true, nil, t0},
+		{"newer inserted concurrently", t0.Add(time.Hour), nil, false, nil, t0.Add(time.Hour)},
+		{"insert fails", time.Time{}, errors.New("connection reset"), false, errors.New("connection reset"), time.Time{}},
+	}
+	for _, tt := range tests {
+		repo, fake := newSQLRepository(t)
+		ctx := context.Background()
+		fake.insertErr = tt.insertErr
+		if !tt.concurrent.IsZero() {
+			// 另一个进程在 UPDATE 和 INSERT 之间插入了记录
+			fake.beforeInsert = func(db *fakeDB) {
+				db.beforeInsert = nil
+				if _, err := repo.Put(ctx, &Record{Subscription: subscription(paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED, tt.concurrent, time.Time{})}); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		stored, err := repo.Put(ctx, &Record{Subscription: subscription(paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, t0, time.Time{})})
+		if stored != tt.wantStored || (err == nil) != (tt.wantErr == nil) || err != nil && err.Error() != tt.wantErr.Error() {
+			t.Errorf("%s: Put = %v, %v, want %v, %v", tt.name, stored, err, tt.wantStored, tt.wantErr)
+		}
+		got, err := repo.Get(ctx, "I-1")
+		if tt.wantTime.IsZero() {
+			if err != ErrNotFound {
+				t.Errorf("%s: Get error = %v, want ErrNotFound", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: Get error = %v", tt.name, err)
+		}
+		if !got.Subscription.UpdateTime.Equal(tt.wantTime) {
+			t.Errorf("%s: stored update_time = %v, want %v", tt.name, got.Subscription.UpdateTime, tt.wantTime)
+		}
+	}
+}
+
+func TestSQLRepositoryStatements(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []SQLOption
+		wantGet    string
+		wantUpdate string
+		wantInsert string
+	}{
+		{
+			name:       "default",
+			wantGet:    "SELECT data, paid_through, synced_at FROM paypal_subscriptions WHERE id = ?",
+			wantUpdate: "UPDATE paypal_subscriptions SET status = ?, plan_id = ?, update_time = ?, paid_through = ?, synced_at = ?, data = ? WHERE id = ? AND update_time <= ?",
+			wantInsert: "INSERT INTO paypal_subscriptions (status, plan_id, update_time, paid_through, synced_at, data, id) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		},
+		{
+			name:       "dollar placeholders",
+			opts:       []SQLOption{WithDollarPlaceholders()},
+			wantGet:    "SELECT data, paid_through, synced_at FROM paypal_subscriptions WHERE id = $1",
+			wantUpdate: "UPDATE paypal_subscriptions SET status = $1, plan_id = $2, update_time = $3, paid_through = $4, synced_at = $5, data = $6 WHERE id = $7 AND update_time <= $8",
+			wantInsert: "INSERT INTO paypal_subscriptions (status, plan_id, update_time, paid_through, synced_at, data, id) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		},
+		{
+			name:       "table",
+			opts:       []SQLOption{WithTable("billing.subscriptions"), WithDollarPlaceholders()},
+			wantGet:    "SELECT data, paid_through, synced_at FROM billing.subscriptions WHERE id = $1",
+			wantUpdate: "UPDATE billing.subscriptions SET status = $1, plan_id = $2, update_time = $3, paid_through = $4, synced_at = $5, data = $6 WHERE id = $7 AND update_time <= $8",
+			wantInsert: "INSERT INTO billing.subscriptions (status, plan_id, update_time, paid_through, synced_at, data, id) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		},
+	}
+	for _, tt := range tests {
+		repo, fake := newSQLRepository(t, tt.opts...)
+		ctx := context.Background()
+		if _, err := repo.Put(ctx, &Record{Subscription: subscription(paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, t0, time.Time{})}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.Get(ctx, "I-1"); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{tt.wantUpdate, tt.wantInsert, tt.wantGet}
+		got := fake.statements()
+		if len(got) != len(want) {
+			t.Fatalf("%s: statements = %q", tt.name, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s: statement %d = %q, want %q", tt.name, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestSQLRepositoryList(t *testing.T) {
+	repo, _ := newSQLRepository(t)
+	ctx := context.Background()
+	for i, id := range []string{"I-3", "I-1", "I-2"} {
+		sub := subscription(paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, t0, time.Time{})
+		sub.ID = id
+		if _, err := repo.Put(ctx, &Record{Subscription: sub, SyncedAt: t0.Add(time.Duration(i) * time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 从未同步的记录存为 0, 总是需要对账
+	if _, err := repo.Put(ctx, &Record{Subscription: &paypalsdk.Subscription{ID: "I-0", UpdateTime: t0}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		syncedBefore time.Time
+		want         []string
+	}{
+		{t0, []string{"I-0"}},
+		{t0.Add(time.Hour), []string{"I-0", "I-3"}},
+		{t0.Add(3 * time.Hour), []string{"I-0", "I-1", "I-2", "I-3"}},
+	}
+	for _, tt := range tests {
+		list, err := repo.List(ctx, tt.syncedBefore)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, rec := range list {
+			got = append(got, rec.ID())
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("List(%v) = %v, want %v", tt.syncedBefore, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Package subsync maintains a local mirror of PayPal subscriptions, for fast reads without ShowSubscriptionDetails:
+//
+//	s := subsync.New(client, subsync.NewMemoryRepository())
+//	http.Handle("/paypal/webhook", client.WebhookHandler(webhookID, s.HandleEvent))
+//	go s.Run(ctx, time.Hour)
+//	...
+//	rec, err := s.Get(ctx, subscriptionID)
+//	if err == nil && rec.IsEntitled(time.Now()) { ... }
+//
+// The mirror is updated from BILLING.SUBSCRIPTION.* events, whose resource is the subscription, and from
+// PAYMENT.SALE.* events, after which the subscription is fetched from PayPal. Events are not delivered in order,
+// so a subscription is only written if its update_time is not older than the stored one. Run reconciles the mirror
+// against ShowSubscriptionDetails periodically, to repair missed events.
+package subsync
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+)
+
+// DefaultStaleAfter is how long after its last sync a subscription is reconciled
+const DefaultStaleAfter = time.Hour
+
+// Syncer keeps a Repository in sync with PayPal
+type Syncer struct {
+	api  paypalsdk.SubscriptionsAPI
+	repo Repository
+
+	// StaleAfter is how long after its last sync a subscription is reconciled, DefaultStaleAfter by default
+	StaleAfter time.Duration
+	// Now returns the current time, time.Now by default
+	Now func() time.Time
+	// OnError is called with the errors of Reconcile, subscriptionID is empty if the repository failed.
+	// Run does not stop on errors, they are only reported here
+	OnError func(subscriptionID string, err error)
+}
+
+func New(api paypalsdk.SubscriptionsAPI, repo Repository) *Syncer {
+	return &Syncer{
+		api:        api,
+		repo:       repo,
+		StaleAfter: DefaultStaleAfter,
+		Now:        time.Now,
+	}
+}
+
+// Get returns the subscription from the mirror, or ErrNotFound
+func (s *Syncer) Get(ctx context.Context, id string) (*Record, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// HandleEvent updates the mirror from a webhook event, it is a paypalsdk.EventHandler.
+// Events of other resources are ignored
+func (s *Syncer) HandleEvent(ctx context.Context, e *paypalsdk.Event) error {
+	switch {
+	case strings.HasPrefix(e.EventType, "BILLING.SUBSCRIPTION."):
+		sub := e.Subscription()
+		if sub == nil || sub.ID == "" {
+			return nil
+		}
+		// 没有 update_time 时无法判断事件的先后, 以 PayPal 返回的为准
+		if sub.UpdateTime.IsZero() {
+			return s.Refresh(ctx, sub.ID)
+		}
+		_, err := s.store(ctx, sub)
+		return err
+	case strings.HasPrefix(e.EventType, "PAYMENT.SALE."):
+		// sale 事件不包含订阅, billing_agreement_id 为订阅 ID
+		sale := e.Sale()
+		if sale == nil || sale.BillingAgreementId == "" {
+			return nil
+		}
+		return s.Refresh(ctx, sale.BillingAgreementId)
+	}
+	return nil
+}
+
+// Refresh fetches a subscription from PayPal and stores it
+func (s *Syncer) Refresh(ctx context.Context, id string) error {
+	sub, err := s.api.ShowSubscriptionDetails(id)
+	if err != nil {
+		return err
+	}
+	_, err = s.store(ctx, sub)
+	return err
+}
+
+// store writes sub unless a newer state is stored, it reports whether sub was written
+func (s *Syncer) store(ctx context.Context, sub *paypalsdk.Subscription) (bool, error) {
+	rec := &Record{
+		Subscription: sub,
+		PaidThrough:  sub.PaidThrough(),
+		SyncedAt:     s.Now(),
+	}
+	old, err := s.repo.Get(ctx, sub.ID)
+	switch {
+	case err == ErrNotFound:
+	case err != nil:
+		return false, err
+	case rec.PaidThrough.IsZero():
+		// 取消、过期后 PayPal 不再返回 next_billing_time, 保留之前已付款到的时间
+		rec.PaidThrough = old.PaidThrough
+	}
+	return s.repo.Put(ctx, rec)
+}
+
+// Reconcile refreshes the subscriptions not synced for StaleAfter, except cancelled and expired ones synced
+// after they ended. Every error is given to OnError, the first one is returned after trying every subscription
+func (s *Syncer) Reconcile(ctx context.Context) error {
+	records, err := s.repo.List(ctx, s.Now().Add(-s.StaleAfter))
+	if err != nil {
+		if s.OnError != nil {
+			s.OnError("", err)
+		}
+		return err
+	}
+	var first error
+	for _, rec := range records {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if rec.Subscription.Status.IsTerminal() && !rec.SyncedAt.Before(rec.Subscription.StatusUpdateTime) {
+			continue
+		}
+		if err := s.Refresh(ctx, rec.ID()); err != nil {
+			if s.OnError != nil {
+				s.OnError(rec.ID(), err)
+			}
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// Run reconciles the mirror every interval until ctx is done
+func (s *Syncer) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.Reconcile(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package subsync
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	paypalsdk "github.com/YYRise/PayPal-GO-SDK"
+	"github.com/YYRise/PayPal-GO-SDK/paypalmock"
+)
+
+var t0 = time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+func subscriptionEvent(eventType string, sub *paypalsdk.Subscription) *paypalsdk.Event {
+	return &paypalsdk.Event{EventType: eventType, ResourceType: paypalsdk.E_EVENT_RESOURCE_TYPE_SUBCRIPTION, Resource: sub}
+}
+
+func subscription(status paypalsdk.E_SubscriptionStatus, updated time.Time, nextBilling time.Time) *paypalsdk.Subscription {
+	return &paypalsdk.Subscription{
+		ID:          "I-1",
+		Status:      status,
+		UpdateTime:  updated,
+		BillingInfo: &paypalsdk.BillingInfo{NextBillingTime: nextBilling},
+	}
+}
+
+func TestHandleEvent(t *testing.T) {
+	paid := t0.AddDate(0, 1, 0)
+	tests := []struct {
+		name        string
+		events      []*paypalsdk.Event
+		wantStatus  paypalsdk.E_SubscriptionStatus
+		paidThrough time.Time
+	}{
+		{
+			name: "in order",
+			events: []*paypalsdk.Event{
+				subscriptionEvent(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_ACTIVATED, subscription(paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, t0, paid)),
+				subscriptionEvent(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_SUSPENDED, subscription(paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED, t0.Add(time.Hour), paid)),
+			},
+			wantStatus:  paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED,
+			paidThrough: paid,
+		},
+		{
+			name: "out of order",
+			events: []*paypalsdk.Event{
+				subscriptionEvent(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_SUSPENDED, subscription(paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED, t0.Add(time.Hour), paid)),
+				subscriptionEvent(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_ACTIVATED, subscription(paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, t0, paid)),
+			},
+			wantStatus:  paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED,
+			paidThrough: paid,
+		},
+		{
+			name: "same update time",
+			events: []*paypalsdk.Event{
+				subscriptionEvent(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_ACTIVATED, subscription(paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, t0, paid)),
+				subscriptionEvent(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_SUSPENDED, subscription(paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED, t0, paid)),
+			},
+			wantStatus:  paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED,
+			paidThrough: paid,
+		},
+		{
+			name: "cancelled keeps paid through",
+			events: []*paypalsdk.Event{
+				subscriptionEvent(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_ACTIVATED, subscription(paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, t0, paid)),
+				subscriptionEvent(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_CANCELLED, subscription(paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED, t0.Add(time.Hour), time.Time{})),
+			},
+			wantStatus:  paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED,
+			paidThrough: paid,
+		},
+		{
+			name: "late activation after cancellation",
+			events: []*paypalsdk.Event{
+				subscriptionEvent(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_ACTIVATED, subscription(paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, t0, paid)),
+				subscriptionEvent(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_CANCELLED, subscription(paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED, t0.Add(2*time.Hour), time.Time{})),
+				subscriptionEvent(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_UPDATED, subscription(paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, t0.Add(time.Hour), paid)),
+			},
+			wantStatus:  paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED,
+			paidThrough: paid,
+		},
+		{
+			name: "cancelled first",
+			events: []*paypalsdk.Event{
+				subscriptionEvent(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_CANCELLED, subscription(paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED, t0, time.Time{})),
+			},
+			wantStatus: paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED,
+		},
+	}
+	for _, tt := range tests {
+		s := New(&paypalmock.SubscriptionsAPI{}, NewMemoryRepository())
+		for _, e := range tt.events {
+			if err := s.HandleEvent(context.Background(), e); err != nil {
+				t.Fatalf("%s: HandleEvent(%s) error = %v", tt.name, e.EventType, err)
+			}
+		}
+		rec, err := s.Get(context.Background(), "I-1")
+		if err != nil {
+			t.Fatalf("%s: Get error = %v", tt.name, err)
+		}
+		if rec.Subscription.Status != tt.wantStatus {
+			t.Errorf("%s: status = %s, want %s", tt.name, rec.Subscription.Status, tt.wantStatus)
+		}
+		if !rec.PaidThrough.Equal(tt.paidThrough) {
+			t.Errorf("%s: PaidThrough = %v, want %v", tt.name, rec.PaidThrough, tt.paidThrough)
+		}
+	}
+}
+
+func TestRecordIsEntitled(t *testing.T) {
+	paid := t0.AddDate(0, 1, 0)
+	rec := &Record{Subscription: subscription(paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED, t0, time.Time{}), PaidThrough: paid}
+	if !rec.IsEntitled(paid.Add(-time.Second)) {
+		t.Error("cancelled subscription not entitled before PaidThrough")
+	}
+	if rec.IsEntitled(paid) {
+		t.Error("cancelled subscription entitled at PaidThrough")
+	}
+	rec.Subscription.Status = paypalsdk.E_SUBSCRIPTION_STATUS_APPROVAL_PENDING
+	if rec.IsEntitled(t0) {
+		t.Error("APPROVAL_PENDING subscription entitled")
+	}
+}
+
+func TestHandleEventRefreshes(t *testing.T) {
+	api := &paypalmock.SubscriptionsAPI{
+		ShowSubscriptionDetailsFunc: func(subID string) (*paypalsdk.Subscription, error) {
+			return subscription(paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, t0, t0.AddDate(0, 1, 0)), nil
+		},
+	}
+	s := New(api, NewMemoryRepository())
+	events := []*paypalsdk.Event{
+		// 没有 update_time 时从 PayPal 获取
+		subscriptionEvent(paypalsdk.E_EVENT_TYPE_BILLING_SUBSCRIPTION_ACTIVATED, &paypalsdk.Subscription{ID: "I-1", Status: paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE}),
+		{EventType: paypalsdk.E_EVENT_TYPE_PAYMENT_SALE_COMPLETED, Resource: &paypalsdk.Sale{Id: "S-1", BillingAgreementId: "I-1"}},
+		// 其他事件被忽略
+		{EventType: paypalsdk.E_EVENT_TYPE_BILLING_PLAN_CREATED, Resource: map[string]interface{}{"id": "P-1"}},
+	}
+	for _, e := range events {
+		if err := s.HandleEvent(context.Background(), e); err != nil {
+			t.Fatalf("HandleEvent(%s) error = %v", e.EventType, err)
+		}
+	}
+	if n := len(api.CallsTo("ShowSubscriptionDetails")); n != 2 {
+		t.Errorf("ShowSubscriptionDetails called %d times, want 2", n)
+	}
+	if _, err := s.Get(context.Background(), "I-1"); err != nil {
+		t.Errorf("Get error = %v", err)
+	}
+}
+
+func TestMemoryRepositoryPut(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	put := func(status paypalsdk.E_SubscriptionStatus, updated time.Time) bool {
+		stored, err := repo.Put(ctx, &Record{Subscription: subscription(status, updated, time.Time{})})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stored
+	}
+	if !put(paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, t0) {
+		t.Error("Put of a new subscription not stored")
+	}
+	if put(paypalsdk.E_SUBSCRIPTION_STATUS_APPROVAL, t0.Add(-time.Second)) {
+		t.Error("Put of an older subscription stored")
+	}
+	if !put(paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED, t0) {
+		t.Error("Put with the same update time not stored")
+	}
+	if _, err := repo.Get(ctx, "I-2"); err != ErrNotFound {
+		t.Errorf("Get(I-2) error = %v, want ErrNotFound", err)
+	}
+}
+
+// failingRepository is a Repository whose List fails
+type failingRepository struct {
+	Repository
+	err error
+}
+
+func (r failingRepository) List(ctx context.Context, syncedBefore time.Time) ([]*Record, error) {
+	return nil, r.err
+}
+
+func TestReconcile(t *testing.T) {
+	errPayPal := errors.New("503 Service Unavailable")
+	tests := []struct {
+		id            string
+		status        paypalsdk.E_SubscriptionStatus
+		syncedAgo     time.Duration
+		statusUpdated time.Duration // 状态更新于多久之前
+		apiErr        error
+		wantRefresh   bool
+	}{
+		{"I-1", paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, 2 * time.Hour, 24 * time.Hour, nil, true},
+		{"I-2", paypalsdk.E_SUBSCRIPTION_STATUS_ACTIVE, 10 * time.Minute, 24 * time.Hour, nil, false},
+		{"I-3", paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED, 2 * time.Hour, 3 * time.Hour, nil, false},
+		// 取消后没有收到事件
+		{"I-4", paypalsdk.E_SUBSCRIPTION_STATUS_CANCELLED, 2 * time.Hour, time.Hour, nil, true},
+		{"I-5", paypalsdk.E_SUBSCRIPTION_STATUS_EXPIRED, 2 * time.Hour, 3 * time.Hour, nil, false},
+		{"I-6", paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED, 2 * time.Hour, 24 * time.Hour, errPayPal, true},
+		{"I-7", paypalsdk.E_SUBSCRIPTION_STATUS_APPROVAL_PENDING, 2 * time.Hour, 24 * time.Hour, nil, true},
+	}
+	now := t0.Add(48 * time.Hour)
+	refreshed := now.Add(-time.Minute)
+	api := &paypalmock.SubscriptionsAPI{
+		ShowSubscriptionDetailsFunc: func(subID string) (*paypalsdk.Subscription, error) {
+			for _, tt := range tests {
+				if tt.id == subID && tt.apiErr != nil {
+					return nil, tt.apiErr
+				}
+			}
+			sub := subscription(paypalsdk.E_SUBSCRIPTION_STATUS_SUSPENDED, refreshed, time.Time{})
+			sub.ID = subID
+			return sub, nil
+		},
+	}
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	for _, tt := range tests {
+		sub := subscription(tt.status, now.Add(-tt.statusUpdated), time.Time{})
+		sub.ID = tt.id
+		sub.StatusUpdateTime = now.Add(-tt.statusUpdated)
+		if _, err := repo.Put(ctx, &Record{Subscription: sub, SyncedAt: now.Add(-tt.syncedAgo)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := New(api, repo)
+	s.Now = func() time.Time { return now }
+	var errs []string
+	s.OnError = func(subscriptionID string, err error) {
+		errs = append(errs, subscriptionID+": "+err.Error())
+	}
+	if err := s.Reconcile(ctx); err != errPayPal {
+		t.Errorf("Reconcile error = %v, want %v", err, errPayPal)
+	}
+
+	calls := map[string]bool{}
+	for _, call := range api.CallsTo("ShowSubscriptionDetails") {
+		calls[call.Args[0].(string)] = true
+	}
+	for _, tt := range tests {
+		if calls[tt.id] != tt.wantRefresh {
+			t.Errorf("%s %s synced %v ago: refreshed = %v, want %v", tt.id, tt.status, tt.syncedAgo, calls[tt.id], tt.wantRefresh)
+		}
+		rec, err := repo.Get(ctx, tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if synced := tt.wantRefresh && tt.apiErr == nil; synced != rec.SyncedAt.Equal(now) {
+			t.Errorf("%s: SyncedAt = %v", tt.id, rec.SyncedAt)
+		}
+	}
+	if want := []string{"I-6: " + errPayPal.Error()}; strings.Join(errs, ",") != strings.Join(want, ",") {
+		t.Errorf("OnError calls = %q, want %q", errs, want)
+	}
+
+	// 仓库出错时 subscriptionID 为空
+	errs = nil
+	errDB := errors.New("database is locked")
+	s = New(api, failingRepository{repo, errDB})
+	s.OnError = func(subscriptionID string, err error) {
+		errs = append(errs, subscriptionID+": "+err.Error())
+	}
+	if err := s.Reconcile(ctx); err != errDB {
+		t.Errorf("Reconcile with a failing repository error = %v, want %v", err, errDB)
+	}
+	if want := ": " + errDB.Error(); len(errs) != 1 || errs[0] != want {
+		t.Errorf("OnError calls = %q, want %q", errs, want)
+	}
+}
@@ -0,0 +1,151 @@
+package paypalsdk
+
+import (
+	"fmt"
+	"net/url"
+)
+
+const (
+	K_TRACKER_API       = "/v1/shipping/trackers"
+	K_TRACKER_BATCH_API = "/v1/shipping/trackers-batch"
+)
+
+/*
+实物商品需要上传物流单号, 否则 PayPal 可能冻结资金。
+transaction_id 为 Sale.Id(订阅扣款) 或 Capture.ID(订单捕获), 一笔交易可以对应多个物流单号。
+*/
+
+// https://developer.paypal.com/docs/api/tracking/v1/#definition-tracker_status
+type E_TrackerStatus string
+
+const (
+	E_TRACKER_STATUS_SHIPPED      E_TrackerStatus = "SHIPPED"      // 已发货。
+	E_TRACKER_STATUS_ON_HOLD      E_TrackerStatus = "ON_HOLD"      // 暂停发货。
+	E_TRACKER_STATUS_DELIVERED    E_TrackerStatus = "DELIVERED"    // 已签收。
+	E_TRACKER_STATUS_CANCELLED    E_TrackerStatus = "CANCELLED"    // 已取消, 用于撤销错误的物流单号。
+	E_TRACKER_STATUS_LOCAL_PICKUP E_TrackerStatus = "LOCAL_PICKUP" // 自提, 无需 tracking_number。
+)
+
+// https://developer.paypal.com/docs/tracking/reference/carriers/
+// 只列出常用的物流商, 其他物流商直接使用 E_Carrier("XXX"), 不在列表中的使用 OTHER 并填写 carrier_name_other。
+type E_Carrier string
+
+const (
+	E_CARRIER_UPS            E_Carrier = "UPS"
+	E_CARRIER_USPS           E_Carrier = "USPS"
+	E_CARRIER_FEDEX          E_Carrier = "FEDEX"
+	E_CARRIER_DHL            E_Carrier = "DHL"
+	E_CARRIER_ROYAL_MAIL     E_Carrier = "ROYAL_MAIL"
+	E_CARRIER_CANADA_POST    E_Carrier = "CANADA_POST"
+	E_CARRIER_AUSTRALIA_POST E_Carrier = "AUSTRALIA_POST"
+	E_CARRIER_JAPAN_POST     E_Carrier = "JAPAN_POST"
+	E_CARRIER_CHINA_POST     E_Carrier = "CHINA_POST"
+	E_CARRIER_SF_EXPRESS     E_Carrier = "SF_EXPRESS"
+	E_CARRIER_YANWEN         E_Carrier = "YANWEN"
+	E_CARRIER_YUNEXPRESS     E_Carrier = "YUNEXPRESS"
+	E_CARRIER_OTHER          E_Carrier = "OTHER"
+)
+
+// https://developer.paypal.com/docs/api/tracking/v1/#definition-tracker
+type Tracker struct {
+	TransactionID      string             `json:"transaction_id"`
+	TrackingNumber     string             `json:"tracking_number,omitempty"`      // status 为 LOCAL_PICKUP 时可为空
+	TrackingNumberType string             `json:"tracking_number_type,omitempty"` // CARRIER_PROVIDED, E2E_PARTNER_PROVIDED
+	Status             E_TrackerStatus    `json:"status"`
+	ShipmentDate       string             `json:"shipment_date,omitempty"` // eg: 2020-03-31
+	Carrier            E_Carrier          `json:"carrier,omitempty"`
+	CarrierNameOther   string             `json:"carrier_name_other,omitempty"` // carrier 为 OTHER 时必填
+	NotifyBuyer        bool               `json:"notify_buyer,omitempty"`       // 是否邮件通知买家
+	LastUpdatedTime    string             `json:"last_updated_time,omitempty"`  // 只读
+	Links              []*LinkDescription `json:"links,omitempty"`
+}
+
+// ID 返回更新、查询 tracker 时使用的 ID: {transaction_id}-{tracking_number}
+func (t *Tracker) ID() string {
+	return TrackerID(t.TransactionID, t.TrackingNumber)
+}
+
+// TrackerID 返回更新、查询 tracker 时使用的 ID: {transaction_id}-{tracking_number}
+func TrackerID(transactionID, trackingNumber string) string {
+	if trackingNumber == "" {
+		return transactionID
+	}
+	return fmt.Sprintf("%s-%s", transactionID, trackingNumber)
+}
+
+// https://developer.paypal.com/docs/api/tracking/v1/#definition-tracker_identifier
+type TrackerIdentifier struct {
+	TransactionID  string             `json:"transaction_id"`
+	TrackingNumber string             `json:"tracking_number,omitempty"`
+	Links          []*LinkDescription `json:"links,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/tracking/v1/#definition-error
+type TrackerError struct {
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message,omitempty"`
+	DebugID string `json:"debug_id,omitempty"`
+	Details []*struct {
+		Field       string `json:"field,omitempty"`
+		Value       string `json:"value,omitempty"`
+		Location    string `json:"location,omitempty"`
+		Issue       string `json:"issue,omitempty"`
+		Description string `json:"description,omitempty"`
+	} `json:"details,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/tracking/v1/#trackers-batch_post
+type AddTrackersRsp struct {
+	TrackerIdentifiers []*TrackerIdentifier `json:"tracker_identifiers,omitempty"` // 添加成功的 tracker
+	Errors             []*TrackerError      `json:"errors,omitempty"`              // 添加失败的 tracker, 整个请求仍返回 200
+	Links              []*LinkDescription   `json:"links,omitempty"`
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/shipping/trackers-batch
+// Add tracking information for multiple PayPal transactions
+// 批量添加物流信息, 最多 20 个。部分失败时仍返回 200, 失败原因见 Errors。
+*/
+
+func (c *Client) AddTrackers(trackers []*Tracker) (*AddTrackersRsp, error) {
+	q := map[string][]*Tracker{"trackers": trackers}
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, K_TRACKER_BATCH_API), q)
+	rsp := &AddTrackersRsp{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// PUT https://api.sandbox.paypal.com/v1/shipping/trackers/8MC585209K746392H-443844607820
+// returns 204 No Content
+// Update or cancel tracking information for PayPal transaction
+// 更新物流信息, 撤销错误的物流单号时将 status 设为 CANCELLED。
+*/
+
+func (c *Client) UpdateTracker(t *Tracker) error {
+	req, err := c.NewRequest("PUT", fmt.Sprintf("%s%s/%s", c.APIBase, K_TRACKER_API, url.PathEscape(t.ID())), t)
+	if err != nil {
+		return err
+	}
+	err = c.SendWithAuth(req, nil)
+	return err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/shipping/trackers/8MC585209K746392H-443844607820
+// Show tracking information
+// 查询物流信息
+*/
+
+func (c *Client) ShowTracker(transactionID, trackingNumber string) (*Tracker, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.APIBase, K_TRACKER_API, url.PathEscape(TrackerID(transactionID, trackingNumber))), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &Tracker{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
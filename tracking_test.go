@@ -0,0 +1,44 @@
+package paypalsdk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrackerPath(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		if r.Method == "GET" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	c, err := NewClient("id", "secret", WithAPIBase(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetAccessToken("token")
+
+	if _, err := c.ShowTracker("8MC585209K746392H", "1Z/99 #1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UpdateTracker(&Tracker{TransactionID: "8MC585209K746392H", TrackingNumber: "a?b"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"/v1/shipping/trackers/8MC585209K746392H-1Z%2F99%20%231",
+		"/v1/shipping/trackers/8MC585209K746392H-a%3Fb",
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("path %d = %s, want %s", i, paths[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package paypalsdk
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+/*
+请求参数校验：
+PayPal 对字段的长度、取值范围有限制, 不满足时返回 422, 且一次只报告部分错误。
+Validate 在本地按文档的限制检查请求, 一次返回所有不合法的字段, 字段用 JSON Pointer 表示(与 PayPal 返回的 details.field 一致),
+如 /application_context/brand_name。
+Client.SetValidateRequests(true) 后, NewRequest 会在发送前校验实现了 Validator 的请求体。
+*/
+
+// Validator 由可以在本地校验的请求实现
+type Validator interface {
+	Validate() error
+}
+
+// FieldViolation 描述一个不合法的字段
+type FieldViolation struct {
+	Field string `json:"field"` // JSON Pointer, eg: /subscriber/shipping_address/address/country_code
+	Issue string `json:"issue"`
+}
+
+// ValidationError 包含请求中所有不合法的字段
+type ValidationError struct {
+	Violations []*FieldViolation `json:"violations"`
+}
+
+func (e *ValidationError) Error() string {
+	issues := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		issues = append(issues, fmt.Sprintf("%s %s", v.Field, v.Issue))
+	}
+	return fmt.Sprintf("paypalsdk: invalid request: %s", strings.Join(issues, "; "))
+}
+
+// validator 收集校验过程中发现的不合法字段
+type validator struct {
+	violations []*FieldViolation
+}
+
+func (v *validator) add(field, format string, args ...interface{}) {
+	if field == "" {
+		field = "/"
+	}
+	v.violations = append(v.violations, &FieldViolation{Field: field, Issue: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v.violations}
+}
+
+func (v *validator) required(field, s string) bool {
+	if s == "" {
+		v.add(field, "is required")
+		return false
+	}
+	return true
+}
+
+// length 校验字符串长度, s 为空时不校验, 必填字段先用 required 校验
+func (v *validator) length(field, s string, min, max int) {
+	if s == "" {
+		return
+	}
+	if n := utf8.RuneCountInString(s); n < min || n > max {
+		v.add(field, "length must be between %d and %d, got %d", min, max, n)
+	}
+}
+
+func (v *validator) between(field string, n, min, max int) {
+	if n < min || n > max {
+		v.add(field, "must be between %d and %d, got %d", min, max, n)
+	}
+}
+
+func (v *validator) digits(field, s string, min, max int) {
+	if s == "" {
+		return
+	}
+	v.length(field, s, min, max)
+	for _, ch := range s {
+		if ch < '0' || ch > '9' {
+			v.add(field, "must contain only digits")
+			return
+		}
+	}
+}
+
+func (v *validator) oneOf(field, s string, values ...string) {
+	if s == "" {
+		return
+	}
+	for _, value := range values {
+		if s == value {
+			return
+		}
+	}
+	v.add(field, "must be one of %s, got %q", strings.Join(values, ", "), s)
+}
+
+func (v *validator) url(field, s string) {
+	if s == "" {
+		return
+	}
+	v.length(field, s, 10, 4000)
+	if u, err := url.Parse(s); err != nil || u.Scheme == "" || u.Host == "" {
+		v.add(field, "must be an absolute URL")
+	}
+}
+
+func (v *validator) dateTime(field, s string) {
+	if s == "" {
+		return
+	}
+	if _, err := time.Parse(time.RFC3339, s); err != nil {
+		v.add(field, "must be an RFC 3339 date-time, eg: 2020-03-09T12:00:01Z")
+	}
+}
+
+func (v *validator) money(field string, m *Money) {
+	if m == nil {
+		return
+	}
+	if v.required(field+"/currency_code", m.CurrencyCode) && len(m.CurrencyCode) != 3 {
+		v.add(field+"/currency_code", "must be a 3 letter ISO-4217 code")
+	}
+	if v.required(field+"/value", m.Value) {
+		v.length(field+"/value", m.Value, 1, 32)
+		if _, err := m.MinorUnits(); err != nil {
+			v.add(field+"/value", "must be a number with at most %d decimal places", CurrencyExponent(m.CurrencyCode))
+		}
+	}
+}
+
+func (q *CreateSubscriptionReq) Validate() error {
+	v := &validator{}
+	if q == nil {
+		v.add("", "is required")
+		return v.err()
+	}
+	if v.required("/plan_id", q.PlanID) {
+		v.length("/plan_id", q.PlanID, 3, 50)
+	}
+	v.dateTime("/start_time", q.StartTime)
+	v.digits("/quantity", q.Quantity, 1, 32)
+	v.money("/shipping_amount", q.ShippingAmount)
+	if q.Subscriber != nil {
+		q.Subscriber.validate(v, "/subscriber")
+	}
+	if q.ApplicationContext != nil {
+		q.ApplicationContext.validate(v, "/application_context")
+	}
+	return v.err()
+}
+
+func (s *Subscriber) validate(v *validator, path string) {
+	if s.Name != nil {
+		v.length(path+"/name/given_name", s.Name.GivenName, 1, 140)
+		v.length(path+"/name/surname", s.Name.Surname, 1, 140)
+	}
+	v.length(path+"/email_address", s.EmailAddress, 3, 254)
+	if s.EmailAddress != "" && !strings.Contains(s.EmailAddress, "@") {
+		v.add(path+"/email_address", "must be an email address")
+	}
+	if s.ShippingAddress != nil {
+		if s.ShippingAddress.Name != nil {
+			v.length(path+"/shipping_address/name/full_name", s.ShippingAddress.Name.FullName, 1, 300)
+		}
+		if a := s.ShippingAddress.Address; a != nil {
+			p := path + "/shipping_address/address"
+			v.length(p+"/address_line_1", a.AddressLine_1, 1, 300)
+			v.length(p+"/address_line_2", a.AddressLine_2, 1, 300)
+			v.length(p+"/admin_area_2", a.AdminArea_2, 1, 120)
+			v.length(p+"/admin_area_1", a.AdminArea_1, 1, 300)
+			v.length(p+"/postal_code", a.PostalCode, 1, 60)
+			if v.required(p+"/country_code", a.CountryCode) && len(a.CountryCode) != 2 {
+				v.add(p+"/country_code", "must be a 2 letter ISO-3166-1 code")
+			}
+		}
+	}
+}
+
+func (a *ApplicationContext) validate(v *validator, path string) {
+	v.length(path+"/brand_name", a.BrandName, 1, 127)
+	v.length(path+"/locale", a.Locale, 2, 10)
+	v.oneOf(path+"/shipping_preference", string(a.ShippingPreference),
+		string(E_SHIPPING_PREFERENCE_GET_FROM_FILE), E_SHIPPING_PREFERENCE_NO_SHIPPING, E_SHIPPING_PREFERENCE_SET_PROVIDED_ADDRESS)
+	v.oneOf(path+"/user_action", string(a.UserAction), string(E_USER_ACTION_CONTINUE), string(E_USER_ACTION_SUBSCRIBE_NOW))
+	v.oneOf(path+"/payment_method/payee_preferred", string(a.PaymentMethod.PayeePreferred),
+		string(E_PAYEE_PREFERRED_UNRESTRICTED), string(E_PAYEE_PREFERRED_IMMEDIATE_PAYMENT_REQUIRED))
+	if v.required(path+"/return_url", a.ReturnUrl) {
+		v.url(path+"/return_url", a.ReturnUrl)
+	}
+	if v.required(path+"/cancel_url", a.CancelUrl) {
+		v.url(path+"/cancel_url", a.CancelUrl)
+	}
+}
+
+func (q *UpdateSubscriptionReq) Validate() error {
+	v := &validator{}
+	if q == nil {
+		v.add("", "is required")
+		return v.err()
+	}
+	if v.required("/reason", q.Reason) {
+		v.length("/reason", q.Reason, 1, 128)
+	}
+	return v.err()
+}
+
+func (q *CreateWebhookReq) Validate() error {
+	v := &validator{}
+	if q == nil {
+		v.add("", "is required")
+		return v.err()
+	}
+	if v.required("/url", q.Url) {
+		v.length("/url", q.Url, 1, 2048)
+		if u, err := url.Parse(q.Url); err != nil || u.Scheme != "https" || u.Host == "" {
+			v.add("/url", "must be an absolute https URL")
+		}
+	}
+	if len(q.EventTypes) == 0 {
+		v.add("/event_types", "is required")
+	}
+	for i, et := range q.EventTypes {
+		p := fmt.Sprintf("/event_types/%d", i)
+		if et == nil {
+			v.add(p, "is required")
+			continue
+		}
+		v.required(p+"/name", et.Name)
+	}
+	return v.err()
+}
+
+// 以下为创建、更新 plan 时使用的结构
+
+func (b *BillingCycle) Validate() error {
+	v := &validator{}
+	if b == nil {
+		v.add("", "is required")
+		return v.err()
+	}
+	b.validate(v, "")
+	return v.err()
+}
+
+func (b *BillingCycle) validate(v *validator, path string) {
+	if b.TenureType == "" {
+		v.add(path+"/tenure_type", "is required")
+	}
+	v.oneOf(path+"/tenure_type", string(b.TenureType), string(E_TENURE_TYPE_REGULAR), E_TENURE_TYPE_TRIAL)
+	v.between(path+"/sequence", b.Sequence, 1, 99)
+	v.between(path+"/total_cycles", b.TotalCycles, 0, 999)
+	if b.Frequency == nil {
+		v.add(path+"/frequency", "is required")
+	} else {
+		b.Frequency.validate(v, path+"/frequency")
+	}
+	// 免费试用周期可以不设置 pricing_scheme
+	if b.PricingScheme == nil {
+		if b.TenureType == E_TENURE_TYPE_REGULAR {
+			v.add(path+"/pricing_scheme", "is required")
+		}
+	} else {
+		b.PricingScheme.validate(v, path+"/pricing_scheme")
+	}
+}
+
+// 不同计费单位下 interval_count 的最大值
+var maxFrequencyIntervalCount = map[E_FrequencyInterval]int{
+	E_FREQUENCY_INTERVAL_DAY:   365,
+	E_FREQUENCY_INTERVAL_WEEK:  52,
+	E_FREQUENCY_INTERVAL_MONTH: 12,
+	E_FREQUENCY_INTERVAL_YEAR:  1,
+}
+
+func (f *Frequency) validate(v *validator, path string) {
+	max, ok := maxFrequencyIntervalCount[f.IntervalUnit]
+	if !ok {
+		v.add(path+"/interval_unit", "must be one of DAY, WEEK, MONTH, YEAR, got %q", f.IntervalUnit)
+		return
+	}
+	// interval_count 为 0 时使用默认值 1
+	if f.IntervalCount != 0 {
+		v.between(path+"/interval_count", f.IntervalCount, 1, max)
+	}
+}
+
+func (p *PricingScheme) Validate() error {
+	v := &validator{}
+	if p == nil {
+		v.add("", "is required")
+		return v.err()
+	}
+	p.validate(v, "")
+	return v.err()
+}
+
+func (p *PricingScheme) validate(v *validator, path string) {
+	v.between(path+"/version", p.Version, 0, 999)
+	if p.FixedPrice == nil {
+		v.add(path+"/fixed_price", "is required")
+	}
+	v.money(path+"/fixed_price", p.FixedPrice)
+}
+
+func (p *PaymentPreferences) Validate() error {
+	v := &validator{}
+	if p == nil {
+		v.add("", "is required")
+		return v.err()
+	}
+	p.validate(v, "")
+	return v.err()
+}
+
+func (p *PaymentPreferences) validate(v *validator, path string) {
+	v.money(path+"/setup_fee", p.SetupFee)
+	v.oneOf(path+"/setup_fee_failure_action", p.SetupFeeFailureAction, "CONTINUE", "CANCEL")
+	v.between(path+"/payment_failure_threshold", p.PaymentFailureThreshold, 0, 999)
+}
+
+func (t *Taxes) Validate() error {
+	v := &validator{}
+	if t == nil {
+		v.add("", "is required")
+		return v.err()
+	}
+	t.validate(v, "")
+	return v.err()
+}
+
+func (t *Taxes) validate(v *validator, path string) {
+	if v.required(path+"/percentage", t.Percentage) {
+		if _, err := parseMinorUnits(t.Percentage, 2); err != nil {
+			v.add(path+"/percentage", "must be a number with at most 2 decimal places")
+		}
+	}
+}
+
+func (p *Plan) Validate() error {
+	v := &validator{}
+	if p == nil {
+		v.add("", "is required")
+		return v.err()
+	}
+	if v.required("/product_id", p.ProductID) {
+		v.length("/product_id", p.ProductID, 6, 50)
+	}
+	if v.required("/name", p.Name) {
+		v.length("/name", p.Name, 1, 127)
+	}
+	v.length("/description", p.Description, 1, 127)
+	v.oneOf("/status", string(p.Status), string(E_PLAN_STATUS_CREATED), string(E_PLAN_STATUS_ACTIVE))
+	if len(p.BillingCycles) == 0 {
+		v.add("/billing_cycles", "is required")
+	} else if len(p.BillingCycles) > 12 {
+		v.add("/billing_cycles", "must have at most 12 items, got %d", len(p.BillingCycles))
+	}
+	for i, b := range p.BillingCycles {
+		path := fmt.Sprintf("/billing_cycles/%d", i)
+		if b == nil {
+			v.add(path, "is required")
+			continue
+		}
+		b.validate(v, path)
+	}
+	if p.PaymentPreferences != nil {
+		p.PaymentPreferences.validate(v, "/payment_preferences")
+	}
+	if p.Taxes != nil {
+		p.Taxes.validate(v, "/taxes")
+	}
+	return v.err()
+}
+
+func (p *Product) Validate() error {
+	v := &validator{}
+	if p == nil {
+		v.add("", "is required")
+		return v.err()
+	}
+	v.length("/id", p.ID, 6, 50)
+	if v.required("/name", p.Name) {
+		v.length("/name", p.Name, 1, 127)
+	}
+	v.length("/description", p.Description, 1, 256)
+	v.oneOf("/type", string(p.Type), string(E_PRODUCT_TYPE_PHYSICAL), string(E_PRODUCT_TYPE_DIGITAL), string(E_PRODUCT_TYPE_SERVICE))
+	v.length("/category", p.Category, 4, 256)
+	v.url("/image_url", p.ImageURL)
+	v.url("/home_url", p.HomeURL)
+	return v.err()
+}
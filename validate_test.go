@@ -0,0 +1,167 @@
+package paypalsdk
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// violationFields returns the sorted JSON pointers of the violations of err
+func violationFields(t *testing.T, err error) []string {
+	if err == nil {
+		return nil
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("error %v is not a *ValidationError", err)
+	}
+	var fields []string
+	for _, v := range verr.Violations {
+		fields = append(fields, v.Field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func TestValidate(t *testing.T) {
+	context := func() *ApplicationContext {
+		return &ApplicationContext{ReturnUrl: "https://example.com/return", CancelUrl: "https://example.com/cancel"}
+	}
+	regular := func() *BillingCycle {
+		return &BillingCycle{
+			TenureType:    E_TENURE_TYPE_REGULAR,
+			Sequence:      1,
+			Frequency:     &Frequency{IntervalUnit: E_FREQUENCY_INTERVAL_MONTH},
+			PricingScheme: &PricingScheme{FixedPrice: &Money{CurrencyCode: "USD", Value: "9.99"}},
+		}
+	}
+
+	tests := []struct {
+		name string
+		req  Validator
+		want []string
+	}{
+		{"subscription", &CreateSubscriptionReq{PlanID: "P-123", Quantity: "2", ApplicationContext: context()}, nil},
+		{"nil subscription", (*CreateSubscriptionReq)(nil), []string{"/"}},
+		{"subscription without plan", &CreateSubscriptionReq{}, []string{"/plan_id"}},
+		{"subscription fields", &CreateSubscriptionReq{
+			PlanID:         "P",
+			StartTime:      "tomorrow",
+			Quantity:       "1.5",
+			ShippingAmount: &Money{CurrencyCode: "US", Value: "1.001"},
+		}, []string{"/plan_id", "/quantity", "/shipping_amount/currency_code", "/shipping_amount/value", "/start_time"}},
+		{"subscriber", &CreateSubscriptionReq{PlanID: "P-123", Subscriber: &Subscriber{
+			Name:         &Name{GivenName: strings.Repeat("a", 141)},
+			EmailAddress: "nobody",
+			ShippingAddress: &ShippingDetail{
+				Name:    &ShippingDetailName{},
+				Address: &ShippingDetailAddressPortable{CountryCode: "USA"},
+			},
+		}}, []string{"/subscriber/email_address", "/subscriber/name/given_name", "/subscriber/shipping_address/address/country_code"}},
+		{"missing country code", &CreateSubscriptionReq{PlanID: "P-123", Subscriber: &Subscriber{
+			ShippingAddress: &ShippingDetail{Address: &ShippingDetailAddressPortable{PostalCode: "95131"}},
+		}}, []string{"/subscriber/shipping_address/address/country_code"}},
+		{"application context", &CreateSubscriptionReq{PlanID: "P-123", ApplicationContext: &ApplicationContext{
+			BrandName:          strings.Repeat("品", 128),
+			Locale:             "z",
+			ShippingPreference: "SHIP",
+			UserAction:         "BUY",
+			PaymentMethod:      PaymentMethod{PayeePreferred: "ANY"},
+			ReturnUrl:          "relative/return",
+		}}, []string{
+			"/application_context/brand_name",
+			"/application_context/cancel_url",
+			"/application_context/locale",
+			"/application_context/payment_method/payee_preferred",
+			"/application_context/return_url",
+			"/application_context/shipping_preference",
+			"/application_context/user_action",
+		}},
+		{"brand name of 127 characters", &CreateSubscriptionReq{PlanID: "P-123", ApplicationContext: &ApplicationContext{
+			BrandName: strings.Repeat("品", 127), ReturnUrl: "https://example.com/return", CancelUrl: "https://example.com/cancel",
+		}}, nil},
+
+		{"reason", &UpdateSubscriptionReq{Reason: "Customer request"}, nil},
+		{"no reason", &UpdateSubscriptionReq{}, []string{"/reason"}},
+		{"long reason", &UpdateSubscriptionReq{Reason: strings.Repeat("r", 129)}, []string{"/reason"}},
+
+		{"webhook", &CreateWebhookReq{Url: "https://example.com/hook", EventTypes: []*EventType{{Name: "*"}}}, nil},
+		{"webhook over http", &CreateWebhookReq{Url: "http://example.com/hook", EventTypes: []*EventType{{Name: "*"}}}, []string{"/url"}},
+		{"webhook event types", &CreateWebhookReq{Url: "https://example.com/hook", EventTypes: []*EventType{{Name: "*"}, nil, {}}},
+			[]string{"/event_types/1", "/event_types/2/name"}},
+		{"webhook without event types", &CreateWebhookReq{Url: "https://example.com/hook"}, []string{"/event_types"}},
+
+		{"plan", &Plan{ProductID: "PROD-123", Name: "Monthly", BillingCycles: []*BillingCycle{regular()}}, nil},
+		{"plan without billing cycles", &Plan{ProductID: "PROD-123", Name: "Monthly"}, []string{"/billing_cycles"}},
+		{"plan fields", &Plan{ProductID: "P", Status: E_PLAN_STATUS_INACTIVE, BillingCycles: make([]*BillingCycle, 13)},
+			append([]string{"/billing_cycles", "/name", "/product_id", "/status"}, nilCycles(13)...)},
+		{"billing cycle", &Plan{ProductID: "PROD-123", Name: "Monthly", BillingCycles: []*BillingCycle{
+			{TenureType: E_TENURE_TYPE_TRIAL, Sequence: 1, TotalCycles: 1, Frequency: &Frequency{IntervalUnit: E_FREQUENCY_INTERVAL_WEEK}},
+			{TenureType: "FOREVER", Sequence: 100, TotalCycles: 1000, Frequency: &Frequency{IntervalUnit: E_FREQUENCY_INTERVAL_MONTH, IntervalCount: 13}},
+			{TenureType: E_TENURE_TYPE_REGULAR, Sequence: 3, Frequency: &Frequency{IntervalUnit: "HOUR"}},
+			{TenureType: E_TENURE_TYPE_REGULAR, Sequence: 4, PricingScheme: &PricingScheme{Version: 1000}},
+		}}, []string{
+			"/billing_cycles/1/frequency/interval_count",
+			"/billing_cycles/1/sequence",
+			"/billing_cycles/1/tenure_type",
+			"/billing_cycles/1/total_cycles",
+			"/billing_cycles/2/frequency/interval_unit",
+			"/billing_cycles/2/pricing_scheme",
+			"/billing_cycles/3/frequency",
+			"/billing_cycles/3/pricing_scheme/fixed_price",
+			"/billing_cycles/3/pricing_scheme/version",
+		}},
+		{"payment preferences and taxes", &Plan{
+			ProductID:          "PROD-123",
+			Name:               "Monthly",
+			BillingCycles:      []*BillingCycle{regular()},
+			PaymentPreferences: &PaymentPreferences{SetupFeeFailureAction: "RETRY", PaymentFailureThreshold: 1000},
+			Taxes:              &Taxes{Percentage: "10.125"},
+		}, []string{"/payment_preferences/payment_failure_threshold", "/payment_preferences/setup_fee_failure_action", "/taxes/percentage"}},
+
+		{"product", &Product{Name: "Video streaming", Type: E_PRODUCT_TYPE_SERVICE, HomeURL: "https://example.com"}, nil},
+		{"product fields", &Product{ID: "P", Type: "FOOD", ImageURL: "example.com/image.png", Category: "ART"},
+			[]string{"/category", "/id", "/image_url", "/name", "/type"}},
+	}
+	for _, tt := range tests {
+		got := violationFields(t, tt.req.Validate())
+		sort.Strings(tt.want)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: violations = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// nilCycles returns the pointers of n nil billing cycles
+func nilCycles(n int) []string {
+	var fields []string
+	for i := 0; i < n; i++ {
+		fields = append(fields, "/billing_cycles/"+strconv.Itoa(i))
+	}
+	return fields
+}
+
+func TestValidateRequests(t *testing.T) {
+	c, err := NewClient("id", "secret", WithSandbox())
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalid := &UpdateSubscriptionReq{}
+	if _, err := c.NewRequest("POST", c.APIBase+"/v1/billing/subscriptions/I-1/cancel", invalid); err != nil {
+		t.Errorf("NewRequest without validation error = %v", err)
+	}
+	c.SetValidateRequests(true)
+	_, err = c.NewRequest("POST", c.APIBase+"/v1/billing/subscriptions/I-1/cancel", invalid)
+	if got := violationFields(t, err); !reflect.DeepEqual(got, []string{"/reason"}) {
+		t.Errorf("NewRequest violations = %v, want [/reason]", got)
+	}
+	if !strings.Contains(err.Error(), "/reason is required") {
+		t.Errorf("error message %q does not name the field", err)
+	}
+	if _, err := c.NewRequest("POST", c.APIBase+"/v1/billing/subscriptions/I-1/cancel", &UpdateSubscriptionReq{Reason: "ok"}); err != nil {
+		t.Errorf("NewRequest of a valid payload error = %v", err)
+	}
+}
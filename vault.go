@@ -0,0 +1,283 @@
+package paypalsdk
+
+import (
+	"fmt"
+	"net/url"
+)
+
+const (
+	K_VAULT_SETUP_TOKEN_API   = "/v3/vault/setup-tokens"
+	K_VAULT_PAYMENT_TOKEN_API = "/v3/vault/payment-tokens"
+)
+
+/*
+保存付款方式(无需购买)流程：
+1. CreateSetupToken 创建 setup token, PayPal 钱包需要将付款人重定向到 rel=approve 链接进行授权。
+2. 付款人授权后(status=APPROVED), CreatePaymentToken 用 setup token 换取长期有效的 payment token。
+3. 之后下单时, 将 PaymentToken.OrderPaymentSource() 作为订单的 payment_source, 付款人无需再次授权。
+
+setup token 没有删除接口, 未使用的 setup token 3 天后自动过期。
+*/
+
+// https://developer.paypal.com/docs/api/payment-tokens/v3/#setup-tokens_create
+type E_SetupTokenStatus string
+
+const (
+	E_SETUP_TOKEN_STATUS_CREATED               E_SetupTokenStatus = "CREATED"
+	E_SETUP_TOKEN_STATUS_PAYER_ACTION_REQUIRED E_SetupTokenStatus = "PAYER_ACTION_REQUIRED" // 需要付款人通过 rel=approve 链接授权。
+	E_SETUP_TOKEN_STATUS_APPROVED              E_SetupTokenStatus = "APPROVED"              // 付款人已授权, 可以创建 payment token。
+	E_SETUP_TOKEN_STATUS_VAULTED               E_SetupTokenStatus = "VAULTED"               // 已创建 payment token。
+	E_SETUP_TOKEN_STATUS_TOKENIZED             E_SetupTokenStatus = "TOKENIZED"
+)
+
+// https://developer.paypal.com/docs/api/payment-tokens/v3/#definition-paypal_wallet_experience_context
+type VaultExperienceContext struct {
+	BrandName          string               `json:"brand_name,omitempty"`
+	Locale             string               `json:"locale,omitempty"`
+	ShippingPreference E_ShippingPreference `json:"shipping_preference,omitempty"`
+	ReturnUrl          string               `json:"return_url,omitempty"`
+	CancelUrl          string               `json:"cancel_url,omitempty"`
+	VaultInstruction   string               `json:"vault_instruction,omitempty"` // ON_CREATE_PAYMENT_TOKENS, ON_PAYER_APPROVAL
+}
+
+// https://developer.paypal.com/docs/api/payment-tokens/v3/#definition-paypal_wallet_base
+type VaultPaypalSource struct {
+	Description                 string                  `json:"description,omitempty"`   // 显示给付款人的授权说明
+	UsagePattern                string                  `json:"usage_pattern,omitempty"` // IMMEDIATE, DEFERRED, RECURRING_PREPAID, RECURRING_POSTPAID, THRESHOLD_PREPAID, THRESHOLD_POSTPAID
+	UsageType                   string                  `json:"usage_type,omitempty"`    // MERCHANT, PLATFORM
+	CustomerType                string                  `json:"customer_type,omitempty"` // CONSUMER, BUSINESS
+	PermitMultiplePaymentTokens bool                    `json:"permit_multiple_payment_tokens,omitempty"`
+	EmailAddress                string                  `json:"email_address,omitempty"` // 只读
+	PayerID                     string                  `json:"payer_id,omitempty"`      // 只读
+	Name                        *Name                   `json:"name,omitempty"`          // 只读
+	ExperienceContext           *VaultExperienceContext `json:"experience_context,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payment-tokens/v3/#definition-card_request
+type VaultCardSource struct {
+	Name               string                         `json:"name,omitempty"`
+	Number             string                         `json:"number,omitempty"`        // 只写
+	Expiry             string                         `json:"expiry,omitempty"`        // eg: 2027-02
+	SecurityCode       string                         `json:"security_code,omitempty"` // 只写
+	Brand              string                         `json:"brand,omitempty"`         // 只读, eg: VISA
+	LastDigits         string                         `json:"last_digits,omitempty"`   // 只读
+	BillingAddress     *ShippingDetailAddressPortable `json:"billing_address,omitempty"`
+	VerificationMethod string                         `json:"verification_method,omitempty"` // SCA_WHEN_REQUIRED, SCA_ALWAYS
+	ExperienceContext  *VaultExperienceContext        `json:"experience_context,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payment-tokens/v3/#definition-token_id_request
+type VaultTokenSource struct {
+	ID   string `json:"id"`
+	Type string `json:"type"` // SETUP_TOKEN
+}
+
+// https://developer.paypal.com/docs/api/payment-tokens/v3/#definition-payment_source
+type VaultPaymentSource struct {
+	Card   *VaultCardSource   `json:"card,omitempty"`
+	Paypal *VaultPaypalSource `json:"paypal,omitempty"`
+	Token  *VaultTokenSource  `json:"token,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payment-tokens/v3/#definition-customer
+type VaultCustomer struct {
+	ID                 string `json:"id,omitempty"` // 商户侧的客户 ID, 不传则由 PayPal 生成。
+	MerchantCustomerID string `json:"merchant_customer_id,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payment-tokens/v3/#setup-tokens_create
+type CreateSetupTokenReq struct {
+	Customer      *VaultCustomer      `json:"customer,omitempty"`
+	PaymentSource *VaultPaymentSource `json:"payment_source"`
+}
+
+// https://developer.paypal.com/docs/api/payment-tokens/v3/#definition-setup_token_response
+type SetupToken struct {
+	ID            string              `json:"id,omitempty"`
+	Customer      *VaultCustomer      `json:"customer,omitempty"`
+	Status        E_SetupTokenStatus  `json:"status,omitempty"`
+	PaymentSource *VaultPaymentSource `json:"payment_source,omitempty"`
+	Links         []*LinkDescription  `json:"links,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payment-tokens/v3/#payment-tokens_create
+type CreatePaymentTokenReq struct {
+	Customer      *VaultCustomer      `json:"customer,omitempty"`
+	PaymentSource *VaultPaymentSource `json:"payment_source"`
+}
+
+// https://developer.paypal.com/docs/api/payment-tokens/v3/#definition-payment_token_response
+type PaymentToken struct {
+	ID            string              `json:"id,omitempty"`
+	Customer      *VaultCustomer      `json:"customer,omitempty"`
+	PaymentSource *VaultPaymentSource `json:"payment_source,omitempty"`
+	Links         []*LinkDescription  `json:"links,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/payment-tokens/v3/#definition-customer_vault_payment_tokens_response
+type PaymentTokenList struct {
+	Customer      *VaultCustomer     `json:"customer,omitempty"`
+	PaymentTokens []*PaymentToken    `json:"payment_tokens,omitempty"`
+	TotalItems    int                `json:"total_items,omitempty"`
+	TotalPages    int                `json:"total_pages,omitempty"`
+	Links         []*LinkDescription `json:"links,omitempty"`
+}
+
+// https://developer.paypal.com/docs/api/orders/v2/#definition-vault_id
+type VaultIDSource struct {
+	VaultID string `json:"vault_id"`
+}
+
+// 订单(Orders v2)的 payment_source, 使用已保存的 payment token 付款。
+// https://developer.paypal.com/docs/api/orders/v2/#definition-payment_source
+type OrderVaultPaymentSource struct {
+	Card   *VaultIDSource `json:"card,omitempty"`
+	Paypal *VaultIDSource `json:"paypal,omitempty"`
+}
+
+// 下单的同时保存付款方式, 放在订单 payment_source.paypal.attributes 或 payment_source.card.attributes 中。
+// https://developer.paypal.com/docs/api/orders/v2/#definition-vault_paypal_wallet_base
+type OrderVaultAttributes struct {
+	Vault    *OrderVaultInstruction `json:"vault,omitempty"`
+	Customer *VaultCustomer         `json:"customer,omitempty"`
+}
+
+type OrderVaultInstruction struct {
+	StoreInVault string `json:"store_in_vault"`          // ON_SUCCESS
+	UsageType    string `json:"usage_type,omitempty"`    // MERCHANT, PLATFORM
+	CustomerType string `json:"customer_type,omitempty"` // CONSUMER, BUSINESS
+}
+
+// OrderPaymentSource 返回订单中使用该 payment token 付款的 payment_source。
+func (t *PaymentToken) OrderPaymentSource() *OrderVaultPaymentSource {
+	source := &OrderVaultPaymentSource{}
+	if t.PaymentSource != nil && t.PaymentSource.Card != nil {
+		source.Card = &VaultIDSource{VaultID: t.ID}
+	} else {
+		source.Paypal = &VaultIDSource{VaultID: t.ID}
+	}
+	return source
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v3/vault/setup-tokens
+// Create a setup token
+// 创建 setup token, PayPal 钱包需要付款人通过 rel=approve 链接授权。requestID 非空时作为 PayPal-Request-Id 保证幂等。
+*/
+
+func (c *Client) CreateSetupToken(q *CreateSetupTokenReq, requestID string) (*SetupToken, error) {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, K_VAULT_SETUP_TOKEN_API), q)
+	rsp := &SetupToken{}
+	if err != nil {
+		return rsp, err
+	}
+	if requestID != "" {
+		req.Header.Set("PayPal-Request-Id", requestID)
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v3/vault/setup-tokens/5C991763VB2781612
+// Retrieve a setup token
+// 查询 setup token, 可用于确认付款人是否已授权。
+*/
+
+func (c *Client) ShowSetupToken(setupTokenID string) (*SetupToken, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.APIBase, K_VAULT_SETUP_TOKEN_API, setupTokenID), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &SetupToken{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v3/vault/payment-tokens
+// Create payment token for a given payment source
+// 触发webhook： VAULT.PAYMENT-TOKEN.CREATED
+// 用已授权的 setup token 创建 payment token。
+*/
+
+func (c *Client) CreatePaymentToken(q *CreatePaymentTokenReq, requestID string) (*PaymentToken, error) {
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, K_VAULT_PAYMENT_TOKEN_API), q)
+	rsp := &PaymentToken{}
+	if err != nil {
+		return rsp, err
+	}
+	if requestID != "" {
+		req.Header.Set("PayPal-Request-Id", requestID)
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+// CreatePaymentTokenFromSetupToken 是 CreatePaymentToken 的简写, 用 setup token ID 创建 payment token。
+func (c *Client) CreatePaymentTokenFromSetupToken(setupTokenID string, customer *VaultCustomer, requestID string) (*PaymentToken, error) {
+	return c.CreatePaymentToken(&CreatePaymentTokenReq{
+		Customer: customer,
+		PaymentSource: &VaultPaymentSource{
+			Token: &VaultTokenSource{ID: setupTokenID, Type: "SETUP_TOKEN"},
+		},
+	}, requestID)
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v3/vault/payment-tokens/8kk8451t
+// Retrieve a payment token
+// 查询 payment token
+*/
+
+func (c *Client) ShowPaymentToken(paymentTokenID string) (*PaymentToken, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.APIBase, K_VAULT_PAYMENT_TOKEN_API, paymentTokenID), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &PaymentToken{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v3/vault/payment-tokens?customer_id=customer_4029352050&page=1&page_size=5&total_required=true
+// List all payment tokens
+// 查询客户保存的所有付款方式, page 从 1 开始, pageSize 为 0 时使用 PayPal 默认值。
+*/
+
+func (c *Client) ListPaymentTokens(customerID string, page, pageSize int) (*PaymentTokenList, error) {
+	v := url.Values{}
+	v.Set("customer_id", customerID)
+	v.Set("total_required", "true")
+	if page > 0 {
+		v.Set("page", fmt.Sprint(page))
+	}
+	if pageSize > 0 {
+		v.Set("page_size", fmt.Sprint(pageSize))
+	}
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s?%s", c.APIBase, K_VAULT_PAYMENT_TOKEN_API, v.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &PaymentTokenList{}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// DELETE https://api.sandbox.paypal.com/v3/vault/payment-tokens/8kk8451t
+// returns 204 No Content
+// Delete payment token
+// 触发webhook： VAULT.PAYMENT-TOKEN.DELETED
+// 删除保存的付款方式
+*/
+
+func (c *Client) DeletePaymentToken(paymentTokenID string) error {
+	req, err := c.NewRequest("DELETE", fmt.Sprintf("%s%s/%s", c.APIBase, K_VAULT_PAYMENT_TOKEN_API, paymentTokenID), nil)
+	if err != nil {
+		return err
+	}
+	err = c.SendWithAuth(req, nil)
+	return err
+}
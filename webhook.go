@@ -1,6 +1,12 @@
 package paypalsdk
 
-import "fmt"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
 
 // https://developer.paypal.com/docs/api/webhooks/v1/#definition-event_type
 type EventType struct {
@@ -83,3 +89,187 @@ func (c *Client) DeleteWebhook(id string) (err error) {
 	err = c.SendWithAuth(req, nil)
 	return err
 }
+
+// https://developer.paypal.com/docs/api/webhooks/v1/#verify-webhook-signature_post
+type VerifyWebhookSignatureReq struct {
+	AuthAlgo         string          `json:"auth_algo"`         // 请求头 PAYPAL-AUTH-ALGO
+	CertURL          string          `json:"cert_url"`          // 请求头 PAYPAL-CERT-URL
+	TransmissionID   string          `json:"transmission_id"`   // 请求头 PAYPAL-TRANSMISSION-ID
+	TransmissionSig  string          `json:"transmission_sig"`  // 请求头 PAYPAL-TRANSMISSION-SIG
+	TransmissionTime string          `json:"transmission_time"` // 请求头 PAYPAL-TRANSMISSION-TIME
+	WebhookID        string          `json:"webhook_id"`        // 创建 webhook 时返回的 ID
+	WebhookEvent     json.RawMessage `json:"webhook_event"`     // 原样的请求体, 不能重新序列化
+}
+
+type VerifyWebhookSignatureRsp struct {
+	VerificationStatus string `json:"verification_status"` // SUCCESS, FAILURE
+}
+
+// NewVerifyWebhookSignatureReq 用 webhook 推送的请求头和请求体构造验签请求
+func NewVerifyWebhookSignatureReq(header http.Header, body []byte, webhookID string) *VerifyWebhookSignatureReq {
+	return &VerifyWebhookSignatureReq{
+		AuthAlgo:         header.Get("Paypal-Auth-Algo"),
+		CertURL:          header.Get("Paypal-Cert-Url"),
+		TransmissionID:   header.Get("Paypal-Transmission-Id"),
+		TransmissionSig:  header.Get("Paypal-Transmission-Sig"),
+		TransmissionTime: header.Get("Paypal-Transmission-Time"),
+		WebhookID:        webhookID,
+		WebhookEvent:     json.RawMessage(body),
+	}
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/notifications/verify-webhook-signature
+// Verify webhook signature
+// 校验 webhook 推送的签名, 签名正确时返回 true。模拟器(webhooks simulator)推送的事件无法验签。
+*/
+
+func (c *Client) VerifyWebhookSignature(header http.Header, body []byte, webhookID string) (bool, error) {
+	return c.verifyWebhookSignature(c.context(), header, body, webhookID)
+}
+
+func (c *Client) verifyWebhookSignature(ctx context.Context, header http.Header, body []byte, webhookID string) (verified bool, err error) {
+	defer func() { c.metrics().ObserveWebhookVerification(verified, err) }()
+
+	q := NewVerifyWebhookSignatureReq(header, body, webhookID)
+	req, err := c.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/notifications/verify-webhook-signature"), q)
+	if err != nil {
+		return false, err
+	}
+	rsp := &VerifyWebhookSignatureRsp{}
+	if err = c.SendWithAuth(req, rsp); err != nil {
+		return false, err
+	}
+	return rsp.VerificationStatus == "SUCCESS", nil
+}
+
+/*
+// PATCH https://api.sandbox.paypal.com/v1/notifications/webhooks/{webhook_id}
+// Update webhook
+// 只能修改 /url 和 /event_types, op 为 replace。
+*/
+
+func (c *Client) UpdateWebhook(id string, patches []Patch) (*Webhook, error) {
+	req, err := c.NewRequest("PATCH", fmt.Sprintf("%s%s/%s", c.APIBase, "/v1/notifications/webhooks", id), patches)
+	rsp := &Webhook{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+// https://developer.paypal.com/docs/api/webhooks/v1/#webhooks-events_list
+type ListWebhookEventsReq struct {
+	PageSize      int    // [1, 300] Default: 10.
+	StartTime     string // 创建时间下限, eg: 2020-03-01T00:00:00Z
+	EndTime       string // 创建时间上限
+	TransactionID string // 按交易 ID 过滤
+	EventType     string // eg: PAYMENT.SALE.COMPLETED
+}
+
+type EventList struct {
+	Events []*Event           `json:"events"`
+	Count  int                `json:"count"`
+	Links  []*LinkDescription `json:"links,omitempty"`
+}
+
+// UnmarshalJSON 与 ParseEvent 一样按 resource_type 解析每个事件的 Resource
+func (l *EventList) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		Events []json.RawMessage  `json:"events"`
+		Count  int                `json:"count"`
+		Links  []*LinkDescription `json:"links,omitempty"`
+	}{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	l.Events, l.Count, l.Links = make([]*Event, 0, len(raw.Events)), raw.Count, raw.Links
+	for _, data := range raw.Events {
+		e, err := ParseEvent(data)
+		if err != nil {
+			return err
+		}
+		l.Events = append(l.Events, e)
+	}
+	return nil
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/notifications/webhooks-events?page_size=10&event_type=PAYMENT.SALE.COMPLETED
+// List event notifications
+// 查询最近的事件, 包括推送失败的。
+*/
+
+func (c *Client) ListWebhookEvents(q *ListWebhookEventsReq) (*EventList, error) {
+	v := url.Values{}
+	if q != nil {
+		if q.PageSize > 0 {
+			v.Set("page_size", fmt.Sprint(q.PageSize))
+		}
+		if q.StartTime != "" {
+			v.Set("start_time", q.StartTime)
+		}
+		if q.EndTime != "" {
+			v.Set("end_time", q.EndTime)
+		}
+		if q.TransactionID != "" {
+			v.Set("transaction_id", q.TransactionID)
+		}
+		if q.EventType != "" {
+			v.Set("event_type", q.EventType)
+		}
+	}
+	endpoint := fmt.Sprintf("%s%s", c.APIBase, "/v1/notifications/webhooks-events")
+	if len(v) > 0 {
+		endpoint = fmt.Sprintf("%s?%s", endpoint, v.Encode())
+	}
+	req, err := c.NewRequest("GET", endpoint, nil)
+	rsp := &EventList{}
+	if err != nil {
+		return rsp, err
+	}
+	err = c.SendWithAuth(req, rsp)
+	return rsp, err
+}
+
+/*
+// GET https://api.sandbox.paypal.com/v1/notifications/webhooks-events/{event_id}
+// Show event notification details
+*/
+
+func (c *Client) ShowWebhookEvent(eventID string) (*Event, error) {
+	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s/%s", c.APIBase, "/v1/notifications/webhooks-events", eventID), nil)
+	if err != nil {
+		return nil, err
+	}
+	var data json.RawMessage
+	if err = c.SendWithAuth(req, &data); err != nil {
+		return nil, err
+	}
+	return ParseEvent(data)
+}
+
+// https://developer.paypal.com/docs/api/webhooks/v1/#webhooks-events_resend
+type ResendWebhookEventReq struct {
+	WebhookIDs []string `json:"webhook_ids,omitempty"` // 为空时重新推送给所有订阅了该事件的 webhook
+}
+
+/*
+// POST https://api.sandbox.paypal.com/v1/notifications/webhooks-events/{event_id}/resend
+// Resend event notification
+// 重新推送事件, 用于处理失败后补推。
+*/
+
+func (c *Client) ResendWebhookEvent(eventID string, webhookIDs []string) (*Event, error) {
+	q := &ResendWebhookEventReq{WebhookIDs: webhookIDs}
+	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s/%s/resend", c.APIBase, "/v1/notifications/webhooks-events", eventID), q)
+	if err != nil {
+		return nil, err
+	}
+	var data json.RawMessage
+	if err = c.SendWithAuth(req, &data); err != nil {
+		return nil, err
+	}
+	return ParseEvent(data)
+}
@@ -1,12 +1,23 @@
 package paypalsdk
 
-import "time"
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
 
 type E_EventResourceType string
 
 const (
-	E_EVENT_RESOURCE_TYPE_SUBCRIPTION E_EventResourceType = "subscription"
-	E_EVENT_RESOURCE_TYPE_SALE        E_EventResourceType = "sale"
+	E_EVENT_RESOURCE_TYPE_SUBCRIPTION   E_EventResourceType = "subscription"
+	E_EVENT_RESOURCE_TYPE_PLAN          E_EventResourceType = "plan"
+	E_EVENT_RESOURCE_TYPE_PRODUCT       E_EventResourceType = "product"
+	E_EVENT_RESOURCE_TYPE_SALE          E_EventResourceType = "sale"
+	E_EVENT_RESOURCE_TYPE_REFUND        E_EventResourceType = "refund" // PAYMENT.SALE.REFUNDED, PAYMENT.SALE.REVERSED, v2 的 PAYMENT.CAPTURE.REFUNDED 也是 refund
+	E_EVENT_RESOURCE_TYPE_PAYOUTS       E_EventResourceType = "payouts"
+	E_EVENT_RESOURCE_TYPE_PAYOUTS_ITEM  E_EventResourceType = "payouts_item"
+	E_EVENT_RESOURCE_TYPE_DISPUTE       E_EventResourceType = "dispute"
+	E_EVENT_RESOURCE_TYPE_PAYMENT_TOKEN E_EventResourceType = "payment_token"
 )
 
 const (
@@ -14,8 +25,13 @@ const (
 	E_EVENT_TYPE_PAYMENT_SALE_REFUNDED  = "PAYMENT.SALE.REFUNDED"
 	E_EVENT_TYPE_PAYMENT_SALE_DENIED    = "PAYMENT.SALE.DENIED"
 	E_EVENT_TYPE_PAYMENT_SALE_PENDING   = "PAYMENT.SALE.PENDING"
+	E_EVENT_TYPE_PAYMENT_SALE_REVERSED  = "PAYMENT.SALE.REVERSED"
+
+	E_EVENT_TYPE_BILLING_PLAN_CREATED     = "BILLING.PLAN.CREATED"
+	E_EVENT_TYPE_BILLING_PLAN_ACTIVATED   = "BILLING.PLAN.ACTIVATED"
+	E_EVENT_TYPE_BILLING_PLAN_DEACTIVATED = "BILLING.PLAN.DEACTIVATED"
 
-	E_EVENT_TYPE_BILLING_PLAN_CREATED = "BILLING.PLAN.CREATED"
+	E_EVENT_TYPE_CATALOG_PRODUCT_CREATED = "CATALOG.PRODUCT.CREATED"
 
 	E_EVENT_TYPE_BILLING_SUBSCRIPTION_CREATED        = "BILLING.SUBSCRIPTION.CREATED"
 	E_EVENT_TYPE_BILLING_SUBSCRIPTION_SUSPENDED      = "BILLING.SUBSCRIPTION.SUSPENDED"
@@ -24,6 +40,29 @@ const (
 	E_EVENT_TYPE_BILLING_SUBSCRIPTION_ACTIVATED      = "BILLING.SUBSCRIPTION.ACTIVATED"
 	E_EVENT_TYPE_BILLING_SUBSCRIPTION_PAYMENT_FAILED = "BILLING.SUBSCRIPTION.PAYMENT.FAILED"
 	E_EVENT_TYPE_BILLING_SUBSCRIPTION_RENEWED        = "BILLING.SUBSCRIPTION.RENEWED"
+	E_EVENT_TYPE_BILLING_SUBSCRIPTION_EXPIRED        = "BILLING.SUBSCRIPTION.EXPIRED"
+
+	E_EVENT_TYPE_PAYMENT_PAYOUTSBATCH_DENIED     = "PAYMENT.PAYOUTSBATCH.DENIED"
+	E_EVENT_TYPE_PAYMENT_PAYOUTSBATCH_PROCESSING = "PAYMENT.PAYOUTSBATCH.PROCESSING"
+	E_EVENT_TYPE_PAYMENT_PAYOUTSBATCH_SUCCESS    = "PAYMENT.PAYOUTSBATCH.SUCCESS"
+
+	E_EVENT_TYPE_PAYMENT_PAYOUTS_ITEM_BLOCKED   = "PAYMENT.PAYOUTS-ITEM.BLOCKED"
+	E_EVENT_TYPE_PAYMENT_PAYOUTS_ITEM_CANCELED  = "PAYMENT.PAYOUTS-ITEM.CANCELED"
+	E_EVENT_TYPE_PAYMENT_PAYOUTS_ITEM_DENIED    = "PAYMENT.PAYOUTS-ITEM.DENIED"
+	E_EVENT_TYPE_PAYMENT_PAYOUTS_ITEM_FAILED    = "PAYMENT.PAYOUTS-ITEM.FAILED"
+	E_EVENT_TYPE_PAYMENT_PAYOUTS_ITEM_HELD      = "PAYMENT.PAYOUTS-ITEM.HELD"
+	E_EVENT_TYPE_PAYMENT_PAYOUTS_ITEM_REFUNDED  = "PAYMENT.PAYOUTS-ITEM.REFUNDED"
+	E_EVENT_TYPE_PAYMENT_PAYOUTS_ITEM_RETURNED  = "PAYMENT.PAYOUTS-ITEM.RETURNED"
+	E_EVENT_TYPE_PAYMENT_PAYOUTS_ITEM_SUCCEEDED = "PAYMENT.PAYOUTS-ITEM.SUCCEEDED"
+	E_EVENT_TYPE_PAYMENT_PAYOUTS_ITEM_UNCLAIMED = "PAYMENT.PAYOUTS-ITEM.UNCLAIMED"
+
+	E_EVENT_TYPE_CUSTOMER_DISPUTE_CREATED  = "CUSTOMER.DISPUTE.CREATED"
+	E_EVENT_TYPE_CUSTOMER_DISPUTE_UPDATED  = "CUSTOMER.DISPUTE.UPDATED"
+	E_EVENT_TYPE_CUSTOMER_DISPUTE_RESOLVED = "CUSTOMER.DISPUTE.RESOLVED"
+
+	E_EVENT_TYPE_VAULT_PAYMENT_TOKEN_CREATED            = "VAULT.PAYMENT-TOKEN.CREATED"
+	E_EVENT_TYPE_VAULT_PAYMENT_TOKEN_DELETED            = "VAULT.PAYMENT-TOKEN.DELETED"
+	E_EVENT_TYPE_VAULT_PAYMENT_TOKEN_DELETION_INITIATED = "VAULT.PAYMENT-TOKEN.DELETION-INITIATED"
 )
 
 type Event struct {
@@ -45,9 +84,85 @@ func (e *Event) Sale() *Sale {
 	return nil
 }
 
+func (e *Event) Refund() *DetailedRefund {
+	if s, ok := e.Resource.(*DetailedRefund); ok {
+		return s
+	}
+	return nil
+}
+
 func (e *Event) Subscription() *Subscription {
 	if s, ok := e.Resource.(*Subscription); ok {
 		return s
 	}
 	return nil
 }
+
+func (e *Event) PayoutBatch() *PayoutBatch {
+	if s, ok := e.Resource.(*PayoutBatch); ok {
+		return s
+	}
+	return nil
+}
+
+func (e *Event) PayoutItem() *PayoutItemDetail {
+	if s, ok := e.Resource.(*PayoutItemDetail); ok {
+		return s
+	}
+	return nil
+}
+
+func (e *Event) Dispute() *Dispute {
+	if s, ok := e.Resource.(*Dispute); ok {
+		return s
+	}
+	return nil
+}
+
+func (e *Event) PaymentToken() *PaymentToken {
+	if s, ok := e.Resource.(*PaymentToken); ok {
+		return s
+	}
+	return nil
+}
+
+// newEventResource 根据 resource_type 返回用于解析 resource 的结构体, 未知类型返回 nil, resource 将被解析为 map。
+// refund 只有 PAYMENT.SALE.* 事件是 v1 的 DetailedRefund, v2 的 PAYMENT.CAPTURE.REFUNDED 等解析为 map。
+func newEventResource(resourceType E_EventResourceType, eventType string) interface{} {
+	switch resourceType {
+	case E_EVENT_RESOURCE_TYPE_SALE:
+		return &Sale{}
+	case E_EVENT_RESOURCE_TYPE_REFUND:
+		if strings.HasPrefix(eventType, "PAYMENT.SALE.") {
+			return &DetailedRefund{}
+		}
+	case E_EVENT_RESOURCE_TYPE_SUBCRIPTION:
+		return &Subscription{}
+	case E_EVENT_RESOURCE_TYPE_PAYOUTS:
+		return &PayoutBatch{}
+	case E_EVENT_RESOURCE_TYPE_PAYOUTS_ITEM:
+		return &PayoutItemDetail{}
+	case E_EVENT_RESOURCE_TYPE_DISPUTE:
+		return &Dispute{}
+	case E_EVENT_RESOURCE_TYPE_PAYMENT_TOKEN:
+		return &PaymentToken{}
+	}
+	return nil
+}
+
+// ParseEvent 解析 webhook 推送的事件, Resource 按 resource_type 解析为对应的结构体,
+// 可通过 Sale()、Subscription()、PayoutBatch() 等方法获取。
+func ParseEvent(data []byte) (*Event, error) {
+	head := struct {
+		ResourceType E_EventResourceType `json:"resource_type"`
+		EventType    string              `json:"event_type"`
+	}{}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+	e := &Event{Resource: newEventResource(head.ResourceType, head.EventType)}
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -0,0 +1,51 @@
+package paypalsdk
+
+import "testing"
+
+func TestParseEventResource(t *testing.T) {
+	tests := []struct {
+		data  string
+		check func(e *Event) bool
+	}{
+		{
+			`{"event_type":"PAYMENT.SALE.COMPLETED","resource_type":"sale","resource":{"id":"S1"}}`,
+			func(e *Event) bool { return e.Sale() != nil && e.Sale().Id == "S1" },
+		},
+		{
+			`{"event_type":"PAYMENT.SALE.REFUNDED","resource_type":"refund","resource":{"id":"R1","sale_id":"S1"}}`,
+			func(e *Event) bool { return e.Refund() != nil && e.Refund().SaleId == "S1" },
+		},
+		{
+			`{"event_type":"PAYMENT.SALE.REVERSED","resource_type":"refund","resource":{"id":"R1","sale_id":"S1"}}`,
+			func(e *Event) bool { return e.Refund() != nil && e.Refund().SaleId == "S1" },
+		},
+		{
+			// v2 的退款不是 DetailedRefund
+			`{"event_type":"PAYMENT.CAPTURE.REFUNDED","resource_version":"2.0","resource_type":"refund","resource":{"id":"R2","status":"COMPLETED"}}`,
+			func(e *Event) bool {
+				m, ok := e.Resource.(map[string]interface{})
+				return e.Refund() == nil && ok && m["id"] == "R2"
+			},
+		},
+		{
+			`{"event_type":"BILLING.SUBSCRIPTION.ACTIVATED","resource_type":"subscription","resource":{"id":"I-1","status":"ACTIVE"}}`,
+			func(e *Event) bool {
+				return e.Subscription() != nil && e.Subscription().Status == E_SUBSCRIPTION_STATUS_ACTIVE
+			},
+		},
+		{
+			`{"event_type":"CHECKOUT.ORDER.APPROVED","resource_type":"checkout-order","resource":{"id":"O1"}}`,
+			func(e *Event) bool { _, ok := e.Resource.(map[string]interface{}); return ok },
+		},
+	}
+	for _, tt := range tests {
+		e, err := ParseEvent([]byte(tt.data))
+		if err != nil {
+			t.Errorf("ParseEvent(%s) error = %v", tt.data, err)
+			continue
+		}
+		if !tt.check(e) {
+			t.Errorf("ParseEvent(%s) resource = %#v", tt.data, e.Resource)
+		}
+	}
+}
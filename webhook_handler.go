@@ -0,0 +1,56 @@
+package paypalsdk
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+)
+
+// webhook 请求体的最大长度
+const kMaxWebhookBodySize = 1 << 20
+
+// EventHandler processes a verified webhook event.
+// Returning an error makes PayPal redeliver the event later, so it should be idempotent by Event.Id
+type EventHandler func(ctx context.Context, e *Event) error
+
+// WebhookHandler returns an http.Handler receiving the deliveries of webhookID:
+// it verifies the signature, parses the event and calls handle.
+// It responds 400 if the body is not JSON or the signature is invalid, 500 if verification or handle fails so that PayPal redelivers, or 200
+func (c *Client) WebhookHandler(webhookID string, handle EventHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, kMaxWebhookBodySize))
+		if err != nil || !json.Valid(body) {
+			// 不是 PayPal 的推送, 不必请求 PayPal 验签
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		verified, err := c.verifyWebhookSignature(r.Context(), r.Header, body, webhookID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !verified {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		e, err := ParseEvent(body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		err = handle(r.Context(), e)
+		c.metrics().ObserveWebhookHandled(e.EventType, err)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
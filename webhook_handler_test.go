@@ -0,0 +1,71 @@
+package paypalsdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	var verifications int
+	status := "SUCCESS"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verifications++
+		if status == "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"verification_status":"` + status + `"}`))
+	}))
+	defer srv.Close()
+	c, err := NewClient("id", "secret", WithAPIBase(srv.URL), WithLogger(discardLogger{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetAccessToken("token")
+
+	const event = `{"id":"WH-1","event_type":"BILLING.SUBSCRIPTION.ACTIVATED","resource_type":"subscription","resource":{"id":"I-1"}}`
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		status     string // 验签结果, 空表示验签请求失败
+		handleErr  error
+		want       int
+		wantVerify bool
+		wantHandle bool
+	}{
+		{"delivered", "POST", event, "SUCCESS", nil, http.StatusOK, true, true},
+		{"handle fails", "POST", event, "SUCCESS", errors.New("db down"), http.StatusInternalServerError, true, true},
+		{"invalid signature", "POST", event, "FAILURE", nil, http.StatusBadRequest, true, false},
+		{"verification fails", "POST", event, "", nil, http.StatusInternalServerError, true, false},
+		{"not json", "POST", `not json`, "SUCCESS", nil, http.StatusBadRequest, false, false},
+		{"empty body", "POST", ``, "SUCCESS", nil, http.StatusBadRequest, false, false},
+		{"not post", "GET", event, "SUCCESS", nil, http.StatusMethodNotAllowed, false, false},
+	}
+	for _, tt := range tests {
+		verifications, status = 0, tt.status
+		var handled *Event
+		h := c.WebhookHandler("WH-ID", func(ctx context.Context, e *Event) error {
+			handled = e
+			return tt.handleErr
+		})
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tt.method, "/paypal/webhook", strings.NewReader(tt.body)))
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+		if (verifications > 0) != tt.wantVerify {
+			t.Errorf("%s: %d verifications, want verified %v", tt.name, verifications, tt.wantVerify)
+		}
+		if (handled != nil) != tt.wantHandle {
+			t.Errorf("%s: handled = %v, want %v", tt.name, handled, tt.wantHandle)
+		}
+		if handled != nil && handled.Id != "WH-1" {
+			t.Errorf("%s: handled event %s", tt.name, handled.Id)
+		}
+	}
+}